@@ -0,0 +1,72 @@
+package seqmut
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// VersionsSince returns how many writes have committed since the version
+// stamp was read at. A write still in progress is not counted.
+func (p *Protected[T]) VersionsSince(stamp Stamp) uint64 {
+	return versionsSince(stamp, Stamp(atomic.LoadUint64(&p.rw.sequence)))
+}
+
+// commitLog is a ring of the most recent commit times.
+type commitLog struct {
+	mu     sync.Mutex
+	stamps []Stamp
+	times  []time.Time
+	next   int
+}
+
+func (c *commitLog) record(stamp Stamp, at time.Time) {
+	c.mu.Lock()
+	c.stamps[c.next] = stamp
+	c.times[c.next] = at
+	c.next = (c.next + 1) % len(c.stamps)
+	c.mu.Unlock()
+}
+
+func (c *commitLog) lookup(stamp Stamp) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, s := range c.stamps {
+		if s == stamp && !c.times[i].IsZero() {
+			return c.times[i], true
+		}
+	}
+	return time.Time{}, false
+}
+
+// RecordCommitTimes makes every subsequent write record when it committed,
+// keeping the times of the last n versions for CommittedAt and Age.
+func (p *Protected[T]) RecordCommitTimes(n int) {
+	if n < 1 {
+		panic("seqmut: RecordCommitTimes needs room for at least one version")
+	}
+	p.lock()
+	p.commits.Store(&commitLog{stamps: make([]Stamp, n), times: make([]time.Time, n)})
+	p.unlock()
+}
+
+// CommittedAt returns when the version stamp was read at was committed. It
+// returns false if commit times are not being recorded, or the version is
+// older than the ones retained by RecordCommitTimes.
+func (p *Protected[T]) CommittedAt(stamp Stamp) (time.Time, bool) {
+	c := p.commits.Load()
+	if c == nil {
+		return time.Time{}, false
+	}
+	return c.lookup(stamp)
+}
+
+// Age returns how long ago the version stamp was read at was committed, see
+// CommittedAt.
+func (p *Protected[T]) Age(stamp Stamp) (time.Duration, bool) {
+	at, ok := p.CommittedAt(stamp)
+	if !ok {
+		return 0, false
+	}
+	return time.Since(at), true
+}
@@ -0,0 +1,45 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVersionsSince(t *testing.T) {
+	p := NewProtected(0)
+	_, stamp := p.LoadStamped()
+
+	p.Store(1)
+	p.Store(2)
+
+	assert.Equal(t, uint64(2), p.VersionsSince(stamp))
+	p.rw.Lock()
+	assert.Equal(t, uint64(2), p.VersionsSince(stamp))
+	p.rw.Unlock()
+}
+
+func TestCommittedAt(t *testing.T) {
+	p := NewProtected(0)
+	_, stamp := p.LoadStamped()
+	_, ok := p.CommittedAt(stamp)
+	assert.False(t, ok)
+
+	p.RecordCommitTimes(2)
+	before := time.Now()
+	p.Store(1)
+	_, first := p.LoadStamped()
+
+	at, ok := p.CommittedAt(first)
+	assert.True(t, ok)
+	assert.False(t, at.Before(before))
+	age, ok := p.Age(first)
+	assert.True(t, ok)
+	assert.True(t, age >= 0)
+
+	// Pushed out of the log by later versions
+	p.Store(2)
+	p.Store(3)
+	_, ok = p.CommittedAt(first)
+	assert.False(t, ok)
+}
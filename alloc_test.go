@@ -0,0 +1,78 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// The whole point of the optimistic read path is to be cheaper than a mutex;
+// a heap allocation per read would make it slower than sync.RWMutex.
+func TestReadPathDoesNotAllocate(t *testing.T) {
+	var rw RWMutex
+	v := 0
+	var readValue int
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		stamp := rw.RStamp()
+		for {
+			readValue = v
+			if rw.Ok(stamp) {
+				break
+			}
+		}
+	})
+
+	assert.Equal(t, v, readValue)
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestReadPathDoesNotAllocateOnRetry(t *testing.T) {
+	var rw RWMutex
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		stamp := rw.RStamp()
+		// A write commits; Lock itself allocates in debug builds, to record
+		// the writer's stack
+		atomic.AddUint64(&rw.sequence, 2)
+		for !rw.Ok(stamp) {
+		}
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+// The stamp only stays off the heap if RStamp and Ok are inlined into the
+// caller, so ask the compiler directly.
+func TestReadPathInlines(t *testing.T) {
+	if testing.Short() {
+		t.Skip("invokes the compiler")
+	}
+	out, err := exec.Command("go", "build", "-gcflags=-m", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+
+	for _, fn := range []string{"(*RWMutex).RStamp", "(*RWMutex).Ok"} {
+		assert.True(t, strings.Contains(string(out), "can inline "+fn), "%s is not inlinable", fn)
+	}
+}
+
+func BenchmarkReadPathAllocs(b *testing.B) {
+	var rw RWMutex
+	var v, readValue int
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		stamp := rw.RStamp()
+		for {
+			readValue = v
+			if rw.Ok(stamp) {
+				break
+			}
+		}
+	}
+	_ = readValue
+}
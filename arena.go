@@ -0,0 +1,289 @@
+package seqmut
+
+import (
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// EpochDomain implements epoch-based reclamation: it tells writers when
+// every reader that might still hold a pointer to an unlinked node has moved
+// on. Readers bracket each operation with Enter and Exit; writers Retire the
+// nodes they unlink, and a retired node is handed back for reuse only after
+// two epoch advances, once no reader can have started before it was
+// unlinked.
+//
+// Readers register on one of several counters per epoch, picked at random
+// and each on its own cache line, so concurrent readers rarely write to the
+// same line; writers, which are rarer, add them all up.
+//
+// The zero value is ready to use.
+type EpochDomain struct {
+	global atomic.Uint64
+	// Readers inside each of the last three epochs, striped
+	active [3][epochStripes]epochSlot
+
+	mu sync.Mutex
+	// Callbacks registered with FreeLater during each of the last three epochs
+	deferred [3][]deferredFree
+	// Number of Reclaim calls, to age deferred callbacks by
+	passes uint64
+}
+
+const epochStripes = 16
+
+type epochSlot struct {
+	readers atomic.Int64
+	// Keep slots on separate cache lines
+	_ [56]byte
+}
+
+// EpochGuard marks a reader inside an epoch, see EpochDomain.Enter.
+type EpochGuard struct {
+	d      *EpochDomain
+	epoch  uint64
+	stripe uint32
+}
+
+// Enter starts a read. Pointers read from the structure stay safe to
+// dereference, in the sense that their memory is not reused, until Exit.
+func (d *EpochDomain) Enter() EpochGuard {
+	stripe := rand.Uint32N(epochStripes)
+	for {
+		e := d.global.Load()
+		d.active[e%3][stripe].readers.Add(1)
+		// If the epoch moved on while registering, a writer may already have
+		// decided the old one was empty; register again in the new one
+		if d.global.Load() == e {
+			return EpochGuard{d, e, stripe}
+		}
+		d.active[e%3][stripe].readers.Add(-1)
+	}
+}
+
+// Exit ends the read started by Enter.
+func (g EpochGuard) Exit() {
+	g.d.active[g.epoch%3][g.stripe].readers.Add(-1)
+}
+
+// readers returns the number of readers inside the epochs sharing slot i.
+// A reader only ever adds to and takes from one stripe, so no stripe goes
+// negative, and a sum of zero means every stripe was empty when it was read.
+func (d *EpochDomain) readers(i uint64) int64 {
+	var n int64
+	for s := range d.active[i] {
+		n += d.active[i][s].readers.Load()
+	}
+	return n
+}
+
+// tryAdvance moves to the next epoch if no reader is left in the previous
+// one, returning the new epoch, and runs the FreeLater callbacks that have
+// become due.
+func (d *EpochDomain) tryAdvance() (epoch uint64, ok bool) {
+	d.mu.Lock()
+	e := d.global.Load()
+	if d.readers((e+2)%3) != 0 || !d.global.CompareAndSwap(e, e+1) {
+		d.mu.Unlock()
+		return e, false
+	}
+	due := d.deferred[(e+1)%3]
+	d.deferred[(e+1)%3] = nil
+	d.mu.Unlock()
+	for _, f := range due {
+		f.fn()
+	}
+	return e + 1, true
+}
+
+// Arena allocates nodes of type T for the container types, from regions of
+// slabSize nodes, and takes back nodes retired by writers once its
+// EpochDomain says no reader can still see them. Allocating in regions cuts
+// the number of objects the GC tracks, and reusing nodes cuts allocation
+// altogether in structures with steady churn.
+//
+// A region is freed as a whole, by dropping the arena's reference to it for
+// the GC to collect, once every node in it has been retired and has
+// outlived its grace period. Until then its free nodes are handed out again.
+//
+// Skiplist allocates its nodes from an Arena and retires them on Delete.
+// IntervalTree never unlinks nodes, it leaves tombstones behind, so it has
+// nothing to hand back and allocates from the heap.
+type Arena[T any] struct {
+	Epochs EpochDomain
+
+	mu       sync.Mutex
+	slabSize int
+	// What is left of the region being carved up
+	slab []T
+	free []*T
+	// Regions with nodes in use or retired, by address, so a node can be
+	// traced back to its region; not tracked with a pool, see UsePool
+	regions []*arenaRegion
+	// Nodes retired during each of the last three epochs
+	limbo [3][]*T
+	// If set by UsePool, nodes past their grace period go here instead of free
+	pool *sync.Pool
+}
+
+// arenaRegion is one allocation of slabSize nodes.
+type arenaRegion struct {
+	start, end uintptr
+	// Keeps the memory alive while the arena still hands it out
+	mem any
+	// Nodes carved out and not back on the free list
+	live int
+	// Set once every node has been carved out, so the region can be freed
+	// when live drops to zero
+	carved bool
+}
+
+// UsePool makes the arena recycle nodes through a sync.Pool rather than its
+// own free list, so that in structures with heavy churn, memory recycled
+// nodes are holding can be given back to the GC when they sit unused. Regions
+// are then freed by the GC once the pool has let go of all their nodes. It
+// must be called before the arena is used.
+//
+// In debug builds, nodes are poisoned as they leave the grace period, see
+// poison, so code still using a node after retiring it reads obvious garbage.
+func (a *Arena[T]) UsePool() {
+	a.pool = &sync.Pool{}
+}
+
+// NewArena returns an arena allocating slabSize nodes at a time.
+func NewArena[T any](slabSize int) *Arena[T] {
+	if slabSize < 1 {
+		panic("seqmut: arena slab size must be positive")
+	}
+	return &Arena[T]{slabSize: slabSize}
+}
+
+// Alloc returns a zeroed node.
+func (a *Arena[T]) Alloc() *T {
+	if a.pool != nil {
+		if p, _ := a.pool.Get().(*T); p != nil {
+			var zero T
+			*p = zero
+			return p
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if n := len(a.free); n > 0 {
+		p := a.free[n-1]
+		a.free = a.free[:n-1]
+		a.region(p).live++
+		var zero T
+		*p = zero
+		return p
+	}
+	if len(a.slab) == 0 {
+		a.newRegion()
+	}
+	p := &a.slab[0]
+	a.slab = a.slab[1:]
+	if len(a.slab) == 0 {
+		// Don't keep the region alive once it is all handed out
+		a.slab = nil
+	}
+	if a.pool == nil {
+		r := a.region(p)
+		r.live++
+		r.carved = a.slab == nil
+	}
+	return p
+}
+
+// newRegion starts carving up a fresh region; must hold mu.
+func (a *Arena[T]) newRegion() {
+	a.slab = make([]T, a.slabSize)
+	if a.pool != nil {
+		return
+	}
+	start := uintptr(unsafe.Pointer(&a.slab[0]))
+	r := &arenaRegion{start: start, end: start + uintptr(a.slabSize)*unsafe.Sizeof(a.slab[0]), mem: a.slab}
+	i := sort.Search(len(a.regions), func(i int) bool { return a.regions[i].start > start })
+	a.regions = append(a.regions, nil)
+	copy(a.regions[i+1:], a.regions[i:])
+	a.regions[i] = r
+}
+
+// region returns the region p was carved from; must hold mu.
+func (a *Arena[T]) region(p *T) *arenaRegion {
+	addr := uintptr(unsafe.Pointer(p))
+	i := sort.Search(len(a.regions), func(i int) bool { return a.regions[i].end > addr })
+	return a.regions[i]
+}
+
+// release puts p, past its grace period, back on the free list, freeing its
+// region instead if that was the last of its nodes in use; must hold mu.
+func (a *Arena[T]) release(p *T) {
+	r := a.region(p)
+	r.live--
+	a.free = append(a.free, p)
+	if r.live > 0 || !r.carved {
+		return
+	}
+	// Every node of the region is on the free list: take them off and let
+	// the region go
+	free := a.free[:0]
+	for _, q := range a.free {
+		if addr := uintptr(unsafe.Pointer(q)); addr < r.start || addr >= r.end {
+			free = append(free, q)
+		}
+	}
+	clear(a.free[len(free):])
+	a.free = free
+	i := sort.Search(len(a.regions), func(i int) bool { return a.regions[i].end > r.start })
+	a.regions = append(a.regions[:i], a.regions[i+1:]...)
+}
+
+// Retire hands back a node that has been unlinked from the structure, so no
+// new reader can reach it. It is reused once every reader that was inside
+// an epoch when it was retired has exited.
+func (a *Arena[T]) Retire(p *T) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	e := a.Epochs.global.Load()
+	a.limbo[e%3] = append(a.limbo[e%3], p)
+	a.advance()
+}
+
+// Reclaim tries to move retired nodes to the free list, advancing the epoch
+// as far as readers allow, and returns how many nodes are free; nodes in
+// regions that were freed, and nodes recycled through a pool, are not
+// counted. Retire does this as it goes; Reclaim is for writers that have
+// stopped retiring.
+func (a *Arena[T]) Reclaim() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := 0; i < 3 && a.advance(); i++ {
+	}
+	return len(a.free)
+}
+
+// advance moves the epoch on if it can, releasing the nodes retired two
+// epochs before the new one; must hold mu.
+func (a *Arena[T]) advance() bool {
+	e, ok := a.Epochs.tryAdvance()
+	if !ok {
+		return false
+	}
+	// Readers are now in e or e-1 at the earliest, so nothing retired in
+	// e-3, which shares e's slot, can still be seen
+	for _, p := range a.limbo[e%3] {
+		if debug {
+			poison(p)
+		}
+		if a.pool != nil {
+			a.pool.Put(p)
+		} else {
+			a.release(p)
+		}
+	}
+	clear(a.limbo[e%3])
+	a.limbo[e%3] = a.limbo[e%3][:0]
+	return true
+}
@@ -0,0 +1,22 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestArenaPoisonsNodesAfterGracePeriod(t *testing.T) {
+	a := NewArena[arenaNode](4)
+	n := a.Alloc()
+	n.value, n.next = 7, a.Alloc()
+
+	a.Retire(n)
+	a.Reclaim()
+
+	// What a use-after-retire would see
+	assert.Equal(t, -0x2424242424242425, n.value)
+	assert.Nil(t, n.next)
+}
@@ -0,0 +1,95 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type arenaNode struct {
+	value int
+	next  *arenaNode
+}
+
+func TestArenaAllocatesFromSlabs(t *testing.T) {
+	a := NewArena[arenaNode](4)
+
+	first := a.Alloc()
+	second := a.Alloc()
+	first.value = 1
+
+	assert.True(t, first != second)
+	assert.Equal(t, 0, second.value)
+	allocs := testing.AllocsPerRun(100, func() { a.Alloc() })
+	assert.True(t, allocs < 1, "%v", allocs)
+}
+
+func TestArenaReusesOnlyAfterReadersExit(t *testing.T) {
+	a := NewArena[arenaNode](4)
+	n := a.Alloc()
+	n.value = 7
+
+	reader := a.Epochs.Enter()
+	a.Retire(n)
+	assert.Equal(t, 0, a.Reclaim())
+	// The reader may still be looking at the node
+	assert.Equal(t, 7, n.value)
+
+	reader.Exit()
+	assert.Equal(t, 1, a.Reclaim())
+	reused := a.Alloc()
+	assert.True(t, reused == n)
+	assert.Equal(t, 0, reused.value)
+}
+
+func TestArenaFreesWholeRegions(t *testing.T) {
+	a := NewArena[arenaNode](2)
+	first, second, third := a.Alloc(), a.Alloc(), a.Alloc()
+
+	a.Retire(first)
+	// Half of the first region is still in use
+	assert.Equal(t, 1, a.Reclaim())
+	assert.Equal(t, 2, len(a.regions))
+
+	a.Retire(second)
+	// The whole first region was retired, so it is gone rather than free
+	assert.Equal(t, 0, a.Reclaim())
+	assert.Equal(t, 1, len(a.regions))
+
+	// The second region is still being carved up, so it stays
+	a.Retire(third)
+	assert.Equal(t, 1, a.Reclaim())
+	assert.Equal(t, 1, len(a.regions))
+}
+
+func TestEpochReadersOnDifferentStripesHoldBackTheEpoch(t *testing.T) {
+	var d EpochDomain
+	var guards []EpochGuard
+	for i := 0; i < 4*epochStripes; i++ {
+		guards = append(guards, d.Enter())
+	}
+
+	_, ok := d.tryAdvance()
+	assert.True(t, ok)
+	// Everyone is still in the epoch before
+	_, ok = d.tryAdvance()
+	assert.False(t, ok)
+
+	for _, g := range guards {
+		g.Exit()
+	}
+	_, ok = d.tryAdvance()
+	assert.True(t, ok)
+}
+
+func TestArenaUsePool(t *testing.T) {
+	a := NewArena[arenaNode](4)
+	a.UsePool()
+	n := a.Alloc()
+	n.value = 3
+
+	a.Retire(n)
+	a.Reclaim()
+
+	// The pool may drop what it is given; whatever comes back is zeroed
+	assert.Equal(t, 0, a.Alloc().value)
+}
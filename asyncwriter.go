@@ -0,0 +1,82 @@
+package seqmut
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("seqmut: writer closed")
+
+// AsyncWriter owns a guarded value and applies write closures to it, one at a
+// time, on a dedicated goroutine. Submitters get a channel that receives the
+// closure's error once it has been applied, so request goroutines can hand off
+// writes without blocking on the lock. Readers use the optimistic path as
+// usual.
+type AsyncWriter[T any] struct {
+	p Protected[T]
+
+	writes    chan asyncWrite[T]
+	quit      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+type asyncWrite[T any] struct {
+	fn     func(v *T) error
+	result chan error
+}
+
+// NewAsyncWriter starts the writer goroutine. Call Close to stop it.
+func NewAsyncWriter[T any](initial T) *AsyncWriter[T] {
+	w := &AsyncWriter[T]{
+		writes:  make(chan asyncWrite[T]),
+		quit:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	w.p.value = initial
+	go w.run()
+	return w
+}
+
+// Submit queues fn to be applied to the guarded value. The returned channel
+// receives fn's error, or ErrClosed if the writer was closed first.
+func (w *AsyncWriter[T]) Submit(fn func(v *T) error) <-chan error {
+	result := make(chan error, 1)
+	select {
+	case w.writes <- asyncWrite[T]{fn: fn, result: result}:
+	case <-w.quit:
+		result <- ErrClosed
+	}
+	return result
+}
+
+// Load returns a consistent copy of the guarded value.
+func (w *AsyncWriter[T]) Load() T {
+	return w.p.Load()
+}
+
+// Read runs fn as an optimistic critical section, see Protected.Read.
+func (w *AsyncWriter[T]) Read(fn func(v *T)) {
+	w.p.Read(fn)
+}
+
+// Close stops the writer goroutine. Writes already handed to the goroutine
+// complete; later submissions fail with ErrClosed.
+func (w *AsyncWriter[T]) Close() {
+	w.closeOnce.Do(func() { close(w.quit) })
+	<-w.stopped
+}
+
+func (w *AsyncWriter[T]) run() {
+	defer close(w.stopped)
+	for {
+		select {
+		case write := <-w.writes:
+			var err error
+			w.p.Update(func(v *T) { err = write.fn(v) })
+			write.result <- err
+		case <-w.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestAsyncWriterAppliesWrites(t *testing.T) {
+	w := NewAsyncWriter(pair{})
+	defer w.Close()
+
+	err := <-w.Submit(func(v *pair) error {
+		v.a, v.b = 1, 1
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, pair{1, 1}, w.Load())
+}
+
+func TestAsyncWriterReturnsError(t *testing.T) {
+	w := NewAsyncWriter(0)
+	defer w.Close()
+	boom := errors.New("boom")
+
+	err := <-w.Submit(func(v *int) error { return boom })
+
+	assert.Equal(t, boom, err)
+}
+
+func TestAsyncWriterSerializesWrites(t *testing.T) {
+	w := NewAsyncWriter(0)
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-w.Submit(func(v *int) error {
+				*v++
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, w.Load())
+}
+
+func TestAsyncWriterSubmitAfterClose(t *testing.T) {
+	w := NewAsyncWriter(0)
+	w.Close()
+	w.Close()
+
+	err := <-w.Submit(func(v *int) error { return nil })
+
+	assert.Equal(t, ErrClosed, err)
+}
@@ -0,0 +1,58 @@
+package seqmut
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// AtomicValue offers the atomic.Value API on top of Protected, for code that
+// publishes values by swapping in full copies. Existing Load/Store/Swap call
+// sites keep working unchanged, while new code can mutate the value in place
+// with Update and use the stamps from LoadStamped as version tokens.
+type AtomicValue[T any] struct {
+	Protected[T]
+}
+
+// FromAtomicValue returns an AtomicValue holding the current contents of v.
+// It panics if v holds something other than a T.
+func FromAtomicValue[T any](v *atomic.Value) *AtomicValue[T] {
+	av := &AtomicValue[T]{}
+	if current := v.Load(); current != nil {
+		typed, ok := current.(T)
+		if !ok {
+			panic(fmt.Sprintf("seqmut: atomic.Value holds %T, not %T", current, av.value))
+		}
+		av.value = typed
+	}
+	return av
+}
+
+// Swap stores new and returns the previous value.
+func (av *AtomicValue[T]) Swap(new T) (old T) {
+	av.lock()
+	old = av.value
+	av.value = new
+	av.unlock()
+	return old
+}
+
+// CompareAndSwap stores new if the current value equals old. Like
+// atomic.Value, it panics if T is not comparable. A failed swap does not
+// count as a write, so it leaves optimistic readers undisturbed.
+func (av *AtomicValue[T]) CompareAndSwap(old, new T) (swapped bool) {
+	av.rw.acquire()
+	defer func() {
+		// Also releases the lock if comparing panicked
+		if !swapped {
+			av.rw.release()
+		}
+	}()
+	if any(av.value) != any(old) {
+		return false
+	}
+	av.enter()
+	av.value = new
+	swapped = true
+	av.unlock()
+	return true
+}
@@ -0,0 +1,91 @@
+package seqmut
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+)
+
+type config struct {
+	Endpoint string
+	Retries  int
+}
+
+func TestFromAtomicValueCopiesCurrentValue(t *testing.T) {
+	var v atomic.Value
+	v.Store(config{Endpoint: "a", Retries: 1})
+
+	av := FromAtomicValue[config](&v)
+
+	assert.Equal(t, config{Endpoint: "a", Retries: 1}, av.Load())
+}
+
+func TestFromEmptyAtomicValue(t *testing.T) {
+	var v atomic.Value
+
+	av := FromAtomicValue[config](&v)
+
+	assert.Equal(t, config{}, av.Load())
+}
+
+func TestFromAtomicValueOfWrongType(t *testing.T) {
+	var v atomic.Value
+	v.Store(1)
+
+	assert.Panics(t, func() { FromAtomicValue[config](&v) })
+}
+
+func TestAtomicValueSwapAndCompareAndSwap(t *testing.T) {
+	var av AtomicValue[int]
+
+	assert.Equal(t, 0, av.Swap(1))
+	assert.False(t, av.CompareAndSwap(0, 2))
+	assert.True(t, av.CompareAndSwap(1, 2))
+	assert.Equal(t, 2, av.Load())
+}
+
+func TestAtomicValueFailedCompareAndSwapDoesNotWrite(t *testing.T) {
+	var av AtomicValue[int]
+	_, before := av.LoadStamped()
+
+	assert.False(t, av.CompareAndSwap(1, 2))
+
+	_, after := av.LoadStamped()
+	assert.Equal(t, before, after)
+}
+
+func TestAtomicValueCompareAndSwapPanicReleasesLock(t *testing.T) {
+	var av AtomicValue[any]
+	av.Store([]int{1})
+
+	assert.Panics(t, func() { av.CompareAndSwap([]int{1}, 2) })
+	av.Store(3)
+	assert.Equal(t, 3, av.Load())
+}
+
+func TestAtomicValueStampsChangeOnWrite(t *testing.T) {
+	var av AtomicValue[int]
+
+	_, before := av.LoadStamped()
+	av.Update(func(v *int) { *v++ })
+	v, after := av.LoadStamped()
+
+	assert.Equal(t, 1, v)
+	assert.NotEqual(t, before, after)
+}
+
+func ExampleFromAtomicValue() {
+	var published atomic.Value
+	published.Store(config{Endpoint: "db:5432", Retries: 3})
+
+	cfg := FromAtomicValue[config](&published)
+
+	// Whole-value swaps work as before...
+	cfg.Store(config{Endpoint: "db:5433", Retries: 3})
+	// ...and small changes no longer need a full copy
+	cfg.Update(func(c *config) { c.Retries = 5 })
+
+	fmt.Printf("%+v\n", cfg.Load())
+	// Output: {Endpoint:db:5433 Retries:5}
+}
@@ -0,0 +1,154 @@
+package seqmut
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Backend is an entry in a Balancer's table.
+type Backend struct {
+	Name    string
+	Weight  int
+	Healthy bool
+	// Exponentially weighted moving average of observed latencies
+	Latency time.Duration
+}
+
+// Balancer is a load balancer's table of backends. Pick runs on every request
+// as an optimistic read of the whole table, so it never contends with other
+// picks; health checkers and latency observers update it under the write
+// lock.
+//
+// The zero value is an empty table ready to use.
+type Balancer struct {
+	rw RWMutex
+	// Replaced when backends are added or removed; fields are updated in
+	// place
+	backends atomic.Pointer[[]Backend]
+}
+
+// latencySmoothing is the weight of each new latency observation.
+const latencySmoothing = 0.2
+
+func (b *Balancer) load() []Backend {
+	if p := b.backends.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// index returns where name is in the table, or -1; must hold the write lock.
+func (b *Balancer) index(name string) int {
+	for i, be := range b.load() {
+		if be.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set adds a backend, healthy, or changes the weight of an existing one.
+func (b *Balancer) Set(name string, weight int) {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+	if i := b.index(name); i >= 0 {
+		b.load()[i].Weight = weight
+		return
+	}
+	backends := append(b.load(), Backend{Name: name, Weight: weight, Healthy: true})
+	b.backends.Store(&backends)
+}
+
+// Remove removes a backend, returning false if there was none.
+func (b *Balancer) Remove(name string) bool {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+	i := b.index(name)
+	if i < 0 {
+		return false
+	}
+	old := b.load()
+	backends := append(append(make([]Backend, 0, len(old)-1), old[:i]...), old[i+1:]...)
+	b.backends.Store(&backends)
+	return true
+}
+
+// SetHealthy marks a backend healthy or not; unhealthy backends are never
+// picked.
+func (b *Balancer) SetHealthy(name string, healthy bool) bool {
+	return b.update(name, func(be *Backend) { be.Healthy = healthy })
+}
+
+// ObserveLatency folds a latency measurement into a backend's average.
+func (b *Balancer) ObserveLatency(name string, d time.Duration) bool {
+	return b.update(name, func(be *Backend) {
+		if be.Latency == 0 {
+			be.Latency = d
+			return
+		}
+		be.Latency += time.Duration(latencySmoothing * float64(d-be.Latency))
+	})
+}
+
+func (b *Balancer) update(name string, fn func(be *Backend)) bool {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+	i := b.index(name)
+	if i < 0 {
+		return false
+	}
+	fn(&b.load()[i])
+	return true
+}
+
+// Pick picks a healthy backend at random, in proportion to the weights. It
+// returns false if no healthy backend has a positive weight.
+func (b *Balancer) Pick() (Backend, bool) {
+	var picked Backend
+	var ok bool
+	r := rand.Uint64()
+	stamp := b.rw.RStamp()
+	for {
+		picked, ok = pickWeighted(b.load(), r)
+		if b.rw.Ok(stamp) {
+			return picked, ok
+		}
+	}
+}
+
+func pickWeighted(backends []Backend, r uint64) (Backend, bool) {
+	total := 0
+	for i := range backends {
+		if backends[i].Healthy && backends[i].Weight > 0 {
+			total += backends[i].Weight
+		}
+	}
+	if total == 0 {
+		return Backend{}, false
+	}
+	n := int(r % uint64(total))
+	for i := range backends {
+		if !backends[i].Healthy || backends[i].Weight <= 0 {
+			continue
+		}
+		if n < backends[i].Weight {
+			return backends[i], true
+		}
+		n -= backends[i].Weight
+	}
+	// Only reachable on a torn view, which will not validate
+	return Backend{}, false
+}
+
+// Backends returns the table, in the order backends were added.
+func (b *Balancer) Backends() []Backend {
+	var backends []Backend
+	stamp := b.rw.RStamp()
+	for {
+		backends = append(backends[:0], b.load()...)
+		if b.rw.Ok(stamp) {
+			return backends
+		}
+	}
+}
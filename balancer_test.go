@@ -0,0 +1,55 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBalancerPicksByWeight(t *testing.T) {
+	var b Balancer
+	_, ok := b.Pick()
+	assert.False(t, ok)
+
+	b.Set("a", 1)
+	b.Set("b", 3)
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		be, ok := b.Pick()
+		assert.True(t, ok)
+		counts[be.Name]++
+	}
+
+	assert.InDelta(t, 1000, counts["a"], 200)
+	assert.InDelta(t, 3000, counts["b"], 200)
+}
+
+func TestBalancerSkipsUnhealthyAndRemoved(t *testing.T) {
+	var b Balancer
+	b.Set("a", 1)
+	b.Set("b", 1)
+	b.Set("c", 1)
+
+	assert.True(t, b.SetHealthy("a", false))
+	assert.True(t, b.Remove("b"))
+	assert.False(t, b.Remove("b"))
+	assert.False(t, b.SetHealthy("b", true))
+
+	for i := 0; i < 100; i++ {
+		be, _ := b.Pick()
+		assert.Equal(t, "c", be.Name)
+	}
+	b.Set("c", 0)
+	_, ok := b.Pick()
+	assert.False(t, ok)
+}
+
+func TestBalancerObserveLatency(t *testing.T) {
+	var b Balancer
+	b.Set("a", 1)
+
+	b.ObserveLatency("a", 100*time.Millisecond)
+	b.ObserveLatency("a", 200*time.Millisecond)
+
+	assert.Equal(t, []Backend{{Name: "a", Weight: 1, Healthy: true, Latency: 120 * time.Millisecond}}, b.Backends())
+}
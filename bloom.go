@@ -0,0 +1,124 @@
+package seqmut
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+)
+
+// BloomFilter is a Bloom filter with write-locked Add and optimistic,
+// validated Contains, so lookups, which in a negative cache vastly outnumber
+// insertions, never serialize on a mutex.
+type BloomFilter struct {
+	rw    RWMutex
+	words []uint64
+	k     int
+}
+
+// maxBloomBits bounds the size of a filter, so bit positions can be computed
+// in 32 bits, which keeps them the same as in filters persisted by earlier
+// versions.
+const maxBloomBits = 1 << 31
+
+// NewBloomFilter returns a filter of m bits, rounded up to a multiple of 64,
+// setting k bits per element. m may be at most 2^31.
+func NewBloomFilter(m, k int) *BloomFilter {
+	if m < 1 || k < 1 {
+		panic("seqmut: Bloom filter needs at least one bit and one hash")
+	}
+	if uint64(m) > maxBloomBits {
+		panic("seqmut: Bloom filter larger than 2^31 bits")
+	}
+	return &BloomFilter{words: make([]uint64, (m+63)/64), k: k}
+}
+
+// bloomHashes derives the filter's hashes from two halves of one 64-bit FNV
+// hash, by double hashing. FNV keeps the bit positions stable across
+// processes, which persisted filters rely on.
+func bloomHashes(data []byte) (h1, h2 uint32) {
+	h := fnv.New64a()
+	h.Write(data)
+	sum := h.Sum64()
+	return uint32(sum), uint32(sum>>32) | 1
+}
+
+// Add inserts data into the filter.
+func (f *BloomFilter) Add(data []byte) {
+	h1, h2 := bloomHashes(data)
+	bits := uint32(len(f.words) * 64)
+	f.rw.Lock()
+	for i := 0; i < f.k; i++ {
+		bit := (h1 + uint32(i)*h2) % bits
+		f.words[bit/64] |= 1 << (bit % 64)
+	}
+	f.rw.Unlock()
+}
+
+// Contains reports whether data may have been added. False positives are
+// possible, false negatives are not.
+func (f *BloomFilter) Contains(data []byte) bool {
+	h1, h2 := bloomHashes(data)
+	bits := uint32(len(f.words) * 64)
+	var found bool
+	stamp := f.rw.RStamp()
+	for {
+		found = true
+		for i := 0; i < f.k && found; i++ {
+			bit := (h1 + uint32(i)*h2) % bits
+			found = f.words[bit/64]&(1<<(bit%64)) != 0
+		}
+		if f.rw.Ok(stamp) {
+			return found
+		}
+	}
+}
+
+// Snapshot returns an independent copy of the filter as of one point in
+// time, e.g. for persisting while Adds continue.
+func (f *BloomFilter) Snapshot() *BloomFilter {
+	words := make([]uint64, len(f.words))
+	stamp := f.rw.RStamp()
+	for {
+		copy(words, f.words)
+		if f.rw.Ok(stamp) {
+			return &BloomFilter{words: words, k: f.k}
+		}
+	}
+}
+
+// ErrBadBloomFilter is returned when decoding malformed filter data.
+var ErrBadBloomFilter = errors.New("seqmut: malformed Bloom filter")
+
+// MarshalBinary encodes a consistent snapshot of the filter.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	s := f.Snapshot()
+	buf := make([]byte, 8+8*len(s.words))
+	binary.LittleEndian.PutUint64(buf, uint64(s.k))
+	for i, w := range s.words {
+		binary.LittleEndian.PutUint64(buf[8+8*i:], w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary. It
+// resizes the filter, so unlike the other methods it must not be called
+// concurrently with any of them; it is meant for restoring into a new
+// BloomFilter.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 || len(data)%8 != 0 {
+		return ErrBadBloomFilter
+	}
+	k := binary.LittleEndian.Uint64(data)
+	if k < 1 || k > 64 {
+		return ErrBadBloomFilter
+	}
+	if uint64(len(data)/8-1)*64 > maxBloomBits {
+		return ErrBadBloomFilter
+	}
+	words := make([]uint64, len(data)/8-1)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8+8*i:])
+	}
+	f.words, f.k = words, int(k)
+	return nil
+}
@@ -0,0 +1,60 @@
+package seqmut
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	f := NewBloomFilter(1024, 3)
+	for i := 0; i < 50; i++ {
+		f.Add([]byte(fmt.Sprint("in-", i)))
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.True(t, f.Contains([]byte(fmt.Sprint("in-", i))))
+	}
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if f.Contains([]byte(fmt.Sprint("out-", i))) {
+			falsePositives++
+		}
+	}
+	assert.True(t, falsePositives < 50, "%d false positives", falsePositives)
+}
+
+func TestBloomFilterSnapshotIsIndependent(t *testing.T) {
+	f := NewBloomFilter(256, 2)
+	f.Add([]byte("a"))
+
+	s := f.Snapshot()
+	f.Add([]byte("b"))
+
+	assert.True(t, s.Contains([]byte("a")))
+	assert.False(t, s.Contains([]byte("b")))
+}
+
+func TestBloomFilterRoundTrip(t *testing.T) {
+	f := NewBloomFilter(256, 4)
+	f.Add([]byte("a"))
+
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+	var restored BloomFilter
+	assert.NoError(t, restored.UnmarshalBinary(data))
+
+	assert.True(t, restored.Contains([]byte("a")))
+	assert.False(t, restored.Contains([]byte("b")))
+	assert.Equal(t, ErrBadBloomFilter, restored.UnmarshalBinary(data[:12]))
+}
+
+func TestBloomFilterRejectsOversizedFilters(t *testing.T) {
+	bits := uint64(maxBloomBits) + 64
+	if uint64(int(bits)) != bits {
+		t.Skip("int cannot hold more than 2^31 bits here")
+	}
+
+	assert.Panics(t, func() { NewBloomFilter(int(bits), 1) })
+	assert.NotPanics(t, func() { NewBloomFilter(1, 1) })
+}
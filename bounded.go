@@ -0,0 +1,71 @@
+package seqmut
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ReadBounded is Read with a retry budget: if fn has not completed without a
+// racing writer after maxRetries retries, it gives up with an error wrapping
+// ErrTooManyRetries.
+func ReadBounded(l OptimisticLocker, maxRetries int, fn func()) error {
+	stamp := l.RStamp()
+	for retries := 0; ; retries++ {
+		fn()
+		if l.Ok(stamp) {
+			return nil
+		}
+		if retries == maxRetries {
+			return fmt.Errorf("%w: gave up after %d", ErrTooManyRetries, retries)
+		}
+	}
+}
+
+// LockIfUnchanged acquires the write lock only if no write has started since
+// stamp was taken, which makes read-check-write sequences atomic without
+// holding the lock while reading. Otherwise it returns an error wrapping
+// ErrStampMismatch, and the lock is not held.
+func (rw *RWMutex) LockIfUnchanged(stamp Stamp) error {
+	stamp = rw.checkOwner(stamp)
+	rw.acquire()
+	if current := Stamp(atomic.LoadUint64(&rw.sequence)); current != stamp {
+		rw.release()
+		return fmt.Errorf("%w: stamp %d, now %d", ErrStampMismatch, stamp, current)
+	}
+	rw.enter()
+	return nil
+}
+
+// LockTimeout is Lock with a deadline: if the lock does not become free
+// within timeout, it gives up with an error wrapping ErrWriterTimeout, and
+// the lock is not held. An RWMutex from NewWithLocker whose locker has no
+// TryLock method waits for the lock like Lock does.
+func (rw *RWMutex) LockTimeout(timeout time.Duration) error {
+	if !rw.acquireWithin(timeout) {
+		return fmt.Errorf("%w: waited %v", ErrWriterTimeout, timeout)
+	}
+	rw.enter()
+	return nil
+}
+
+func (rw *RWMutex) acquireWithin(timeout time.Duration) bool {
+	try := rw.mut.TryLock
+	if rw.locker != nil {
+		l, ok := rw.locker.(interface{ TryLock() bool })
+		if !ok {
+			rw.locker.Lock()
+			return true
+		}
+		try = l.TryLock
+	}
+	deadline := time.Now().Add(timeout)
+	for wait := time.Microsecond; !try(); wait = min(2*wait, time.Millisecond) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false
+		}
+		time.Sleep(min(wait, remaining))
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadBounded(t *testing.T) {
+	var rw RWMutex
+	attempts := 0
+
+	err := ReadBounded(&rw, 2, func() {
+		attempts++
+		rw.Lock()
+		rw.Unlock()
+	})
+
+	assert.True(t, errors.Is(err, ErrTooManyRetries), "%v", err)
+	assert.Equal(t, 3, attempts)
+
+	assert.NoError(t, ReadBounded(&rw, 0, func() {}))
+}
+
+func TestLockIfUnchanged(t *testing.T) {
+	var rw RWMutex
+	stamp := *rw.RStamp()
+
+	assert.NoError(t, rw.LockIfUnchanged(stamp))
+	rw.Unlock()
+
+	err := rw.LockIfUnchanged(stamp)
+	assert.True(t, errors.Is(err, ErrStampMismatch), "%v", err)
+
+	// Not left holding the lock
+	rw.Lock()
+	rw.Unlock()
+}
+
+func TestLockTimeout(t *testing.T) {
+	var rw RWMutex
+	rw.Lock()
+	stamp := *rw.RStamp()
+
+	err := rw.LockTimeout(time.Millisecond)
+	assert.True(t, errors.Is(err, ErrWriterTimeout), "%v", err)
+	// Gave up without entering a write
+	assert.Equal(t, stamp, *rw.RStamp())
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		rw.Unlock()
+	}()
+	assert.NoError(t, rw.LockTimeout(time.Minute))
+	rw.Unlock()
+}
+
+func TestLockTimeoutWithLocker(t *testing.T) {
+	var mu sync.Mutex
+	rw := NewWithLocker(&mu)
+	mu.Lock()
+
+	err := rw.LockTimeout(time.Millisecond)
+
+	assert.True(t, errors.Is(err, ErrWriterTimeout), "%v", err)
+	mu.Unlock()
+}
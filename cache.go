@@ -0,0 +1,234 @@
+package seqmut
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sweepBatch is the most expired entries removed under one hold of the write
+// lock, so a large sweep does not stall writers, or make readers retry, for
+// its whole duration.
+const sweepBatch = 256
+
+type cacheEntry[V any] struct {
+	value V
+	// Zero for entries that never expire
+	expires time.Time
+}
+
+func (e *cacheEntry[V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// SeqCache is an in-memory cache with per-entry TTLs. Get is an optimistic
+// read that checks the entry's deadline itself, so expired entries are never
+// returned, even before a sweep has removed them.
+type SeqCache[K comparable, V any] struct {
+	entries SeqMap[K, cacheEntry[V]]
+
+	// Set for bounded caches, see NewBoundedSeqCache. Writers hold policyMu
+	// over both the change to entries and the policy's bookkeeping.
+	policy   EvictionPolicy[K]
+	capacity int
+	policyMu sync.Mutex
+	// Keys read since the policy was last told, see recordRead
+	reads *readBuffer[K]
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSeqCache returns an empty cache. Expired entries stay in memory until
+// Sweep is called, or a sweeper started with StartSweeper gets to them.
+func NewSeqCache[K comparable, V any]() *SeqCache[K, V] {
+	return &SeqCache[K, V]{}
+}
+
+// readBufferSize bounds the reads buffered for the eviction policy between
+// writes; reads landing on a full slot go unrecorded.
+const readBufferSize = 128
+
+const (
+	readSlotEmpty uint32 = iota
+	readSlotWriting
+	readSlotFull
+)
+
+// readBuffer is a lossy buffer of reads for the eviction policy. Each read
+// goes to a random slot, so concurrent readers rarely touch the same cache
+// line, and nothing is shared between them the way a lock or a counter
+// would be.
+type readBuffer[K comparable] struct {
+	slots [readBufferSize]readSlot[K]
+}
+
+type readSlot[K comparable] struct {
+	state atomic.Uint32
+	key   K
+	// Keep slots on separate cache lines
+	_ [64]byte
+}
+
+// NewBoundedSeqCache returns an empty cache holding at most capacity entries,
+// evicting the entries policy picks to make room.
+func NewBoundedSeqCache[K comparable, V any](capacity int, policy EvictionPolicy[K]) *SeqCache[K, V] {
+	return &SeqCache[K, V]{policy: policy, capacity: capacity, reads: &readBuffer[K]{}}
+}
+
+// Get returns the value cached for key, if present and not expired.
+func (c *SeqCache[K, V]) Get(key K) (V, bool) {
+	e, ok := c.entries.Load(key)
+	if !ok || e.expired(time.Now()) {
+		var zero V
+		return zero, false
+	}
+	if c.policy != nil {
+		c.recordRead(key)
+	}
+	return e.value, true
+}
+
+// recordRead buffers a read for the eviction policy. It never waits: if the
+// slot it picks is taken, the read is dropped, which policies are built to
+// tolerate.
+func (c *SeqCache[K, V]) recordRead(key K) {
+	s := &c.reads.slots[rand.Uint32N(readBufferSize)]
+	// Only write to the slot if it looks free, so a full buffer costs readers
+	// nothing but a shared load
+	if s.state.Load() != readSlotEmpty || !s.state.CompareAndSwap(readSlotEmpty, readSlotWriting) {
+		return
+	}
+	s.key = key
+	s.state.Store(readSlotFull)
+}
+
+// drainReads hands buffered reads to the policy; must hold policyMu.
+func (c *SeqCache[K, V]) drainReads() {
+	var reads []K
+	var zero K
+	for i := range c.reads.slots {
+		s := &c.reads.slots[i]
+		if s.state.Load() == readSlotFull {
+			reads = append(reads, s.key)
+			s.key = zero
+			s.state.Store(readSlotEmpty)
+		}
+	}
+	if len(reads) > 0 {
+		c.policy.Accessed(reads)
+	}
+}
+
+// Set caches value for key for ttl; a ttl of zero or less never expires.
+func (c *SeqCache[K, V]) Set(key K, value V, ttl time.Duration) {
+	e := cacheEntry[V]{value: value}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	if c.policy == nil {
+		c.entries.Store(key, e)
+		return
+	}
+
+	c.policyMu.Lock()
+	defer c.policyMu.Unlock()
+	c.drainReads()
+	c.entries.Apply(func(view MutableView[K, cacheEntry[V]]) {
+		if _, ok := view.Load(key); ok {
+			c.policy.Accessed([]K{key})
+		} else {
+			c.policy.Added(key)
+		}
+		view.Store(key, e)
+		for view.Len() > c.capacity {
+			victim, ok := c.policy.Victim()
+			if !ok {
+				break
+			}
+			view.Delete(victim)
+		}
+	})
+}
+
+// Delete removes key from the cache.
+func (c *SeqCache[K, V]) Delete(key K) {
+	if c.policy == nil {
+		c.entries.Delete(key)
+		return
+	}
+	c.policyMu.Lock()
+	defer c.policyMu.Unlock()
+	c.entries.Delete(key)
+	c.policy.Removed(key)
+}
+
+// Len returns the number of entries held, including expired entries that
+// have not been swept yet.
+func (c *SeqCache[K, V]) Len() int {
+	return c.entries.Len()
+}
+
+// Sweep removes expired entries, returning how many it removed.
+func (c *SeqCache[K, V]) Sweep() int {
+	now := time.Now()
+	var expired []K
+	for k, e := range c.entries.All() {
+		if e.expired(now) {
+			expired = append(expired, k)
+		}
+	}
+	removed := 0
+	for len(expired) > 0 {
+		batch := expired[:min(len(expired), sweepBatch)]
+		expired = expired[len(batch):]
+		if c.policy != nil {
+			c.policyMu.Lock()
+		}
+		c.entries.Apply(func(view MutableView[K, cacheEntry[V]]) {
+			for _, k := range batch {
+				// Check again, the entry may have been set since the scan
+				if e, ok := view.Load(k); ok && e.expired(now) {
+					view.Delete(k)
+					if c.policy != nil {
+						c.policy.Removed(k)
+					}
+					removed++
+				}
+			}
+		})
+		if c.policy != nil {
+			c.policyMu.Unlock()
+		}
+	}
+	return removed
+}
+
+// StartSweeper starts a goroutine calling Sweep every interval, until Close.
+func (c *SeqCache[K, V]) StartSweeper(interval time.Duration) {
+	c.stop = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.Sweep()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the sweeper, if one was started, and waits for it to exit.
+func (c *SeqCache[K, V]) Close() {
+	if c.stop != nil {
+		close(c.stop)
+		c.wg.Wait()
+		c.stop = nil
+	}
+}
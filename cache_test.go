@@ -0,0 +1,53 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSeqCacheTTL(t *testing.T) {
+	c := NewSeqCache[string, int]()
+	c.Set("short", 1, time.Millisecond)
+	c.Set("forever", 2, 0)
+
+	v, ok := c.Get("short")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	time.Sleep(2 * time.Millisecond)
+	_, ok = c.Get("short")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	assert.Equal(t, 1, c.Sweep())
+	assert.Equal(t, 1, c.Len())
+	v, ok = c.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestSeqCacheSweepsInBatches(t *testing.T) {
+	c := NewSeqCache[int, int]()
+	for i := 0; i < 3*sweepBatch; i++ {
+		c.Set(i, i, time.Nanosecond)
+	}
+	time.Sleep(time.Millisecond)
+
+	assert.Equal(t, 3*sweepBatch, c.Sweep())
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestSeqCacheSweeper(t *testing.T) {
+	c := NewSeqCache[int, int]()
+	c.StartSweeper(time.Millisecond)
+	defer c.Close()
+	c.Set(1, 1, time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for c.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, 0, c.Len())
+}
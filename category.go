@@ -0,0 +1,88 @@
+package seqmut
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MaxCategories is the number of distinct write categories a CategoryRWMutex
+// can track.
+const MaxCategories = 8
+
+// CategoryMask is a set of write categories, one bit per category.
+type CategoryMask uint8
+
+// AllCategories is the mask covering every category.
+const AllCategories = CategoryMask(1<<MaxCategories - 1)
+
+// CategoryRWMutex is a sequence lock where each write declares which
+// categories of state it touches, and readers only retry if a write touched a
+// category they read. A stats-only write then doesn't invalidate readers of
+// unrelated state guarded by the same lock.
+//
+// Each category has its own sequence; writers still exclude each other
+// regardless of category.
+type CategoryRWMutex struct {
+	mut       sync.Mutex
+	sequences [MaxCategories]uint64
+	// Categories touched by the current writer
+	active CategoryMask
+}
+
+// CategoryStamp is the ticket to ride for reads of a set of categories.
+type CategoryStamp struct {
+	mask      CategoryMask
+	sequences [MaxCategories]Stamp
+}
+
+// RStamp begins an optimistic read of the categories in mask.
+func (c *CategoryRWMutex) RStamp(mask CategoryMask) *CategoryStamp {
+	stamp := &CategoryStamp{mask: mask}
+	c.load(stamp)
+	return stamp
+}
+
+// Ok validates a read of the categories in the stamp's mask; writes to other
+// categories are ignored. See RWMutex.Ok.
+func (c *CategoryRWMutex) Ok(stamp *CategoryStamp) (ok bool) {
+	for i := 0; i < MaxCategories; i++ {
+		if stamp.mask&(1<<i) == 0 {
+			continue
+		}
+		current := Stamp(atomic.LoadUint64(&c.sequences[i]))
+		if (stamp.sequences[i]&1) == 1 || current != stamp.sequences[i] {
+			c.load(stamp)
+			return false
+		}
+	}
+	return true
+}
+
+// Lock acquires the write lock for a write touching the categories in mask.
+func (c *CategoryRWMutex) Lock(mask CategoryMask) {
+	c.mut.Lock()
+	c.active = mask
+	c.bump(mask)
+}
+
+func (c *CategoryRWMutex) Unlock() {
+	c.bump(c.active)
+	c.active = 0
+	c.mut.Unlock()
+}
+
+func (c *CategoryRWMutex) load(stamp *CategoryStamp) {
+	for i := 0; i < MaxCategories; i++ {
+		if stamp.mask&(1<<i) != 0 {
+			stamp.sequences[i] = Stamp(atomic.LoadUint64(&c.sequences[i]))
+		}
+	}
+}
+
+func (c *CategoryRWMutex) bump(mask CategoryMask) {
+	for i := 0; i < MaxCategories; i++ {
+		if mask&(1<<i) != 0 {
+			atomic.AddUint64(&c.sequences[i], 1)
+		}
+	}
+}
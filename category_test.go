@@ -0,0 +1,46 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const (
+	routing CategoryMask = 1 << iota
+	stats
+)
+
+func TestWriteToOtherCategoryDoesNotInvalidate(t *testing.T) {
+	var c CategoryRWMutex
+
+	stamp := c.RStamp(routing)
+	c.Lock(stats)
+
+	assert.True(t, c.Ok(stamp))
+	c.Unlock()
+	assert.True(t, c.Ok(stamp))
+}
+
+func TestWriteToSameCategoryInvalidates(t *testing.T) {
+	var c CategoryRWMutex
+
+	stamp := c.RStamp(routing | stats)
+	c.Lock(stats)
+
+	assert.False(t, c.Ok(stamp))
+	c.Unlock()
+	assert.False(t, c.Ok(stamp))
+	assert.True(t, c.Ok(stamp))
+}
+
+func TestAllCategoriesWriteInvalidatesEveryone(t *testing.T) {
+	var c CategoryRWMutex
+
+	s1 := c.RStamp(routing)
+	s2 := c.RStamp(stats)
+	c.Lock(AllCategories)
+	c.Unlock()
+
+	assert.False(t, c.Ok(s1))
+	assert.False(t, c.Ok(s2))
+}
@@ -0,0 +1,75 @@
+package seqmut
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// CertStore holds a TLS certificate chain that can be replaced while a server
+// is running, for certificate rotation without restarts. GetCertificate is an
+// optimistic read, so handshakes never contend with each other or with a
+// reload. Every reload gets a new version, which handshakes can log to tell
+// which certificate they were served.
+//
+// The zero value holds no certificate.
+type CertStore struct {
+	current Protected[versionedCert]
+}
+
+type versionedCert struct {
+	cert    *tls.Certificate
+	version uint64
+}
+
+var errNoCertificate = errors.New("seqmut: no certificate loaded")
+
+// Reload validates a PEM certificate chain and key and, if they are valid,
+// makes them the current certificate. The key must match the leaf
+// certificate, and the leaf must be valid now.
+func (s *CertStore) Reload(certPEM, keyPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("seqmut: invalid certificate: %w", err)
+	}
+	if now := time.Now(); now.Before(cert.Leaf.NotBefore) || now.After(cert.Leaf.NotAfter) {
+		return fmt.Errorf("seqmut: certificate is only valid from %v to %v", cert.Leaf.NotBefore, cert.Leaf.NotAfter)
+	}
+	s.current.Update(func(c *versionedCert) {
+		c.cert = &cert
+		c.version++
+	})
+	return nil
+}
+
+// ReloadFiles is Reload with the chain and key read from files.
+func (s *CertStore) ReloadFiles(certFile, keyFile string) error {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	return s.Reload(certPEM, keyPEM)
+}
+
+// Current returns the current certificate and its version, which starts at
+// 1 and goes up by one with every successful reload.
+func (s *CertStore) Current() (*tls.Certificate, uint64) {
+	c := s.current.Load()
+	return c.cert, c.version
+}
+
+// GetCertificate returns the current certificate, for use as
+// tls.Config.GetCertificate.
+func (s *CertStore) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, _ := s.Current()
+	if cert == nil {
+		return nil, errNoCertificate
+	}
+	return cert, nil
+}
@@ -0,0 +1,65 @@
+package seqmut
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func selfSigned(t *testing.T, name string, notAfter time.Time) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCertStoreReload(t *testing.T) {
+	var s CertStore
+	_, err := s.GetCertificate(&tls.ClientHelloInfo{})
+	assert.Error(t, err)
+
+	certPEM, keyPEM := selfSigned(t, "one", time.Now().Add(time.Hour))
+	assert.NoError(t, s.Reload(certPEM, keyPEM))
+	cert, err := s.GetCertificate(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, "one", cert.Leaf.Subject.CommonName)
+
+	certPEM, keyPEM = selfSigned(t, "two", time.Now().Add(time.Hour))
+	assert.NoError(t, s.Reload(certPEM, keyPEM))
+	cert, version := s.Current()
+	assert.Equal(t, "two", cert.Leaf.Subject.CommonName)
+	assert.Equal(t, uint64(2), version)
+}
+
+func TestCertStoreRejectsInvalidCertificates(t *testing.T) {
+	var s CertStore
+	certPEM, keyPEM := selfSigned(t, "good", time.Now().Add(time.Hour))
+	assert.NoError(t, s.Reload(certPEM, keyPEM))
+
+	expiredPEM, expiredKeyPEM := selfSigned(t, "expired", time.Now().Add(-time.Minute))
+	assert.Error(t, s.Reload(expiredPEM, expiredKeyPEM))
+	_, otherKeyPEM := selfSigned(t, "other", time.Now().Add(time.Hour))
+	assert.Error(t, s.Reload(certPEM, otherKeyPEM))
+
+	cert, version := s.Current()
+	assert.Equal(t, "good", cert.Leaf.Subject.CommonName)
+	assert.Equal(t, uint64(1), version)
+}
@@ -0,0 +1,26 @@
+package seqmut
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// PublishIntermediate lets a writer in the middle of a long write transaction
+// mark the guarded state as consistent. It briefly makes the sequence even,
+// giving readers a window to complete against the intermediate state, and
+// then re-enters the write critical section at a new version.
+//
+// The caller must hold the lock, and the guarded state must be consistent at
+// the point of the call.
+func (rw *RWMutex) PublishIntermediate() {
+	if debug {
+		if seq := atomic.LoadUint64(&rw.sequence); (seq & 1) == 0 {
+			panic(fmt.Sprintf("seqmut: PublishIntermediate without holding the lock (sequence %d)", seq))
+		}
+	}
+	atomic.AddUint64(&rw.sequence, 1)
+	rw.wakeParked()
+	runtime.Gosched()
+	atomic.AddUint64(&rw.sequence, 1)
+}
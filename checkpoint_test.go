@@ -0,0 +1,67 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPublishIntermediateKeepsLockHeld(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+	rw.PublishIntermediate()
+
+	assert.Equal(t, uint64(3), rw.sequence)
+	stamp := rw.RStamp()
+	assert.False(t, rw.Ok(stamp))
+
+	rw.Unlock()
+	assert.Equal(t, uint64(4), rw.sequence)
+}
+
+func TestPublishIntermediateInvalidatesEarlierStamps(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+	stamp := rw.RStamp()
+	rw.PublishIntermediate()
+
+	assert.False(t, rw.Ok(stamp))
+	rw.Unlock()
+}
+
+func TestReadersProgressDuringLongWrite(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	var rw RWMutex
+	var consistent, step int32
+	var reads int32
+
+	rw.Lock()
+	done := make(chan bool)
+	go func() {
+		for atomic.LoadInt32(&step) >= 0 {
+			stamp := rw.RStamp()
+			c := atomic.LoadInt32(&consistent)
+			if rw.Ok(stamp) {
+				if c != 1 {
+					panic("read inconsistent intermediate state")
+				}
+				atomic.AddInt32(&reads, 1)
+			}
+		}
+		done <- true
+	}()
+
+	// A long write that publishes a checkpoint every so often
+	for atomic.LoadInt32(&reads) == 0 {
+		atomic.StoreInt32(&consistent, 0)
+		atomic.AddInt32(&step, 1)
+		atomic.StoreInt32(&consistent, 1)
+		rw.PublishIntermediate()
+	}
+	rw.Unlock()
+	atomic.StoreInt32(&step, -1)
+	<-done
+}
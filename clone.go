@@ -0,0 +1,184 @@
+package seqmut
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// Cloner is implemented by types that know how to deep-copy themselves.
+// Anything in this package that needs a private copy of a guarded value uses
+// Clone if T implements it (on T or *T), and falls back to a reflection-based
+// deep copy otherwise. The fallback copies time.Time, and structs from other
+// packages that keep pointers in unexported fields, as they are: their
+// internals belong to their package, which may rely on pointer identity (a
+// Time's location must stay == time.Local). Implement Cloner if one of those
+// needs a deeper copy.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Clone returns a deep copy of v, using Cloner if T implements it.
+func Clone[T any](v T) T {
+	if c, ok := any(v).(Cloner[T]); ok {
+		return c.Clone()
+	}
+	if c, ok := any(&v).(Cloner[T]); ok {
+		return c.Clone()
+	}
+	t := reflect.TypeOf(&v).Elem()
+	out := reflect.New(t).Elem()
+	c := cloner{pkg: ownerPkg(t), seen: map[uintptr]reflect.Value{}}
+	c.deepCopy(out, reflect.ValueOf(&v).Elem())
+	return out.Interface().(T)
+}
+
+type cloner struct {
+	// The package of the type being cloned, whose unexported fields are fair
+	// game; empty until a named type is reached
+	pkg string
+	// Pointers already copied, so shared and cyclic structures keep their
+	// shape
+	seen map[uintptr]reflect.Value
+}
+
+// ownerPkg returns the package of the named type t is built from, looking
+// through pointers, slices, arrays and maps.
+func ownerPkg(t reflect.Type) string {
+	for t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+	return t.PkgPath()
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// opaque reports whether values of t are copied as they are.
+func (c *cloner) opaque(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	if c.pkg == "" {
+		// Cloning through an interface or unnamed type, the first named type
+		// reached is the one being cloned
+		c.pkg = t.PkgPath()
+	}
+	return t.Kind() == reflect.Struct && t.PkgPath() != c.pkg && hidesPointers(t)
+}
+
+// hidesPointers reports whether struct type t has an unexported field that
+// holds, or contains, a reference.
+func hidesPointers(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.IsExported() {
+			if f.Type.Kind() == reflect.Struct && hidesPointers(f.Type) {
+				return true
+			}
+			continue
+		}
+		if holdsPointer(f.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func holdsPointer(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.Slice:
+		return true
+	case reflect.Array:
+		return holdsPointer(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if holdsPointer(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deepCopy copies src into dst, which must be settable.
+func (c *cloner) deepCopy(dst, src reflect.Value) {
+	if c.opaque(src.Type()) {
+		dst.Set(src)
+		return
+	}
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if p, ok := c.seen[src.Pointer()]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.seen[src.Pointer()] = p
+		c.deepCopy(p.Elem(), src.Elem())
+		dst.Set(p)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		v := reflect.New(src.Elem().Type()).Elem()
+		c.deepCopy(v, src.Elem())
+		dst.Set(v)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			c.deepCopy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.deepCopy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(src.Type().Key()).Elem()
+			c.deepCopy(k, iter.Key())
+			v := reflect.New(src.Type().Elem()).Elem()
+			c.deepCopy(v, iter.Value())
+			m.SetMapIndex(k, v)
+		}
+		dst.Set(m)
+	case reflect.Struct:
+		if !src.CanAddr() {
+			// Map values and the contents of interfaces are not addressable,
+			// so copy them somewhere that is before reading unexported fields
+			addressable := reflect.New(src.Type()).Elem()
+			addressable.Set(src)
+			src = addressable
+		}
+		for i := 0; i < src.NumField(); i++ {
+			c.deepCopy(settable(dst.Field(i)), settable(src.Field(i)))
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// settable gives access to unexported struct fields, which reflect otherwise
+// refuses to read or write. v must be addressable.
+func settable(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
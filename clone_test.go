@@ -0,0 +1,155 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Tags []string
+}
+
+type outer struct {
+	Name    string
+	Inner   *inner
+	Counts  map[string]int
+	Any     interface{}
+	Fixed   [2]*int
+	private []int
+	Self    *outer
+}
+
+func TestCloneDeepCopiesNestedValues(t *testing.T) {
+	one := 1
+	src := outer{
+		Name:    "a",
+		Inner:   &inner{Tags: []string{"x"}},
+		Counts:  map[string]int{"k": 1},
+		Any:     []int{1},
+		Fixed:   [2]*int{&one, nil},
+		private: []int{1},
+	}
+
+	dst := Clone(src)
+	assert.Equal(t, src, dst)
+
+	src.Inner.Tags[0] = "y"
+	src.Counts["k"] = 2
+	src.Any.([]int)[0] = 2
+	*src.Fixed[0] = 2
+	src.private[0] = 2
+
+	assert.Equal(t, "x", dst.Inner.Tags[0])
+	assert.Equal(t, 1, dst.Counts["k"])
+	assert.Equal(t, 1, dst.Any.([]int)[0])
+	assert.Equal(t, 1, *dst.Fixed[0])
+	assert.Equal(t, 1, dst.private[0])
+}
+
+func TestClonePreservesCycles(t *testing.T) {
+	src := &outer{Name: "loop"}
+	src.Self = src
+
+	dst := Clone(src)
+
+	assert.True(t, src != dst)
+	assert.Same(t, dst, dst.Self)
+}
+
+type withPrivate struct {
+	n    int
+	Tags []string
+}
+
+func TestCloneMapOfStructs(t *testing.T) {
+	src := map[string]withPrivate{"a": {n: 1, Tags: []string{"x"}}}
+
+	dst := Clone(src)
+	assert.Equal(t, src, dst)
+
+	src["a"].Tags[0] = "y"
+	assert.Equal(t, "x", dst["a"].Tags[0])
+	assert.Equal(t, 1, dst["a"].n)
+}
+
+func TestCloneInterfaceHoldingStruct(t *testing.T) {
+	var src any = withPrivate{n: 1, Tags: []string{"x"}}
+
+	dst := Clone(src)
+	assert.Equal(t, src, dst)
+
+	src.(withPrivate).Tags[0] = "y"
+	assert.Equal(t, "x", dst.(withPrivate).Tags[0])
+	assert.Equal(t, 1, dst.(withPrivate).n)
+}
+
+type withForeign struct {
+	At      time.Time
+	Builder *strings.Builder
+	Tags    []string
+}
+
+func TestCloneKeepsForeignInternals(t *testing.T) {
+	src := withForeign{At: time.Now(), Builder: &strings.Builder{}, Tags: []string{"x"}}
+	src.Builder.WriteString("a")
+
+	dst := Clone(src)
+
+	// A Time's location must stay pointer-equal to time.Local
+	assert.Same(t, time.Local, dst.At.Location())
+	assert.True(t, src.At.Equal(dst.At))
+	// The Builder is still copied, but its internals are left alone
+	assert.True(t, src.Builder != dst.Builder)
+	assert.Equal(t, "a", dst.Builder.String())
+	src.Tags[0] = "y"
+	assert.Equal(t, "x", dst.Tags[0])
+}
+
+func TestCloneTimeThroughInterface(t *testing.T) {
+	var src any = time.Now()
+
+	dst := Clone(src)
+
+	assert.Same(t, time.Local, dst.(time.Time).Location())
+}
+
+type countingCloner struct {
+	clones *int
+}
+
+func (c countingCloner) Clone() countingCloner {
+	*c.clones++
+	return c
+}
+
+func TestCloneUsesCloner(t *testing.T) {
+	n := 0
+
+	Clone(countingCloner{clones: &n})
+
+	assert.Equal(t, 1, n)
+}
+
+func TestProtectedSnapshotIsDeep(t *testing.T) {
+	p := NewProtected(inner{Tags: []string{"a"}})
+
+	snap := p.Snapshot()
+	p.Update(func(v *inner) { v.Tags[0] = "b" })
+
+	assert.Equal(t, "a", snap.Tags[0])
+}
+
+func TestRetainedSnapshotIsDeep(t *testing.T) {
+	p := NewProtected(inner{Tags: []string{"a"}})
+	p.RetainSnapshots()
+
+	// Mutate the live value in place without publishing a new snapshot
+	p.rw.Lock()
+	p.value.Tags[0] = "b"
+	stale, _ := p.ReadStale()
+	p.rw.Unlock()
+
+	assert.Equal(t, "a", stale.Tags[0])
+}
@@ -0,0 +1,131 @@
+// Command seqmut-bench sweeps reader/writer contention scenarios across
+// seqmut.RWMutex, sync.RWMutex and atomic.Value, so you can tell whether the
+// optimistic approach wins for the shape of your workload. To run the same
+// sweeps against your own data types, use package seqmutbench directly.
+//
+// Usage:
+//
+//	seqmut-bench -readers 1,4,8 -writers 0,1 -cs 0,100 -payload 8,1024 -format csv
+//
+// Every combination of the parameter lists is run against every
+// implementation for -duration, and one row per run is written to stdout.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"seqmut/seqmutbench"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	readers := flag.String("readers", "1,2,4", "reader goroutine counts to sweep")
+	writers := flag.String("writers", "0,1", "writer goroutine counts to sweep")
+	cs := flag.String("cs", "0,100", "critical section lengths to sweep, in spin iterations")
+	payload := flag.String("payload", "8,256", "guarded payload sizes to sweep, in bytes")
+	impls := flag.String("impls", "seqmut,sync,atomic", "implementations to compare")
+	duration := flag.Duration("duration", 200*time.Millisecond, "how long to run each combination")
+	format := flag.String("format", "csv", "output format, csv or json")
+	flag.Parse()
+
+	// Checked up front, a typo should not cost a whole sweep
+	if err := checkFormat(*format); err != nil {
+		fmt.Fprintln(os.Stderr, "seqmut-bench:", err)
+		os.Exit(2)
+	}
+	sweep, err := parseSweep(*readers, *writers, *cs, *payload, *impls)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seqmut-bench:", err)
+		os.Exit(2)
+	}
+	sweep.Duration = *duration
+
+	results, err := sweep.Run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seqmut-bench:", err)
+		os.Exit(2)
+	}
+	if err := write(os.Stdout, *format, results); err != nil {
+		fmt.Fprintln(os.Stderr, "seqmut-bench:", err)
+		os.Exit(1)
+	}
+}
+
+func parseSweep(readers, writers, cs, payload, impls string) (seqmutbench.Sweep, error) {
+	var s seqmutbench.Sweep
+	var err error
+	if s.Readers, err = parseInts(readers); err != nil {
+		return s, err
+	}
+	if s.Writers, err = parseInts(writers); err != nil {
+		return s, err
+	}
+	if s.CriticalSections, err = parseInts(cs); err != nil {
+		return s, err
+	}
+	if s.Payloads, err = parseInts(payload); err != nil {
+		return s, err
+	}
+	for _, name := range strings.Split(impls, ",") {
+		if _, ok := seqmutbench.Builtins[name]; !ok {
+			return s, fmt.Errorf("unknown implementation %q", name)
+		}
+		s.Impls = append(s.Impls, name)
+	}
+	return s, nil
+}
+
+func parseInts(list string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid count %q", f)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case "csv", "json":
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func write(w io.Writer, format string, results []seqmutbench.Result) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"impl", "readers", "writers", "cs", "payload", "reads_per_sec", "writes_per_sec", "retries_per_read"})
+		for _, r := range results {
+			cw.Write([]string{
+				r.Impl,
+				strconv.Itoa(r.Readers),
+				strconv.Itoa(r.Writers),
+				strconv.Itoa(r.CriticalSection),
+				strconv.Itoa(r.Payload),
+				strconv.FormatFloat(r.ReadsPerSec, 'f', 0, 64),
+				strconv.FormatFloat(r.WritesPerSec, 'f', 0, 64),
+				strconv.FormatFloat(r.RetriesPerRead, 'f', 4, 64),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return checkFormat(format)
+	}
+}
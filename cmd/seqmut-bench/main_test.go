@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"seqmut/seqmutbench"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSweepRunsEveryCombination(t *testing.T) {
+	s, err := parseSweep("1,2", "0,1", "0", "8", "seqmut,sync,atomic")
+	assert.NoError(t, err)
+	s.Duration = time.Millisecond
+
+	results, err := s.Run()
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2*2*3)
+	for _, r := range results {
+		assert.True(t, r.ReadsPerSec > 0, "%+v", r)
+	}
+}
+
+func TestParseSweepRejectsBadInput(t *testing.T) {
+	_, err := parseSweep("1,x", "0", "0", "8", "seqmut")
+	assert.Error(t, err)
+
+	_, err = parseSweep("1", "0", "0", "8", "seqmut,mystery")
+	assert.EqualError(t, err, `unknown implementation "mystery"`)
+}
+
+func TestWriteFormats(t *testing.T) {
+	results := []seqmutbench.Result{{Impl: "seqmut", Readers: 1, Payload: 8, ReadsPerSec: 10}}
+
+	var out bytes.Buffer
+	assert.NoError(t, write(&out, "csv", results))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, "impl,readers,writers,cs,payload,reads_per_sec,writes_per_sec,retries_per_read", lines[0])
+	assert.Equal(t, "seqmut,1,0,0,8,10,0,0.0000", lines[1])
+
+	out.Reset()
+	assert.NoError(t, write(&out, "json", results))
+	var decoded []seqmutbench.Result
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, results, decoded)
+
+	assert.Error(t, write(&out, "xml", results))
+}
+
+func TestCheckFormat(t *testing.T) {
+	assert.NoError(t, checkFormat("csv"))
+	assert.NoError(t, checkFormat("json"))
+	assert.EqualError(t, checkFormat("jsno"), `unknown format "jsno"`)
+}
@@ -0,0 +1,101 @@
+// Code generated by seqmutgen; DO NOT EDIT.
+
+package example
+
+import (
+	"seqmut"
+	"time"
+)
+
+// ConfigGuard guards a Config with a sequence lock.
+type ConfigGuard struct {
+	rw seqmut.RWMutex
+	v  Config
+}
+
+func (g *ConfigGuard) Endpoint() string {
+	stamp := g.rw.RStamp()
+	for {
+		v := g.v.Endpoint
+		if g.rw.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *ConfigGuard) SetEndpoint(v string) {
+	g.rw.Lock()
+	g.v.Endpoint = v
+	g.rw.Unlock()
+}
+
+func (g *ConfigGuard) Retries() int {
+	stamp := g.rw.RStamp()
+	for {
+		v := g.v.Retries
+		if g.rw.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *ConfigGuard) SetRetries(v int) {
+	g.rw.Lock()
+	g.v.Retries = v
+	g.rw.Unlock()
+}
+
+func (g *ConfigGuard) Timeout() time.Duration {
+	stamp := g.rw.RStamp()
+	for {
+		v := g.v.Timeout
+		if g.rw.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *ConfigGuard) SetTimeout(v time.Duration) {
+	g.rw.Lock()
+	g.v.Timeout = v
+	g.rw.Unlock()
+}
+
+func (g *ConfigGuard) weights() []float64 {
+	stamp := g.rw.RStamp()
+	for {
+		v := g.v.weights
+		if g.rw.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *ConfigGuard) setWeights(v []float64) {
+	g.rw.Lock()
+	g.v.weights = v
+	g.rw.Unlock()
+}
+
+// Load returns a consistent copy of the whole Config.
+func (g *ConfigGuard) Load() Config {
+	stamp := g.rw.RStamp()
+	for {
+		v := g.v
+		if g.rw.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+// Store replaces the whole Config.
+func (g *ConfigGuard) Store(v Config) {
+	g.Update(func(p *Config) { *p = v })
+}
+
+// Update runs fn with exclusive access to the whole Config.
+func (g *ConfigGuard) Update(fn func(v *Config)) {
+	g.rw.Lock()
+	defer g.rw.Unlock()
+	fn(&g.v)
+}
@@ -0,0 +1,20 @@
+// Package example holds types wrapped by seqmutgen, so the generated code is
+// compiled and checked along with the rest of the module.
+package example
+
+import "time"
+
+//go:generate go run seqmut/cmd/seqmutgen -type Config example.go
+//go:generate go run seqmut/cmd/seqmutgen -type Stats -per-field example.go
+
+type Config struct {
+	Endpoint string
+	Retries  int
+	Timeout  time.Duration
+	weights  []float64
+}
+
+type Stats struct {
+	Requests, Errors uint64
+	Last             string
+}
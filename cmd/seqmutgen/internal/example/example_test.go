@@ -0,0 +1,27 @@
+package example
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConfigGuard(t *testing.T) {
+	var g ConfigGuard
+
+	g.SetEndpoint("db:5432")
+	g.Update(func(c *Config) { c.Retries = 3 })
+
+	assert.Equal(t, "db:5432", g.Endpoint())
+	assert.Equal(t, Config{Endpoint: "db:5432", Retries: 3}, g.Load())
+}
+
+func TestStatsGuardPerField(t *testing.T) {
+	var g StatsGuard
+
+	g.SetRequests(10)
+	g.Store(Stats{Requests: 11, Errors: 1, Last: "GET /"})
+
+	assert.Equal(t, uint64(11), g.Requests())
+	assert.Equal(t, "GET /", g.Last())
+	assert.Equal(t, Stats{Requests: 11, Errors: 1, Last: "GET /"}, g.Load())
+}
@@ -0,0 +1,98 @@
+// Code generated by seqmutgen; DO NOT EDIT.
+
+package example
+
+import "seqmut"
+
+// StatsGuard guards a Stats with a sequence lock per field.
+type StatsGuard struct {
+	rwRequests seqmut.RWMutex
+	rwErrors   seqmut.RWMutex
+	rwLast     seqmut.RWMutex
+	v          Stats
+}
+
+func (g *StatsGuard) Requests() uint64 {
+	stamp := g.rwRequests.RStamp()
+	for {
+		v := g.v.Requests
+		if g.rwRequests.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *StatsGuard) SetRequests(v uint64) {
+	g.rwRequests.Lock()
+	g.v.Requests = v
+	g.rwRequests.Unlock()
+}
+
+func (g *StatsGuard) Errors() uint64 {
+	stamp := g.rwErrors.RStamp()
+	for {
+		v := g.v.Errors
+		if g.rwErrors.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *StatsGuard) SetErrors(v uint64) {
+	g.rwErrors.Lock()
+	g.v.Errors = v
+	g.rwErrors.Unlock()
+}
+
+func (g *StatsGuard) Last() string {
+	stamp := g.rwLast.RStamp()
+	for {
+		v := g.v.Last
+		if g.rwLast.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *StatsGuard) SetLast(v string) {
+	g.rwLast.Lock()
+	g.v.Last = v
+	g.rwLast.Unlock()
+}
+
+// Load returns a consistent copy of the whole Stats.
+func (g *StatsGuard) Load() Stats {
+	for {
+		sRequests := g.rwRequests.RStamp()
+		sErrors := g.rwErrors.RStamp()
+		sLast := g.rwLast.RStamp()
+		v := g.v
+		if g.rwRequests.Ok(sRequests) && g.rwErrors.Ok(sErrors) && g.rwLast.Ok(sLast) {
+			return v
+		}
+	}
+}
+
+// Store replaces the whole Stats.
+func (g *StatsGuard) Store(v Stats) {
+	g.Update(func(p *Stats) { *p = v })
+}
+
+// Update runs fn with exclusive access to the whole Stats.
+func (g *StatsGuard) Update(fn func(v *Stats)) {
+	g.lockAll()
+	defer g.unlockAll()
+	fn(&g.v)
+}
+
+func (g *StatsGuard) lockAll() {
+	g.rwRequests.Lock()
+	g.rwErrors.Lock()
+	g.rwLast.Lock()
+}
+
+func (g *StatsGuard) unlockAll() {
+	g.rwRequests.Unlock()
+	g.rwErrors.Unlock()
+	g.rwLast.Unlock()
+}
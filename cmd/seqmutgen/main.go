@@ -0,0 +1,287 @@
+// Command seqmutgen generates a guarded wrapper for a struct type, with typed
+// optimistic getters and write-locked setters for each field.
+//
+// Usage:
+//
+//	seqmutgen -type Config [-per-field] [-o config_seqmut.go] config.go
+//
+// For a struct Config, this emits a ConfigGuard type holding a Config. Each
+// field F gets a getter F() that retries until it reads a consistent value,
+// without allocating, and a setter SetF(v) that writes under the lock.
+// Unexported fields get unexported setters. Load, Store and Update work on the whole struct.
+//
+// With -per-field, every field gets its own sequence, so readers of one field
+// never retry because another field changed. Load then has to validate every
+// field's sequence, and Update has to take every field's lock.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the struct type to wrap")
+	perField := flag.Bool("per-field", false, "give every field its own sequence")
+	output := flag.String("o", "", "output file, defaults to <type>_seqmut.go next to the input")
+	pkgPath := flag.String("seqmut", "seqmut", "import path of the seqmut package")
+	flag.Parse()
+
+	if *typeName == "" || flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: seqmutgen -type T [-per-field] [-o file] input.go")
+		os.Exit(2)
+	}
+	input := flag.Arg(0)
+	src, err := os.ReadFile(input)
+	if err != nil {
+		fatal(err)
+	}
+
+	out, err := generate(input, src, *typeName, *pkgPath, *perField)
+	if err != nil {
+		fatal(err)
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Join(filepath.Dir(input), strings.ToLower(*typeName)+"_seqmut.go")
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "seqmutgen:", err)
+	os.Exit(1)
+}
+
+type field struct {
+	Name   string
+	Type   string
+	Setter string
+}
+
+// importSpec is an import the generated file needs, with the name it was
+// given in the input file, if any.
+type importSpec struct {
+	Name string
+	Path string
+}
+
+type model struct {
+	Package  string
+	Import   string
+	Imports  []importSpec
+	Type     string
+	PerField bool
+	Fields   []field
+}
+
+// generate returns the formatted source of the wrapper for typeName, which
+// must be a struct declared in src.
+func generate(filename string, src []byte, typeName, pkgPath string, perField bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	st := findStruct(file, typeName)
+	if st == nil {
+		return nil, fmt.Errorf("struct type %s not found in %s", typeName, filename)
+	}
+
+	m := model{Package: file.Name.Name, Import: pkgPath, Type: typeName, PerField: perField}
+	imports, err := usedImports(file, st, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	m.Imports = imports
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, errors.New("embedded fields are not supported")
+		}
+		var typ bytes.Buffer
+		if err := format.Node(&typ, fset, f.Type); err != nil {
+			return nil, err
+		}
+		for _, name := range f.Names {
+			m.Fields = append(m.Fields, field{Name: name.Name, Type: typ.String(), Setter: setterName(name)})
+		}
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, m); err != nil {
+		return nil, err
+	}
+	return format.Source(out.Bytes())
+}
+
+// usedImports returns the imports of file that the field types of st refer
+// to, other than pkgPath, which the generated file always imports.
+func usedImports(file *ast.File, st *ast.StructType, pkgPath string) ([]importSpec, error) {
+	used := map[string]bool{}
+	ast.Inspect(st, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				used[pkg.Name] = true
+			}
+		}
+		return true
+	})
+
+	var imports []importSpec
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		// Without an explicit name, assume the package is named after the
+		// last element of its path, as it almost always is
+		if !used[name] && !(name == "" && used[path[strings.LastIndex(path, "/")+1:]]) {
+			continue
+		}
+		if path != pkgPath || name != "" {
+			imports = append(imports, importSpec{Name: name, Path: path})
+		}
+	}
+	return imports, nil
+}
+
+// setterName keeps setters of unexported fields unexported.
+func setterName(name *ast.Ident) string {
+	if name.IsExported() {
+		return "Set" + name.Name
+	}
+	return "set" + strings.ToUpper(name.Name[:1]) + name.Name[1:]
+}
+
+func findStruct(file *ast.File, name string) *ast.StructType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok && ts.Name.Name == name {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+var tmpl = template.Must(template.New("guard").Parse(`// Code generated by seqmutgen; DO NOT EDIT.
+
+package {{.Package}}
+
+{{if .Imports -}}
+import (
+	"{{.Import}}"
+{{- range .Imports}}
+	{{with .Name}}{{.}} {{end}}"{{.Path}}"
+{{- end}}
+)
+{{- else -}}
+import "{{.Import}}"
+{{- end}}
+
+// {{.Type}}Guard guards a {{.Type}} with a sequence lock{{if .PerField}} per field{{end}}.
+type {{.Type}}Guard struct {
+{{- if .PerField}}
+{{- range .Fields}}
+	rw{{.Name}} seqmut.RWMutex
+{{- end}}
+{{- else}}
+	rw seqmut.RWMutex
+{{- end}}
+	v  {{.Type}}
+}
+{{range .Fields}}
+func (g *{{$.Type}}Guard) {{.Name}}() {{.Type}} {
+	stamp := g.rw{{if $.PerField}}{{.Name}}{{end}}.RStamp()
+	for {
+		v := g.v.{{.Name}}
+		if g.rw{{if $.PerField}}{{.Name}}{{end}}.Ok(stamp) {
+			return v
+		}
+	}
+}
+
+func (g *{{$.Type}}Guard) {{.Setter}}(v {{.Type}}) {
+	g.rw{{if $.PerField}}{{.Name}}{{end}}.Lock()
+	g.v.{{.Name}} = v
+	g.rw{{if $.PerField}}{{.Name}}{{end}}.Unlock()
+}
+{{end}}
+// Load returns a consistent copy of the whole {{.Type}}.
+func (g *{{.Type}}Guard) Load() {{.Type}} {
+{{- if .PerField}}
+	for {
+{{- range .Fields}}
+		s{{.Name}} := g.rw{{.Name}}.RStamp()
+{{- end}}
+		v := g.v
+		if {{range $i, $f := .Fields}}{{if $i}} && {{end}}g.rw{{$f.Name}}.Ok(s{{$f.Name}}){{end}} {
+			return v
+		}
+	}
+{{- else}}
+	stamp := g.rw.RStamp()
+	for {
+		v := g.v
+		if g.rw.Ok(stamp) {
+			return v
+		}
+	}
+{{- end}}
+}
+
+// Store replaces the whole {{.Type}}.
+func (g *{{.Type}}Guard) Store(v {{.Type}}) {
+	g.Update(func(p *{{.Type}}) { *p = v })
+}
+
+// Update runs fn with exclusive access to the whole {{.Type}}.
+func (g *{{.Type}}Guard) Update(fn func(v *{{.Type}})) {
+{{- if .PerField}}
+	g.lockAll()
+	defer g.unlockAll()
+{{- else}}
+	g.rw.Lock()
+	defer g.rw.Unlock()
+{{- end}}
+	fn(&g.v)
+}
+{{- if .PerField}}
+
+func (g *{{.Type}}Guard) lockAll() {
+{{- range .Fields}}
+	g.rw{{.Name}}.Lock()
+{{- end}}
+}
+
+func (g *{{.Type}}Guard) unlockAll() {
+{{- range .Fields}}
+	g.rw{{.Name}}.Unlock()
+{{- end}}
+}
+{{- end}}
+`))
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The generated files in internal/example are compiled with the module, so
+// checking that they are up to date also checks that the output compiles.
+func TestGeneratedExamplesAreUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	src, err := os.ReadFile(filepath.Join(dir, "example.go"))
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		typ      string
+		perField bool
+	}{
+		{"Config", false},
+		{"Stats", true},
+	} {
+		out, err := generate("example.go", src, tc.typ, "seqmut", tc.perField)
+		assert.NoError(t, err)
+
+		golden, err := os.ReadFile(filepath.Join(dir, strings.ToLower(tc.typ)+"_seqmut.go"))
+		assert.NoError(t, err)
+		assert.Equal(t, string(golden), string(out), "run go generate in %s", dir)
+	}
+}
+
+func TestGenerateRejectsUnknownType(t *testing.T) {
+	_, err := generate("x.go", []byte("package x\n\ntype A struct{}\n"), "B", "seqmut", false)
+
+	assert.EqualError(t, err, "struct type B not found in x.go")
+}
+
+func TestGenerateRejectsEmbeddedFields(t *testing.T) {
+	_, err := generate("x.go", []byte("package x\n\ntype B struct{}\ntype A struct{ B }\n"), "A", "seqmut", false)
+
+	assert.Error(t, err)
+}
+
+func TestGenerateImportsFieldTypePackages(t *testing.T) {
+	src := `package x
+
+import (
+	"net/netip"
+	"os"
+	stdtime "time"
+)
+
+var _ = os.Args
+
+type A struct {
+	Addr    netip.Addr
+	Timeout stdtime.Duration
+}
+`
+	out, err := generate("x.go", []byte(src), "A", "seqmut", false)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(out), "import (\n\t\"net/netip\"\n\t\"seqmut\"\n\tstdtime \"time\"\n)\n")
+}
@@ -0,0 +1,52 @@
+package seqmut
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	src := NewProtected(label{Name: "a", At: point{1, 2}})
+	for i := 0; i < 5; i++ {
+		src.Update(func(v *label) { v.At.X++ })
+	}
+
+	var buf bytes.Buffer
+	stamp, err := src.EncodeSnapshot(&buf, GobCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, Stamp(10), stamp)
+
+	var dst Protected[label]
+	restored, err := dst.DecodeSnapshot(&buf, GobCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, stamp, restored)
+
+	v, at := dst.LoadStamped()
+	assert.Equal(t, label{Name: "a", At: point{6, 2}}, v)
+	assert.Equal(t, stamp, at)
+}
+
+func TestDecodeSnapshotNeverMovesSequenceBackwards(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewProtected(1).EncodeSnapshot(&buf, GobCodec{})
+	assert.NoError(t, err)
+
+	dst := NewProtected(0)
+	for i := 0; i < 3; i++ {
+		dst.Store(i)
+	}
+	restored, err := dst.DecodeSnapshot(&buf, GobCodec{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Stamp(8), restored)
+	assert.Equal(t, 1, dst.Load())
+}
+
+func TestDecodeSnapshotRejectsGarbage(t *testing.T) {
+	var dst Protected[int]
+
+	_, err := dst.DecodeSnapshot(bytes.NewReader([]byte("definitely not a snapshot")), GobCodec{})
+
+	assert.Equal(t, ErrNotSnapshot, err)
+}
@@ -0,0 +1,74 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestCombinerAppliesWriteUnderLock(t *testing.T) {
+	var rw RWMutex
+	c := NewCombiner(&rw)
+	v := 0
+
+	err := c.Do(func() error {
+		v = 1
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, uint64(2), rw.sequence)
+}
+
+func TestCombinerReturnsErrorToSubmitter(t *testing.T) {
+	var rw RWMutex
+	c := NewCombiner(&rw)
+	boom := errors.New("boom")
+
+	err := c.Do(func() error { return boom })
+
+	assert.Equal(t, boom, err)
+}
+
+func TestCombinerRepanicsInSubmitter(t *testing.T) {
+	var rw RWMutex
+	c := NewCombiner(&rw)
+
+	assert.Panics(t, func() {
+		_ = c.Do(func() error { panic("boom") })
+	})
+
+	// Lock must have been released despite the panic
+	stamp := rw.RStamp()
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestCombinerBatchesConcurrentWrites(t *testing.T) {
+	n := 1000
+	if testing.Short() {
+		n = 50
+	}
+	var rw RWMutex
+	c := NewCombiner(&rw)
+	counter := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = c.Do(func() error {
+				counter++
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, n, counter)
+	// At most one Lock/Unlock per write, usually far fewer
+	assert.True(t, rw.sequence <= uint64(2*n))
+	assert.Equal(t, uint64(0), rw.sequence&1)
+}
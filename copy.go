@@ -0,0 +1,49 @@
+package seqmut
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// copyBlock is how much copyValidated copies between looks at the sequence.
+// It is large enough that the extra loads don't show against the memmove,
+// and small enough that a copy racing a writer is abandoned early.
+const copyBlock = 64 << 10
+
+// copyValidated is the copy step of a validate-copy-validate read of large
+// payloads. It copies src into dst a block at a time, giving up as soon as
+// the sequence moves away from stamp, since the read will have to be retried
+// anyway. It returns the number of bytes copied, and false if it gave up;
+// a true return still needs the usual validation afterwards.
+//
+// The blocks themselves are copied with the built-in copy: the runtime's
+// memmove already uses the widest moves the CPU has, and hand-rolled word
+// loops benchmark several times slower (see BenchmarkCopyValidated).
+func copyValidated(dst, src []byte, sequence *uint64, stamp Stamp) (int, bool) {
+	n := min(len(dst), len(src))
+	for off := 0; off < n; off += copyBlock {
+		if Stamp(atomic.LoadUint64(sequence)) != stamp {
+			return off, false
+		}
+		copy(dst[off:n], src[off:min(off+copyBlock, n)])
+	}
+	return n, true
+}
+
+// CopyChecked makes a single attempt at copying src, which rw guards, into
+// dst. It returns the stamp the copy was taken at, or an error wrapping
+// ErrTornRead if a writer interfered, in which case dst holds garbage. It is
+// for callers with their own retry policy, such as falling back to excluding
+// writers after one failure, rather than looping like Read.
+func CopyChecked(dst, src []byte, rw *RWMutex) (Stamp, error) {
+	stamp := rw.RStamp()
+	seen := rw.plain(stamp)
+	if seen&1 == 1 {
+		return seen, fmt.Errorf("%w: writer active at %d", ErrTornRead, seen)
+	}
+	copyValidated(dst, src, &rw.sequence, seen)
+	if !rw.Ok(stamp) {
+		return seen, fmt.Errorf("%w: sequence moved from %d to %d", ErrTornRead, seen, rw.plain(stamp))
+	}
+	return seen, nil
+}
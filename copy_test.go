@@ -0,0 +1,84 @@
+package seqmut
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unsafe"
+)
+
+func TestCopyValidated(t *testing.T) {
+	src := make([]byte, 3*copyBlock+10)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	dst := make([]byte, len(src))
+	var seq uint64 = 4
+
+	n, ok := copyValidated(dst, src, &seq, 4)
+	assert.True(t, ok)
+	assert.Equal(t, len(src), n)
+	assert.Equal(t, src, dst)
+
+	n, ok = copyValidated(dst, src, &seq, 2)
+	assert.False(t, ok)
+	assert.Equal(t, 0, n)
+
+	n, _ = copyValidated(dst[:5], src, &seq, 4)
+	assert.Equal(t, 5, n)
+}
+
+// wordCopy is the hand-rolled alternative to the built-in copy, kept here to
+// show why copyValidated doesn't use one.
+func wordCopy(dst, src []byte) {
+	n := min(len(dst), len(src))
+	d, s := unsafe.Pointer(unsafe.SliceData(dst)), unsafe.Pointer(unsafe.SliceData(src))
+	i := 0
+	for ; i+32 <= n; i += 32 {
+		*(*[4]uint64)(unsafe.Add(d, i)) = *(*[4]uint64)(unsafe.Add(s, i))
+	}
+	for ; i < n; i++ {
+		*(*byte)(unsafe.Add(d, i)) = *(*byte)(unsafe.Add(s, i))
+	}
+}
+
+func BenchmarkCopyValidated(b *testing.B) {
+	var seq uint64
+	for _, size := range []int{64, 1024, 16 << 10, 1 << 20} {
+		src, dst := make([]byte, size), make([]byte, size)
+		b.Run(fmt.Sprintf("builtin/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				copy(dst, src)
+			}
+		})
+		b.Run(fmt.Sprintf("words/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				wordCopy(dst, src)
+			}
+		})
+		b.Run(fmt.Sprintf("validated/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				copyValidated(dst, src, &seq, 0)
+			}
+		})
+	}
+}
+
+func TestCopyChecked(t *testing.T) {
+	var rw RWMutex
+	src, dst := []byte("payload"), make([]byte, 7)
+
+	stamp, err := CopyChecked(dst, src, &rw)
+	assert.NoError(t, err)
+	assert.Equal(t, Stamp(0), stamp)
+	assert.Equal(t, src, dst)
+
+	rw.Lock()
+	_, err = CopyChecked(dst, src, &rw)
+	assert.True(t, errors.Is(err, ErrTornRead), "%v", err)
+	rw.Unlock()
+}
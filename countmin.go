@@ -0,0 +1,99 @@
+package seqmut
+
+// CountMinSketch estimates how often keys have been seen in a fixed amount of
+// memory. Increments take the write lock briefly; estimates and scrapes are
+// optimistic reads of the whole table, so exported estimates are never
+// internally contradictory, for instance summing to more than Total.
+type CountMinSketch struct {
+	rw     RWMutex
+	width  uint32
+	counts [][]uint64
+	total  uint64
+}
+
+// NewCountMinSketch returns a sketch with depth rows of width counters.
+// Estimates overcount by at most about 2/width of Total with probability
+// 1-2^-depth.
+func NewCountMinSketch(width, depth int) *CountMinSketch {
+	if width < 1 || depth < 1 {
+		panic("seqmut: count-min sketch needs at least one row and column")
+	}
+	counts := make([][]uint64, depth)
+	for i := range counts {
+		counts[i] = make([]uint64, width)
+	}
+	return &CountMinSketch{width: uint32(width), counts: counts}
+}
+
+// Add counts n occurrences of key.
+func (s *CountMinSketch) Add(key []byte, n uint64) {
+	h1, h2 := bloomHashes(key)
+	s.rw.Lock()
+	for row, counts := range s.counts {
+		counts[(h1+uint32(row)*h2)%s.width] += n
+	}
+	s.total += n
+	s.rw.Unlock()
+}
+
+func (s *CountMinSketch) estimate(h1, h2 uint32) uint64 {
+	min := ^uint64(0)
+	for row, counts := range s.counts {
+		if c := counts[(h1+uint32(row)*h2)%s.width]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// Estimate returns an upper bound on how often key has been added.
+func (s *CountMinSketch) Estimate(key []byte) uint64 {
+	h1, h2 := bloomHashes(key)
+	var n uint64
+	stamp := s.rw.RStamp()
+	for {
+		n = s.estimate(h1, h2)
+		if s.rw.Ok(stamp) {
+			return n
+		}
+	}
+}
+
+// EstimateAll returns estimates for all keys, as of a single point in time,
+// together with the total count at that point.
+func (s *CountMinSketch) EstimateAll(keys [][]byte) (estimates []uint64, total uint64) {
+	h1s, h2s := make([]uint32, len(keys)), make([]uint32, len(keys))
+	for i, key := range keys {
+		h1s[i], h2s[i] = bloomHashes(key)
+	}
+	estimates = make([]uint64, len(keys))
+	stamp := s.rw.RStamp()
+	for {
+		for i := range keys {
+			estimates[i] = s.estimate(h1s[i], h2s[i])
+		}
+		total = s.total
+		if s.rw.Ok(stamp) {
+			return estimates, total
+		}
+	}
+}
+
+// Scrape returns a consistent copy of the counter table, rows first, and the
+// total count, for exporting the sketch as a whole.
+func (s *CountMinSketch) Scrape() (counts [][]uint64, total uint64) {
+	counts = make([][]uint64, len(s.counts))
+	for i := range counts {
+		counts[i] = make([]uint64, s.width)
+	}
+	stamp := s.rw.RStamp()
+	for {
+		for i := range counts {
+			copy(counts[i], s.counts[i])
+		}
+		total = s.total
+		if s.rw.Ok(stamp) {
+			return counts, total
+		}
+	}
+}
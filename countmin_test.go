@@ -0,0 +1,39 @@
+package seqmut
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	s := NewCountMinSketch(256, 4)
+	s.Add([]byte("hot"), 100)
+	for i := 0; i < 50; i++ {
+		s.Add([]byte(fmt.Sprint("cold-", i)), 1)
+	}
+
+	assert.True(t, s.Estimate([]byte("hot")) >= 100)
+	assert.True(t, s.Estimate([]byte("hot")) < 110)
+	estimates, total := s.EstimateAll([][]byte{[]byte("hot"), []byte("cold-0")})
+	assert.Equal(t, uint64(150), total)
+	assert.True(t, estimates[0] >= 100)
+	assert.True(t, estimates[1] >= 1)
+}
+
+func TestCountMinSketchScrapeIsConsistent(t *testing.T) {
+	s := NewCountMinSketch(8, 2)
+	s.Add([]byte("a"), 3)
+	s.Add([]byte("b"), 4)
+
+	counts, total := s.Scrape()
+
+	assert.Equal(t, uint64(7), total)
+	for _, row := range counts {
+		var sum uint64
+		for _, c := range row {
+			sum += c
+		}
+		assert.Equal(t, total, sum)
+	}
+}
@@ -0,0 +1,88 @@
+package seqmut
+
+import (
+	"sync"
+	"time"
+)
+
+// Recomputable is a derived value that can tell whether it is out of date,
+// such as a Computed or a Derived. Version brings the value up to date and
+// changes whenever it is recomputed; GetVersioned returns the value together
+// with its version.
+type Recomputable[U any] interface {
+	Get() U
+	GetVersioned() (U, uint64)
+	Stale() bool
+	Version() uint64
+}
+
+// Debounced recomputes an expensive derived value in the background, at most
+// once per interval however often its sources are written, and hands each
+// new result to its subscribers. A burst of writes within one interval costs
+// a single recompute, of the value as of the end of the burst.
+type Debounced[U any] struct {
+	v        Recomputable[U]
+	interval time.Duration
+
+	subscribers subscribers[U]
+	// Version of the value last handed to subscribers, only touched by run
+	// once started
+	notified uint64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDebounced starts recomputing v, checking every interval whether any of
+// its sources have changed, until Close. v is brought up to date first;
+// subscribers only hear about values computed after that.
+func NewDebounced[U any](v Recomputable[U], interval time.Duration) *Debounced[U] {
+	d := &Debounced[U]{v: v, interval: interval, notified: v.Version(), stop: make(chan struct{})}
+	d.wg.Add(1)
+	go d.run()
+	return d
+}
+
+func (d *Debounced[U]) run() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Compare versions rather than asking Stale: a Get in between
+			// ticks recomputes the value, and that change is still news
+			if u, version := d.v.GetVersioned(); version != d.notified {
+				d.notified = version
+				d.notify(u)
+			}
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// Get returns the value, recomputing it right away if it is stale rather
+// than waiting for the next interval.
+func (d *Debounced[U]) Get() U {
+	return d.v.Get()
+}
+
+// Subscribe calls fn with every value recomputed from now on. fn runs on the
+// scheduler's goroutine, so a slow subscriber delays the next recompute. The
+// returned function ends the subscription; it may be called from fn.
+func (d *Debounced[U]) Subscribe(fn func(U)) (cancel func()) {
+	return d.subscribers.add(fn)
+}
+
+func (d *Debounced[U]) notify(u U) {
+	for _, sub := range d.subscribers.list() {
+		sub.fn(u)
+	}
+}
+
+// Close stops the scheduler and waits for it to exit.
+func (d *Debounced[U]) Close() {
+	close(d.stop)
+	d.wg.Wait()
+}
@@ -0,0 +1,94 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebouncedCoalescesBursts(t *testing.T) {
+	p := NewProtected(0)
+	var calls atomic.Int64
+	d := NewDebounced[int](NewDerived(p, func(v int) int { calls.Add(1); return v * 2 }), 20*time.Millisecond)
+	defer d.Close()
+	results := make(chan int, 16)
+	d.Subscribe(func(v int) { results <- v })
+
+	for i := 1; i <= 100; i++ {
+		p.Store(i)
+	}
+
+	assert.Equal(t, 200, <-results)
+	// Once up front, and once for the whole burst
+	assert.Equal(t, int64(2), calls.Load())
+	select {
+	case v := <-results:
+		t.Fatalf("unexpected recompute to %d", v)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestDebouncedCancelledSubscriptionIsNotCalled(t *testing.T) {
+	p := NewProtected(0)
+	d := NewDebounced[int](NewDerived(p, func(v int) int { return v }), time.Millisecond)
+	defer d.Close()
+	called := make(chan int, 16)
+	cancel := d.Subscribe(func(v int) { called <- v })
+	p.Store(1)
+	assert.Equal(t, 1, <-called)
+
+	cancel()
+	p.Store(2)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(t, 2, d.Get())
+	assert.Len(t, called, 0)
+}
+
+func TestDebouncedNotifiesChangesAlreadyRecomputedByGet(t *testing.T) {
+	p := NewProtected(0)
+	d := NewDebounced[int](NewDerived(p, func(v int) int { return v }), 20*time.Millisecond)
+	defer d.Close()
+	results := make(chan int, 16)
+	d.Subscribe(func(v int) { results <- v })
+
+	p.Store(1)
+	// Recomputes before the scheduler gets to it, so it is no longer stale
+	assert.Equal(t, 1, d.Get())
+
+	assert.Equal(t, 1, <-results)
+}
+
+func TestDebouncedSubscriberCancelsItself(t *testing.T) {
+	p := NewProtected(0)
+	d := NewDebounced[int](NewDerived(p, func(v int) int { return v }), time.Millisecond)
+	defer d.Close()
+	called := make(chan int, 16)
+	var cancel func()
+	cancel = d.Subscribe(func(v int) {
+		called <- v
+		cancel()
+	})
+
+	p.Store(1)
+	assert.Equal(t, 1, <-called)
+	p.Store(2)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Len(t, called, 0)
+}
+
+func TestDebouncedDoesNotNotifyTheInitialValue(t *testing.T) {
+	p := NewProtected(1)
+	d := NewDebounced[int](NewDerived(p, func(v int) int { return v }), time.Millisecond)
+	defer d.Close()
+	called := make(chan int, 16)
+	d.Subscribe(func(v int) { called <- v })
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, called, 0)
+
+	p.Store(2)
+	assert.Equal(t, 2, <-called)
+}
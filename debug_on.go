@@ -0,0 +1,12 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+// debug enables extra, comparatively expensive, misuse checks. Build with
+// -tags seqmutdebug to turn them on.
+const debug = true
+
+// ownerBits are the bits of a stamp from RWMutex.RStamp holding the owner
+// word, see ownerTag.
+const ownerBits Stamp = 0xffff << 48
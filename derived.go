@@ -0,0 +1,198 @@
+package seqmut
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Source is something a derived value can depend on: a Protected, or another
+// derived value. Version changes whenever the source's value may have.
+type Source interface {
+	Version() uint64
+}
+
+// Version returns the current sequence of the lock, as a Source. It is odd
+// while a write is in progress.
+func (p *Protected[T]) Version() uint64 {
+	return atomic.LoadUint64(&p.rw.sequence)
+}
+
+// Computed is a memoized value computed from any number of Sources, including
+// other Computed and Derived values, so they form a graph of incremental
+// computations. The result of f is cached together with the versions of the
+// sources it was computed from, and recomputed on the next Get once any of
+// them moves on; the sequence doubles as the cache's invalidation signal.
+//
+// Nothing is recomputed eagerly: a stale value is only brought up to date,
+// along with whatever it depends on, when it or something downstream of it is
+// read.
+type Computed[U any] struct {
+	f  func() U
+	mu sync.Mutex
+	// Guarded by mu
+	sources []Source
+
+	cached atomic.Pointer[computedValue[U]]
+	// Bumped by every recompute, and used as the version of the value
+	generation atomic.Uint64
+}
+
+type computedValue[U any] struct {
+	sources  []Source
+	versions []uint64
+	value    U
+	version  uint64
+}
+
+func (v *computedValue[U]) fresh() bool {
+	for i, s := range v.sources {
+		if s.Version() != v.versions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NewComputed returns a Computed running f over sources. f reads the sources
+// itself, with their usual read methods; it never runs concurrently with
+// itself, and is re-run if a source moves on while it does.
+func NewComputed[U any](f func() U, sources ...Source) *Computed[U] {
+	return &Computed[U]{f: f, sources: slices.Clone(sources)}
+}
+
+// Guards the shape of the dependency graph while DependOn checks for cycles
+var graphMu sync.Mutex
+
+// DependOn adds s to the sources of c. It returns ErrCycle, and leaves c
+// unchanged, if s is c or depends on c, directly or not.
+func (c *Computed[U]) DependOn(s Source) error {
+	graphMu.Lock()
+	defer graphMu.Unlock()
+	if reaches(s, c) {
+		return ErrCycle
+	}
+	c.mu.Lock()
+	c.sources = append(c.sources[:len(c.sources):len(c.sources)], s)
+	c.cached.Store(nil)
+	c.mu.Unlock()
+	return nil
+}
+
+// dependent is implemented by Sources that have sources of their own.
+type dependent interface {
+	dependencies() []Source
+}
+
+func (c *Computed[U]) dependencies() []Source {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sources
+}
+
+// reaches returns true if target is from, or one of its transitive sources.
+func reaches(from, target Source) bool {
+	if from == target {
+		return true
+	}
+	if d, ok := from.(dependent); ok {
+		for _, s := range d.dependencies() {
+			if reaches(s, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Get returns the value, recomputing it first if any source has changed
+// since it was computed.
+func (c *Computed[U]) Get() U {
+	return c.load().value
+}
+
+// Version returns the version of the value, bringing it up to date first. It
+// changes every time the value is recomputed.
+func (c *Computed[U]) Version() uint64 {
+	return c.load().version
+}
+
+// GetVersioned is Get, also returning the Version of the value it returns.
+func (c *Computed[U]) GetVersioned() (U, uint64) {
+	v := c.load()
+	return v.value, v.version
+}
+
+// Stale returns true if the next Get will recompute the value. Upstream
+// Computed and Derived values are brought up to date to find out.
+func (c *Computed[U]) Stale() bool {
+	v := c.cached.Load()
+	return v == nil || !v.fresh()
+}
+
+func (c *Computed[U]) load() *computedValue[U] {
+	if v := c.cached.Load(); v != nil && v.fresh() {
+		return v
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v := c.cached.Load(); v != nil && v.fresh() {
+		return v
+	}
+	for {
+		v := &computedValue[U]{sources: c.sources, versions: make([]uint64, len(c.sources))}
+		for i, s := range c.sources {
+			v.versions[i] = s.Version()
+		}
+		v.value = c.f()
+		if v.fresh() {
+			v.version = c.generation.Add(1)
+			c.cached.Store(v)
+			return v
+		}
+	}
+}
+
+// Derived is a Computed over a single Protected value, handing f a
+// consistent copy of it.
+type Derived[T, U any] struct {
+	c Computed[U]
+}
+
+// NewDerived returns a Derived computing f over p. f is only called when Get
+// is, and not again for a version of p it has already seen.
+func NewDerived[T, U any](p *Protected[T], f func(T) U) *Derived[T, U] {
+	return &Derived[T, U]{c: Computed[U]{f: func() U { return f(p.Load()) }, sources: []Source{p}}}
+}
+
+// Get returns f applied to the current value, recomputing it if p has been
+// written since the cached result was computed.
+func (d *Derived[T, U]) Get() U {
+	return d.c.Get()
+}
+
+// GetStamped is Get, also returning the version of p the result was computed
+// from.
+func (d *Derived[T, U]) GetStamped() (U, Stamp) {
+	v := d.c.load()
+	return v.value, Stamp(v.versions[0])
+}
+
+// Version returns the version of the result, see Computed.Version.
+func (d *Derived[T, U]) Version() uint64 {
+	return d.c.Version()
+}
+
+// GetVersioned is Get, also returning the Version of the result it returns.
+func (d *Derived[T, U]) GetVersioned() (U, uint64) {
+	return d.c.GetVersioned()
+}
+
+// Stale returns true if p has been written since the result was computed.
+func (d *Derived[T, U]) Stale() bool {
+	return d.c.Stale()
+}
+
+func (d *Derived[T, U]) dependencies() []Source {
+	return d.c.dependencies()
+}
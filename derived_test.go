@@ -0,0 +1,76 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDerivedRecomputesOnlyOnWrite(t *testing.T) {
+	p := NewProtected(pair{a: 1, b: 2})
+	calls := 0
+	sum := NewDerived(p, func(v pair) int { calls++; return v.a + v.b })
+
+	assert.Equal(t, 3, sum.Get())
+	assert.Equal(t, 3, sum.Get())
+	assert.Equal(t, 1, calls)
+
+	p.Store(pair{a: 5, b: 5})
+
+	assert.Equal(t, 10, sum.Get())
+	assert.Equal(t, 10, sum.Get())
+	assert.Equal(t, 2, calls)
+}
+
+func TestDerivedGetStampedReturnsSourceVersion(t *testing.T) {
+	p := NewProtected(pair{})
+	d := NewDerived(p, func(v pair) int { return v.a })
+	p.Update(func(v *pair) { v.a = 7 })
+
+	u, stamp := d.GetStamped()
+	_, want := p.LoadStamped()
+
+	assert.Equal(t, 7, u)
+	assert.Equal(t, want, stamp)
+}
+
+func TestComputedTracksAllSources(t *testing.T) {
+	a, b := NewProtected(1), NewProtected(10)
+	calls := 0
+	sum := NewComputed(func() int { calls++; return a.Load() + b.Load() }, a, b)
+
+	assert.Equal(t, 11, sum.Get())
+	a.Store(2)
+	assert.Equal(t, 12, sum.Get())
+	b.Store(20)
+	assert.Equal(t, 22, sum.Get())
+	assert.Equal(t, 22, sum.Get())
+	assert.Equal(t, 3, calls)
+}
+
+func TestComputedChainsThroughDerivedValues(t *testing.T) {
+	p := NewProtected(pair{a: 1, b: 2})
+	sum := NewDerived(p, func(v pair) int { return v.a + v.b })
+	doubled := NewComputed(func() int { return sum.Get() * 2 }, sum)
+	version := doubled.Version()
+
+	assert.Equal(t, 6, doubled.Get())
+	assert.Equal(t, version, doubled.Version())
+
+	p.Store(pair{a: 5, b: 5})
+
+	assert.Equal(t, 20, doubled.Get())
+	assert.True(t, doubled.Version() != version)
+}
+
+func TestDependOnRejectsCycles(t *testing.T) {
+	p := NewProtected(1)
+	a := NewComputed(func() int { return p.Load() }, p)
+	b := NewComputed(func() int { return a.Get() }, a)
+	c := NewComputed(func() int { return b.Get() }, b)
+
+	assert.Equal(t, ErrCycle, a.DependOn(c))
+	assert.Equal(t, ErrCycle, a.DependOn(a))
+	assert.Equal(t, 1, c.Get())
+
+	assert.NoError(t, c.DependOn(a))
+}
@@ -0,0 +1,316 @@
+package seqmut
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Entity identifies an entity in a World.
+type Entity uint64
+
+// World is an entity-component store. Entities with the same set of
+// component types share an archetype, which keeps one dense array per
+// component type and its own sequence lock. Systems read through Each,
+// Each2 and Get as optimistic reads validated per archetype, so any number
+// of them run in parallel, and in parallel with Set, which only locks the
+// archetype it writes to. Structural changes, Spawn and Despawn, also take a
+// world-wide mutex.
+//
+// The zero value is an empty world ready to use.
+type World struct {
+	// Serializes structural changes
+	mu   sync.Mutex
+	next Entity
+	// Append-only; published as a whole so readers can range over it
+	archetypes atomic.Pointer[[]*archetype]
+	// Guarded by mu
+	byKey map[string]*archetype
+	// Numbers each component type, to order and key archetypes by; type
+	// names are not unique across packages. Guarded by mu.
+	typeIDs   map[reflect.Type]int
+	locations SeqMap[Entity, entityLocation]
+}
+
+type entityLocation struct {
+	arch *archetype
+	row  int
+}
+
+type archetype struct {
+	rw RWMutex
+	// Immutable once the archetype is published
+	columns  map[reflect.Type]column
+	entities atomic.Pointer[[]Entity]
+}
+
+// column is a dense array of one component type. Slice headers are
+// published atomically, as in SeqSlice, so a racing reader never indexes
+// out of bounds; the archetype lock makes the contents consistent.
+type column interface {
+	push(v any)
+	swapRemove(row int)
+}
+
+type denseColumn[T any] struct {
+	data atomic.Pointer[[]T]
+}
+
+func (c *denseColumn[T]) load() []T {
+	if p := c.data.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func (c *denseColumn[T]) push(v any) {
+	data := append(c.load(), v.(T))
+	c.data.Store(&data)
+}
+
+func (c *denseColumn[T]) swapRemove(row int) {
+	data := c.load()
+	last := len(data) - 1
+	data[row] = data[last]
+	var zero T
+	data[last] = zero
+	data = data[:last]
+	c.data.Store(&data)
+}
+
+// ComponentValue is a component to Spawn an entity with, see With.
+type ComponentValue struct {
+	typ       reflect.Type
+	value     any
+	newColumn func() column
+}
+
+// With wraps v as a component for Spawn. Entities can have at most one
+// component of each type.
+func With[T any](v T) ComponentValue {
+	return ComponentValue{
+		typ:       reflect.TypeFor[T](),
+		value:     v,
+		newColumn: func() column { return &denseColumn[T]{} },
+	}
+}
+
+// Spawn creates an entity with the given components. It panics if two
+// components have the same type.
+func (w *World) Spawn(components ...ComponentValue) Entity {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sortComponents(components)
+	w.next++
+	e := w.next
+	a := w.archetype(components)
+	a.rw.Lock()
+	for _, c := range components {
+		a.columns[c.typ].push(c.value)
+	}
+	entities := append(a.loadEntities(), e)
+	a.entities.Store(&entities)
+	w.locations.Store(e, entityLocation{a, len(entities) - 1})
+	a.rw.Unlock()
+	return e
+}
+
+// Despawn removes an entity, returning false if it does not exist.
+func (w *World) Despawn(e Entity) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	loc, ok := w.locations.Load(e)
+	if !ok {
+		return false
+	}
+	a := loc.arch
+	a.rw.Lock()
+	defer a.rw.Unlock()
+	for _, c := range a.columns {
+		c.swapRemove(loc.row)
+	}
+	entities := a.loadEntities()
+	last := len(entities) - 1
+	if loc.row != last {
+		moved := entities[last]
+		entities[loc.row] = moved
+		w.locations.Store(moved, loc)
+	}
+	entities = entities[:last]
+	a.entities.Store(&entities)
+	w.locations.Delete(e)
+	return true
+}
+
+// sortComponents puts components in the canonical order of their types,
+// panicking on duplicates; must hold mu.
+func (w *World) sortComponents(components []ComponentValue) {
+	if w.typeIDs == nil {
+		w.typeIDs = map[reflect.Type]int{}
+	}
+	for _, c := range components {
+		if _, ok := w.typeIDs[c.typ]; !ok {
+			w.typeIDs[c.typ] = len(w.typeIDs)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return w.typeIDs[components[i].typ] < w.typeIDs[components[j].typ] })
+	for i := 1; i < len(components); i++ {
+		if components[i].typ == components[i-1].typ {
+			panic(fmt.Sprintf("seqmut: duplicate component type %v", components[i].typ))
+		}
+	}
+}
+
+// archetype returns the archetype for a sorted set of components, creating
+// it if need be; must hold mu.
+func (w *World) archetype(components []ComponentValue) *archetype {
+	ids := make([]string, len(components))
+	for i, c := range components {
+		ids[i] = strconv.Itoa(w.typeIDs[c.typ])
+	}
+	key := strings.Join(ids, ",")
+	if a, ok := w.byKey[key]; ok {
+		return a
+	}
+	a := &archetype{columns: map[reflect.Type]column{}}
+	for _, c := range components {
+		a.columns[c.typ] = c.newColumn()
+	}
+	if w.byKey == nil {
+		w.byKey = map[string]*archetype{}
+	}
+	w.byKey[key] = a
+	var archetypes []*archetype
+	if p := w.archetypes.Load(); p != nil {
+		archetypes = *p
+	}
+	archetypes = append(archetypes[:len(archetypes):len(archetypes)], a)
+	w.archetypes.Store(&archetypes)
+	return a
+}
+
+func (w *World) loadArchetypes() []*archetype {
+	if p := w.archetypes.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func (a *archetype) loadEntities() []Entity {
+	if p := a.entities.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func columnOf[T any](a *archetype) *denseColumn[T] {
+	c, _ := a.columns[reflect.TypeFor[T]()].(*denseColumn[T])
+	return c
+}
+
+// Get returns the entity's component of type T.
+func Get[T any](w *World, e Entity) (v T, ok bool) {
+	for {
+		loc, found := w.locations.Load(e)
+		if !found {
+			return v, false
+		}
+		c := columnOf[T](loc.arch)
+		if c == nil {
+			return v, false
+		}
+		var at Entity
+		stamp := loc.arch.rw.RStamp()
+		for {
+			entities, data := loc.arch.loadEntities(), c.load()
+			if loc.row < len(entities) && loc.row < len(data) {
+				at, v = entities[loc.row], data[loc.row]
+			}
+			if loc.arch.rw.Ok(stamp) {
+				break
+			}
+		}
+		if at == e {
+			return v, true
+		}
+		// The entity moved since its location was looked up
+	}
+}
+
+// Set replaces the entity's component of type T, returning false if the
+// entity does not exist or has no such component. Only the entity's
+// archetype is locked.
+func Set[T any](w *World, e Entity, v T) bool {
+	for {
+		loc, found := w.locations.Load(e)
+		if !found {
+			return false
+		}
+		c := columnOf[T](loc.arch)
+		if c == nil {
+			return false
+		}
+		loc.arch.rw.Lock()
+		if entities := loc.arch.loadEntities(); loc.row < len(entities) && entities[loc.row] == e {
+			c.load()[loc.row] = v
+			loc.arch.rw.Unlock()
+			return true
+		}
+		loc.arch.rw.Unlock()
+	}
+}
+
+// Each calls fn for every entity with a component of type T. Each archetype
+// is copied out as one validated optimistic read before fn sees any of it,
+// so fn only ever sees consistent components, but different archetypes may
+// be read at different times.
+func Each[T any](w *World, fn func(e Entity, v T)) {
+	var entities []Entity
+	var as []T
+	for _, a := range w.loadArchetypes() {
+		c := columnOf[T](a)
+		if c == nil {
+			continue
+		}
+		stamp := a.rw.RStamp()
+		for {
+			entities = append(entities[:0], a.loadEntities()...)
+			as = append(as[:0], c.load()...)
+			if a.rw.Ok(stamp) {
+				break
+			}
+		}
+		for i, e := range entities {
+			fn(e, as[i])
+		}
+	}
+}
+
+// Each2 is Each for entities with components of both type A and type B.
+func Each2[A, B any](w *World, fn func(e Entity, a A, b B)) {
+	var entities []Entity
+	var as []A
+	var bs []B
+	for _, a := range w.loadArchetypes() {
+		ca, cb := columnOf[A](a), columnOf[B](a)
+		if ca == nil || cb == nil {
+			continue
+		}
+		stamp := a.rw.RStamp()
+		for {
+			entities = append(entities[:0], a.loadEntities()...)
+			as = append(as[:0], ca.load()...)
+			bs = append(bs[:0], cb.load()...)
+			if a.rw.Ok(stamp) {
+				break
+			}
+		}
+		for i, e := range entities {
+			fn(e, as[i], bs[i])
+		}
+	}
+}
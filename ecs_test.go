@@ -0,0 +1,124 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type position struct{ x, y int }
+type velocity struct{ dx, dy int }
+
+// The package-level position, for tests that shadow it
+type outerPosition = position
+
+func TestWorldSpawnGetSet(t *testing.T) {
+	var w World
+	a := w.Spawn(With(position{1, 2}), With(velocity{1, 0}))
+	b := w.Spawn(With(position{5, 5}))
+
+	p, ok := Get[position](&w, a)
+	assert.True(t, ok)
+	assert.Equal(t, position{1, 2}, p)
+	_, ok = Get[velocity](&w, b)
+	assert.False(t, ok)
+
+	assert.True(t, Set(&w, b, position{6, 6}))
+	assert.False(t, Set(&w, b, velocity{}))
+	p, _ = Get[position](&w, b)
+	assert.Equal(t, position{6, 6}, p)
+}
+
+func TestWorldDespawnMovesLastRow(t *testing.T) {
+	var w World
+	a := w.Spawn(With(position{1, 1}))
+	b := w.Spawn(With(position{2, 2}))
+	c := w.Spawn(With(position{3, 3}))
+
+	assert.True(t, w.Despawn(a))
+	assert.False(t, w.Despawn(a))
+
+	_, ok := Get[position](&w, a)
+	assert.False(t, ok)
+	p, _ := Get[position](&w, c)
+	assert.Equal(t, position{3, 3}, p)
+	p, _ = Get[position](&w, b)
+	assert.Equal(t, position{2, 2}, p)
+}
+
+func TestEachVisitsMatchingArchetypes(t *testing.T) {
+	var w World
+	a := w.Spawn(With(position{1, 1}), With(velocity{1, 1}))
+	b := w.Spawn(With(position{2, 2}))
+	w.Spawn(With(velocity{3, 3}))
+
+	var withPosition []Entity
+	Each(&w, func(e Entity, p position) { withPosition = append(withPosition, e) })
+	sort.Slice(withPosition, func(i, j int) bool { return withPosition[i] < withPosition[j] })
+	assert.Equal(t, []Entity{a, b}, withPosition)
+
+	var moving []Entity
+	Each2(&w, func(e Entity, p position, v velocity) { moving = append(moving, e) })
+	assert.Equal(t, []Entity{a}, moving)
+}
+
+func TestEachSeesConsistentComponentsUnderConcurrentChanges(t *testing.T) {
+	var w World
+	var entities []Entity
+	for i := 0; i < 16; i++ {
+		entities = append(entities, w.Spawn(With(position{i, i}), With(velocity{i, i})))
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			e := entities[i%len(entities)]
+			Set(&w, e, position{i, i})
+			w.Despawn(e)
+			entities[i%len(entities)] = w.Spawn(With(position{i, i}), With(velocity{i, i}))
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		Each2(&w, func(e Entity, p position, v velocity) {
+			assert.Equal(t, p.x, p.y)
+			assert.Equal(t, v.dx, v.dy)
+		})
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestWorldDistinguishesTypesWithTheSameName(t *testing.T) {
+	// Prints as seqmut.position too, but is a different type
+	type position struct{ z int }
+	var w World
+	a := w.Spawn(With(position{1}))
+	b := w.Spawn(With(velocity{1, 1}))
+	c := w.Spawn(With(outerPosition{2, 3}))
+
+	p, ok := Get[position](&w, a)
+	assert.True(t, ok)
+	assert.Equal(t, position{1}, p)
+	_, ok = Get[position](&w, b)
+	assert.False(t, ok)
+	_, ok = Get[position](&w, c)
+	assert.False(t, ok)
+	op, ok := Get[outerPosition](&w, c)
+	assert.True(t, ok)
+	assert.Equal(t, outerPosition{2, 3}, op)
+}
+
+func TestWorldSpawnRejectsDuplicateComponents(t *testing.T) {
+	var w World
+
+	assert.Panics(t, func() { w.Spawn(With(position{1, 2}), With(position{3, 4})) })
+}
@@ -0,0 +1,28 @@
+package seqmut
+
+import "errors"
+
+// Failure modes of the bounded and conditional APIs. Errors returned by those
+// APIs may wrap these with more detail, so compare with errors.Is.
+var (
+	// A read gave up after its retry budget, see ReadBounded
+	ErrTooManyRetries = errors.New("seqmut: too many retries")
+	// The lock was not released within the allowed time, see LockTimeout
+	ErrWriterTimeout = errors.New("seqmut: timed out waiting for writer")
+	// A writer panicked, leaving the guarded state possibly inconsistent, see
+	// Protected.TryUpdate
+	ErrPoisoned = errors.New("seqmut: lock poisoned by panicking writer")
+	// The guarded state has been frozen and no longer accepts writes, see
+	// Protected.Freeze
+	ErrSealed = errors.New("seqmut: sealed")
+	// The state changed since the stamp was taken, see LockIfUnchanged
+	ErrStampMismatch = errors.New("seqmut: stamp mismatch")
+	// A single read attempt raced a writer, see CopyChecked
+	ErrTornRead = errors.New("seqmut: torn read")
+	// Adding a dependency would make a derived value depend on itself, see
+	// Computed.DependOn
+	ErrCycle = errors.New("seqmut: dependency cycle")
+	// A writer presented a fencing token older than one already seen, see
+	// CheckFence
+	ErrStaleFence = errors.New("seqmut: stale fencing token")
+)
@@ -0,0 +1,67 @@
+package seqmut
+
+import "time"
+
+// Event is an entry in an EventRing.
+type Event[T any] struct {
+	// Position of the event among all events ever appended, from 0, so gaps
+	// in a dump show how many were overwritten
+	Seq   uint64
+	Time  time.Time
+	Value T
+}
+
+// EventRing keeps the last N events, such as errors or state transitions,
+// for crash reports and /debug pages. Append is a short write; Dump returns
+// every kept event, in order, as of a single moment, however many goroutines
+// are appending.
+//
+// The zero value keeps no events; use NewEventRing.
+type EventRing[T any] struct {
+	rw     RWMutex
+	events []Event[T]
+	count  uint64
+}
+
+// NewEventRing returns a ring keeping the last size events. It panics if size
+// is not positive.
+func NewEventRing[T any](size int) *EventRing[T] {
+	if size < 1 {
+		panic("seqmut: event ring size must be positive")
+	}
+	return &EventRing[T]{events: make([]Event[T], size)}
+}
+
+// Append records v as the newest event, overwriting the oldest if the ring
+// is full.
+func (r *EventRing[T]) Append(v T) {
+	if len(r.events) == 0 {
+		return
+	}
+	now := time.Now()
+	r.rw.Lock()
+	r.events[r.count%uint64(len(r.events))] = Event[T]{Seq: r.count, Time: now, Value: v}
+	r.count++
+	r.rw.Unlock()
+}
+
+// Dump returns the kept events, oldest first.
+func (r *EventRing[T]) Dump() []Event[T] {
+	var events []Event[T]
+	size := uint64(len(r.events))
+	stamp := r.rw.RStamp()
+	for {
+		events = events[:0]
+		count := r.count
+		start := uint64(0)
+		if count > size {
+			start = count - size
+		}
+		for i := start; i < count; i++ {
+			events = append(events, r.events[i%size])
+		}
+		if r.rw.Ok(stamp) {
+			return events
+		}
+	}
+}
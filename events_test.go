@@ -0,0 +1,68 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func values(events []Event[int]) []int {
+	var vs []int
+	for _, e := range events {
+		vs = append(vs, e.Value)
+	}
+	return vs
+}
+
+func TestEventRingDumpIsOrdered(t *testing.T) {
+	r := NewEventRing[int](3)
+	assert.Empty(t, r.Dump())
+
+	r.Append(1)
+	r.Append(2)
+	assert.Equal(t, []int{1, 2}, values(r.Dump()))
+
+	for i := 3; i <= 7; i++ {
+		r.Append(i)
+	}
+	events := r.Dump()
+	assert.Equal(t, []int{5, 6, 7}, values(events))
+	assert.Equal(t, uint64(4), events[0].Seq)
+	assert.False(t, events[2].Time.Before(events[0].Time))
+}
+
+func TestEventRingDumpDuringAppends(t *testing.T) {
+	r := NewEventRing[int](8)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			r.Append(i)
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		events := r.Dump()
+		for j := 1; j < len(events); j++ {
+			assert.Equal(t, events[j-1].Seq+1, events[j].Seq)
+			assert.Equal(t, int(events[j].Seq), events[j].Value)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestEventRingWithoutCapacity(t *testing.T) {
+	assert.Panics(t, func() { NewEventRing[int](0) })
+
+	var r EventRing[int]
+	r.Append(1)
+	assert.Empty(t, r.Dump())
+}
@@ -0,0 +1,260 @@
+package seqmut
+
+import (
+	"container/heap"
+	"container/list"
+	"math/rand/v2"
+)
+
+// EvictionPolicy decides which entry a bounded SeqCache evicts. Policies are
+// only ever called from the write side, under the cache's policy lock: reads
+// are recorded in a buffer and handed over in batches, so the choice of
+// policy never brings locking back into Get.
+type EvictionPolicy[K comparable] interface {
+	// Added is called when key is inserted into the cache
+	Added(key K)
+	// Accessed is called with a batch of keys read since the previous batch.
+	// Reads are sampled under contention, and keys may since have left the
+	// cache; policies must tolerate both
+	Accessed(keys []K)
+	// Removed is called when key is deleted or expires
+	Removed(key K)
+	// Victim picks a key to evict, and stops tracking it; false if the
+	// policy is tracking no keys
+	Victim() (key K, ok bool)
+}
+
+// LRU evicts the least recently used key.
+type LRU[K comparable] struct {
+	order *list.List
+	elems map[K]*list.Element
+}
+
+func NewLRU[K comparable]() *LRU[K] {
+	return &LRU[K]{order: list.New(), elems: map[K]*list.Element{}}
+}
+
+func (p *LRU[K]) Added(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.elems[key] = p.order.PushFront(key)
+}
+
+func (p *LRU[K]) Accessed(keys []K) {
+	for _, k := range keys {
+		if e, ok := p.elems[k]; ok {
+			p.order.MoveToFront(e)
+		}
+	}
+}
+
+func (p *LRU[K]) Removed(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *LRU[K]) Victim() (key K, ok bool) {
+	e := p.order.Back()
+	if e == nil {
+		return key, false
+	}
+	key = p.order.Remove(e).(K)
+	delete(p.elems, key)
+	return key, true
+}
+
+type lfuItem[K comparable] struct {
+	key   K
+	count uint64
+	// Tie-breaker: among equally frequent keys, evict the oldest
+	seq   uint64
+	index int
+}
+
+type lfuHeap[K comparable] []*lfuItem[K]
+
+func (h lfuHeap[K]) Len() int { return len(h) }
+func (h lfuHeap[K]) Less(i, j int) bool {
+	return h[i].count < h[j].count || h[i].count == h[j].count && h[i].seq < h[j].seq
+}
+func (h lfuHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *lfuHeap[K]) Push(x any) {
+	item := x.(*lfuItem[K])
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *lfuHeap[K]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// LFU evicts the least frequently used key.
+type LFU[K comparable] struct {
+	heap  lfuHeap[K]
+	items map[K]*lfuItem[K]
+	seq   uint64
+}
+
+func NewLFU[K comparable]() *LFU[K] {
+	return &LFU[K]{items: map[K]*lfuItem[K]{}}
+}
+
+func (p *LFU[K]) Added(key K) {
+	if _, ok := p.items[key]; ok {
+		p.Accessed([]K{key})
+		return
+	}
+	p.seq++
+	item := &lfuItem[K]{key: key, count: 1, seq: p.seq}
+	p.items[key] = item
+	heap.Push(&p.heap, item)
+}
+
+func (p *LFU[K]) Accessed(keys []K) {
+	for _, k := range keys {
+		if item, ok := p.items[k]; ok {
+			item.count++
+			heap.Fix(&p.heap, item.index)
+		}
+	}
+}
+
+func (p *LFU[K]) Removed(key K) {
+	if item, ok := p.items[key]; ok {
+		heap.Remove(&p.heap, item.index)
+		delete(p.items, key)
+	}
+}
+
+func (p *LFU[K]) Victim() (key K, ok bool) {
+	if len(p.heap) == 0 {
+		return key, false
+	}
+	item := heap.Pop(&p.heap).(*lfuItem[K])
+	delete(p.items, item.key)
+	return item.key, true
+}
+
+// Random evicts a key chosen uniformly at random.
+type Random[K comparable] struct {
+	keys  []K
+	index map[K]int
+}
+
+func NewRandom[K comparable]() *Random[K] {
+	return &Random[K]{index: map[K]int{}}
+}
+
+func (p *Random[K]) Added(key K) {
+	if _, ok := p.index[key]; !ok {
+		p.index[key] = len(p.keys)
+		p.keys = append(p.keys, key)
+	}
+}
+
+func (p *Random[K]) Accessed([]K) {}
+
+func (p *Random[K]) Removed(key K) {
+	i, ok := p.index[key]
+	if !ok {
+		return
+	}
+	last := p.keys[len(p.keys)-1]
+	p.keys[i], p.index[last] = last, i
+	p.keys = p.keys[:len(p.keys)-1]
+	delete(p.index, key)
+}
+
+func (p *Random[K]) Victim() (key K, ok bool) {
+	if len(p.keys) == 0 {
+		return key, false
+	}
+	key = p.keys[rand.IntN(len(p.keys))]
+	p.Removed(key)
+	return key, true
+}
+
+// ARC is the Adaptive Replacement Cache policy (Megiddo and Modha), which
+// balances between recency and frequency by remembering recently evicted
+// keys, and shifting its preference towards whichever list those keys keep
+// returning to.
+type ARC[K comparable] struct {
+	capacity int
+	// Target size of t1
+	p int
+	// t1 holds keys seen once recently, t2 keys seen at least twice; b1 and
+	// b2 are ghosts of keys recently evicted from each
+	t1, t2, b1, b2 *LRU[K]
+}
+
+// NewARC returns an ARC policy for a cache of the given capacity.
+func NewARC[K comparable](capacity int) *ARC[K] {
+	return &ARC[K]{capacity: capacity, t1: NewLRU[K](), t2: NewLRU[K](), b1: NewLRU[K](), b2: NewLRU[K]()}
+}
+
+func (p *ARC[K]) Added(key K) {
+	switch {
+	case p.t1.contains(key) || p.t2.contains(key):
+		p.Accessed([]K{key})
+	case p.b1.contains(key):
+		// Recency would have kept it, so favour t1
+		p.p = min(p.capacity, p.p+max(p.b2.len()/p.b1.len(), 1))
+		p.b1.Removed(key)
+		p.t2.Added(key)
+	case p.b2.contains(key):
+		p.p = max(0, p.p-max(p.b1.len()/p.b2.len(), 1))
+		p.b2.Removed(key)
+		p.t2.Added(key)
+	default:
+		p.t1.Added(key)
+	}
+}
+
+func (p *ARC[K]) Accessed(keys []K) {
+	for _, k := range keys {
+		if p.t1.contains(k) {
+			p.t1.Removed(k)
+			p.t2.Added(k)
+		} else if p.t2.contains(k) {
+			p.t2.Accessed([]K{k})
+		}
+	}
+}
+
+func (p *ARC[K]) Removed(key K) {
+	p.t1.Removed(key)
+	p.t2.Removed(key)
+}
+
+func (p *ARC[K]) Victim() (key K, ok bool) {
+	if p.t1.len() > 0 && (p.t1.len() > p.p || p.t2.len() == 0) {
+		key, ok = p.t1.Victim()
+		p.b1.Added(key)
+	} else if key, ok = p.t2.Victim(); ok {
+		p.b2.Added(key)
+	}
+	for _, ghosts := range []*LRU[K]{p.b1, p.b2} {
+		for ghosts.len() > p.capacity {
+			ghosts.Victim()
+		}
+	}
+	return key, ok
+}
+
+func (p *LRU[K]) contains(key K) bool {
+	_, ok := p.elems[key]
+	return ok
+}
+
+func (p *LRU[K]) len() int {
+	return len(p.elems)
+}
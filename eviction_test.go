@@ -0,0 +1,100 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func victims[K comparable](p EvictionPolicy[K], n int) []K {
+	var keys []K
+	for i := 0; i < n; i++ {
+		k, ok := p.Victim()
+		if !ok {
+			break
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestLRU(t *testing.T) {
+	p := NewLRU[string]()
+	p.Added("a")
+	p.Added("b")
+	p.Added("c")
+	p.Accessed([]string{"a", "gone"})
+	p.Removed("c")
+
+	assert.Equal(t, []string{"b", "a"}, victims[string](p, 5))
+}
+
+func TestLFU(t *testing.T) {
+	p := NewLFU[string]()
+	p.Added("a")
+	p.Added("b")
+	p.Added("c")
+	p.Accessed([]string{"a", "a", "c"})
+
+	assert.Equal(t, []string{"b", "c", "a"}, victims[string](p, 5))
+}
+
+func TestRandom(t *testing.T) {
+	p := NewRandom[int]()
+	for i := 0; i < 10; i++ {
+		p.Added(i)
+	}
+	p.Removed(3)
+
+	seen := map[int]bool{}
+	for _, k := range victims[int](p, 20) {
+		seen[k] = true
+	}
+	assert.Len(t, seen, 9)
+	assert.False(t, seen[3])
+}
+
+func TestARCPrefersFrequentKeys(t *testing.T) {
+	p := NewARC[string](2)
+	p.Added("hot")
+	p.Accessed([]string{"hot"})
+	p.Added("once")
+
+	k, _ := p.Victim()
+	assert.Equal(t, "once", k)
+
+	// A ghost hit comes back into the frequent list
+	p.Added("once")
+	k, _ = p.Victim()
+	assert.Equal(t, "hot", k)
+}
+
+func TestBoundedSeqCache(t *testing.T) {
+	c := NewBoundedSeqCache[string, int](2, NewLRU[string]())
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Get("a")
+	c.Set("c", 3, 0)
+
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.Delete("a")
+	c.Set("d", 4, 0)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+}
+
+func TestBoundedSeqCacheReadsDoNotAllocate(t *testing.T) {
+	c := NewBoundedSeqCache[string, int](2, NewLRU[string]())
+	c.Set("a", 1, 0)
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		c.Get("a")
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
@@ -0,0 +1,49 @@
+package seqmut
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ExternalSeqLock is a sequence lock whose sequence word lives wherever the
+// caller wants it, typically embedded in a record inside a larger layout such
+// as a ring buffer or memory-mapped table. Writers within the process are
+// excluded from each other by a mutex, as with RWMutex.
+type ExternalSeqLock struct {
+	mut      sync.Mutex
+	sequence *uint64
+}
+
+// NewFromSequencePtr returns a lock using *seq as its sequence. seq must be
+// 8-byte aligned and even, meaning no write is in progress, and must not be
+// touched by anything other than the returned lock from then on.
+func NewFromSequencePtr(seq *uint64) *ExternalSeqLock {
+	if uintptr(unsafe.Pointer(seq))%8 != 0 {
+		panic("seqmut: sequence word must be 8-byte aligned")
+	}
+	if current := atomic.LoadUint64(seq); (current & 1) == 1 {
+		panic(fmt.Sprintf("seqmut: sequence word is odd (%d), a write is in progress", current))
+	}
+	return &ExternalSeqLock{sequence: seq}
+}
+
+func (e *ExternalSeqLock) RStamp() *Stamp {
+	return loadStamp(e.sequence)
+}
+
+// See RWMutex.Ok
+func (e *ExternalSeqLock) Ok(stamp *Stamp) (ok bool) {
+	return validate(e.sequence, stamp)
+}
+
+func (e *ExternalSeqLock) Lock() {
+	e.mut.Lock()
+	atomic.AddUint64(e.sequence, 1)
+}
+
+func (e *ExternalSeqLock) Unlock() {
+	atomic.AddUint64(e.sequence, 1)
+	e.mut.Unlock()
+}
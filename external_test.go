@@ -0,0 +1,38 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unsafe"
+)
+
+type record struct {
+	seq   uint64
+	price int64
+	size  int64
+}
+
+func TestExternalSeqLockUsesEmbeddedWord(t *testing.T) {
+	table := make([]record, 4)
+	lock := NewFromSequencePtr(&table[2].seq)
+
+	stamp := lock.RStamp()
+	lock.Lock()
+	assert.Equal(t, uint64(1), table[2].seq)
+	table[2].price = 10
+	lock.Unlock()
+
+	assert.Equal(t, uint64(2), table[2].seq)
+	assert.Equal(t, uint64(0), table[1].seq)
+	assert.False(t, lock.Ok(stamp))
+	assert.True(t, lock.Ok(stamp))
+}
+
+func TestNewFromSequencePtrRejectsBadWords(t *testing.T) {
+	odd := uint64(3)
+	assert.Panics(t, func() { NewFromSequencePtr(&odd) })
+
+	buf := make([]uint64, 2)
+	misaligned := (*uint64)(unsafe.Add(unsafe.Pointer(&buf[0]), 4))
+	assert.Panics(t, func() { NewFromSequencePtr(misaligned) })
+}
@@ -0,0 +1,96 @@
+package seqmut
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// FenceToken is a fencing token minted by a Fencer. Tokens only ever grow, so
+// a system accepting writes from lock holders can reject any writer presenting
+// a lower token than one it has already seen, see CheckFence.
+type FenceToken uint64
+
+// Fencer mints fencing tokens from the commits of an RWMutex. A token is
+// floor plus the number of commits since the Fencer was created, counting
+// the write it is minted in, so it grows with every write and is a plain
+// integer comparison for whoever checks it. Tokens can only be minted while
+// holding the write lock, see WriteToken, so a process that merely read the
+// state never holds one.
+//
+// The sequence itself is unsuitable as a token: it restarts from zero with
+// the process and may wrap. The Fencer counts commits relative to where the
+// sequence was when it was created, which holds across wraparound for fewer
+// than 2^63 commits, and starts above floor, so a restarted process can carry
+// on from where the previous one stopped. floor must be at least every token
+// the previous incarnation handed out, so persist tokens, or a high-water mark
+// ahead of them, before handing them out.
+type Fencer struct {
+	rw    *RWMutex
+	floor FenceToken
+	start uint64
+}
+
+// NewFencer returns a Fencer minting tokens above floor from the commits of
+// rw. It must not be called while rw is held for writing.
+func NewFencer(rw *RWMutex, floor FenceToken) *Fencer {
+	return &Fencer{rw: rw, floor: floor, start: atomic.LoadUint64(&rw.sequence) &^ 1}
+}
+
+// NewFencer returns a Fencer minting tokens from the commits to p, see
+// NewFencer.
+func (p *Protected[T]) NewFencer(floor FenceToken) *Fencer {
+	return NewFencer(&p.rw, floor)
+}
+
+// WriteToken returns the token of the write in progress, which is higher
+// than any token minted before it. It must only be called while holding the
+// write lock: the token is then tied to this write, and no other writer can
+// hold an equal one.
+func (f *Fencer) WriteToken() FenceToken {
+	seq := atomic.LoadUint64(&f.rw.sequence)
+	if seq&1 == 0 {
+		panic("seqmut: WriteToken called without holding the write lock")
+	}
+	commits := versionsSince(Stamp(f.start), Stamp(seq)+1)
+	if commits > math.MaxUint64-uint64(f.floor) {
+		panic("seqmut: fencing tokens exhausted")
+	}
+	return f.floor + FenceToken(commits)
+}
+
+// CheckFence returns nil if a writer presenting token may go ahead, given the
+// highest token seen so far, and ErrStaleFence if it has been fenced off by a
+// newer writer. Equal tokens are accepted, so one writer can make several
+// writes.
+func CheckFence(highest, token FenceToken) error {
+	if token < highest {
+		return fmt.Errorf("%w: token %d, highest %d", ErrStaleFence, token, highest)
+	}
+	return nil
+}
+
+// FenceGate tracks the highest token seen, for systems accepting writes
+// in-process. The zero value accepts any token.
+type FenceGate struct {
+	highest atomic.Uint64
+}
+
+// Admit checks token with CheckFence and, if it is accepted, raises the
+// highest token seen to it.
+func (g *FenceGate) Admit(token FenceToken) error {
+	for {
+		highest := FenceToken(g.highest.Load())
+		if err := CheckFence(highest, token); err != nil {
+			return err
+		}
+		if token == highest || g.highest.CompareAndSwap(uint64(highest), uint64(token)) {
+			return nil
+		}
+	}
+}
+
+// Highest returns the highest token admitted so far.
+func (g *FenceGate) Highest() FenceToken {
+	return FenceToken(g.highest.Load())
+}
@@ -0,0 +1,98 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"sync"
+	"testing"
+)
+
+// writeToken mints a token in a write of its own.
+func writeToken(rw *RWMutex, f *Fencer) FenceToken {
+	rw.Lock()
+	defer rw.Unlock()
+	return f.WriteToken()
+}
+
+func TestFencerTokens(t *testing.T) {
+	var rw RWMutex
+	f := NewFencer(&rw, 0)
+
+	rw.Lock()
+	first := f.WriteToken()
+	// Stable within the write
+	assert.Equal(t, first, f.WriteToken())
+	rw.Unlock()
+
+	assert.Equal(t, FenceToken(1), first)
+	assert.Equal(t, FenceToken(2), writeToken(&rw, f))
+}
+
+func TestFencerOnlyMintsForWriters(t *testing.T) {
+	var rw RWMutex
+	f := NewFencer(&rw, 0)
+
+	assert.Panics(t, func() { f.WriteToken() })
+}
+
+func TestFencerAcrossWraparound(t *testing.T) {
+	rw := NewWithSequence(math.MaxUint64 - 3)
+	f := NewFencer(rw, 0)
+
+	var last FenceToken
+	for i := 0; i < 4; i++ {
+		token := writeToken(rw, f)
+		assert.Greater(t, uint64(token), uint64(last))
+		last = token
+	}
+	assert.Equal(t, FenceToken(4), last)
+}
+
+func TestFencerRestart(t *testing.T) {
+	var before RWMutex
+	f := NewFencer(&before, 0)
+	var persisted FenceToken
+	for i := 0; i < 10; i++ {
+		persisted = writeToken(&before, f)
+	}
+
+	// A restarted process has a fresh lock, but carries on above the floor
+	var after RWMutex
+	restarted := NewFencer(&after, persisted)
+	assert.Greater(t, uint64(writeToken(&after, restarted)), uint64(persisted))
+}
+
+func TestFencerExhausted(t *testing.T) {
+	var rw RWMutex
+	f := NewFencer(&rw, math.MaxUint64-1)
+	assert.Equal(t, FenceToken(math.MaxUint64), writeToken(&rw, f))
+
+	rw.Lock()
+	assert.Panics(t, func() { f.WriteToken() })
+	rw.Unlock()
+}
+
+func TestCheckFence(t *testing.T) {
+	assert.NoError(t, CheckFence(3, 3))
+	assert.NoError(t, CheckFence(3, 4))
+	err := CheckFence(3, 2)
+	assert.True(t, errors.Is(err, ErrStaleFence), "%v", err)
+}
+
+func TestFenceGate(t *testing.T) {
+	var g FenceGate
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func(token FenceToken) {
+			defer wg.Done()
+			g.Admit(token)
+		}(FenceToken(i))
+	}
+	wg.Wait()
+
+	assert.Equal(t, FenceToken(100), g.Highest())
+	assert.NoError(t, g.Admit(100))
+	assert.True(t, errors.Is(g.Admit(99), ErrStaleFence))
+}
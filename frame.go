@@ -0,0 +1,58 @@
+package seqmut
+
+import "sync/atomic"
+
+// FrameState publishes the state of a simulation or game loop once per tick
+// to any number of readers, such as render, audio and network threads. There
+// is exactly one publisher, and it never waits for readers; readers never
+// wait for it either, and always get the latest complete frame along with its
+// frame number.
+//
+// Frames rotate through three buffers, so the publisher only ever writes a
+// buffer two frames older than the latest. A reader copying a frame only has
+// to retry if the publisher laps it, publishing two more frames in the time
+// it takes to copy one, and then picks up the newest frame instead.
+//
+// The zero value is ready to use, and holds frame 0 with the zero value of T.
+type FrameState[T any] struct {
+	slots  [3]frameSlot[T]
+	latest atomic.Uint64
+}
+
+type frameSlot[T any] struct {
+	lock  SingleWriterSeqLock
+	frame uint64
+	value T
+}
+
+// Publish makes v the latest frame, returning its frame number. It must only
+// be called from the one publishing goroutine; building with -tags
+// seqmutdebug detects concurrent publishers.
+func (f *FrameState[T]) Publish(v T) uint64 {
+	n := f.latest.Load() + 1
+	s := &f.slots[n%3]
+	s.lock.Lock()
+	s.frame = n
+	s.value = v
+	s.lock.Unlock()
+	f.latest.Store(n)
+	return n
+}
+
+// Load returns a copy of the latest frame and its frame number.
+func (f *FrameState[T]) Load() (v T, frame uint64) {
+	for {
+		s := &f.slots[f.latest.Load()%3]
+		stamp := s.lock.RStamp()
+		v, frame = s.value, s.frame
+		if s.lock.Ok(stamp) {
+			return v, frame
+		}
+	}
+}
+
+// Frame returns the number of the latest frame without copying it, so a
+// reader can skip work when nothing new has been published.
+func (f *FrameState[T]) Frame() uint64 {
+	return f.latest.Load()
+}
@@ -0,0 +1,54 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestFrameStateLoadReturnsLatestFrame(t *testing.T) {
+	var f FrameState[pair]
+	v, frame := f.Load()
+	assert.Equal(t, pair{}, v)
+	assert.Equal(t, uint64(0), frame)
+
+	for i := 1; i <= 5; i++ {
+		assert.Equal(t, uint64(i), f.Publish(pair{a: i, b: i}))
+	}
+
+	v, frame = f.Load()
+	assert.Equal(t, pair{a: 5, b: 5}, v)
+	assert.Equal(t, uint64(5), frame)
+	assert.Equal(t, uint64(5), f.Frame())
+}
+
+func TestFrameStateReadersSeeCompleteFrames(t *testing.T) {
+	var f FrameState[pair]
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last uint64
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				v, frame := f.Load()
+				assert.Equal(t, v.a, v.b)
+				assert.Equal(t, int(frame), v.a)
+				assert.True(t, frame >= last, "frame went backwards")
+				last = frame
+			}
+		}()
+	}
+
+	for i := 1; i <= 10000; i++ {
+		f.Publish(pair{a: i, b: i})
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,80 @@
+package seqmut
+
+import (
+	"runtime"
+	"sort"
+)
+
+type deferredFree struct {
+	fn func()
+	// Reclaim pass the callback was registered in
+	pass uint64
+	// Where FreeLater was called from, only recorded in debug builds
+	stack string
+}
+
+// FreeLater registers fn to run once every reader currently inside the
+// domain has exited, typically to release something a writer has just
+// unlinked. Callbacks run on whichever goroutine advances the epoch, in
+// Reclaim or an Arena's Retire, and must not block.
+func (d *EpochDomain) FreeLater(fn func()) {
+	f := deferredFree{fn: fn}
+	if debug {
+		buf := make([]byte, 4096)
+		f.stack = string(buf[:runtime.Stack(buf, false)])
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f.pass = d.passes
+	e := d.global.Load()
+	d.deferred[e%3] = append(d.deferred[e%3], f)
+}
+
+// Reclaim advances the epoch as far as readers allow, running the FreeLater
+// callbacks that become due, and returns how many it ran. Each call counts
+// as one pass for Leaks.
+func (d *EpochDomain) Reclaim() int {
+	d.mu.Lock()
+	d.passes++
+	before := d.pendingLocked()
+	d.mu.Unlock()
+	for i := 0; i < 3; i++ {
+		if _, ok := d.tryAdvance(); !ok {
+			break
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return before - d.pendingLocked()
+}
+
+func (d *EpochDomain) pendingLocked() int {
+	return len(d.deferred[0]) + len(d.deferred[1]) + len(d.deferred[2])
+}
+
+// DeferredLeak is a FreeLater callback that has not run, see Leaks.
+type DeferredLeak struct {
+	// Reclaim passes since the callback was registered
+	Passes uint64
+	// Stack of the FreeLater call; only recorded in debug builds
+	Stack string
+}
+
+// Leaks returns the FreeLater callbacks still pending after more than
+// passes calls to Reclaim, oldest first. Something pending that long usually
+// means a reader that never called Exit, holding the epoch back; in debug
+// builds the registration stacks show what is being held up.
+func (d *EpochDomain) Leaks(passes uint64) []DeferredLeak {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var leaks []DeferredLeak
+	for _, fs := range d.deferred {
+		for _, f := range fs {
+			if age := d.passes - f.pass; age > passes {
+				leaks = append(leaks, DeferredLeak{Passes: age, Stack: f.stack})
+			}
+		}
+	}
+	sort.Slice(leaks, func(i, j int) bool { return leaks[i].Passes > leaks[j].Passes })
+	return leaks
+}
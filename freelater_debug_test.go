@@ -0,0 +1,23 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestLeaksIncludeRegistrationStack(t *testing.T) {
+	var d EpochDomain
+	reader := d.Enter()
+	defer reader.Exit()
+	d.FreeLater(func() {})
+	d.Reclaim()
+
+	leaks := d.Leaks(0)
+
+	assert.Len(t, leaks, 1)
+	assert.True(t, strings.Contains(leaks[0].Stack, "TestLeaksIncludeRegistrationStack"), leaks[0].Stack)
+}
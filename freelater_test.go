@@ -0,0 +1,39 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFreeLaterRunsAfterReadersExit(t *testing.T) {
+	var d EpochDomain
+	freed := false
+
+	reader := d.Enter()
+	d.FreeLater(func() { freed = true })
+	assert.Equal(t, 0, d.Reclaim())
+	assert.False(t, freed)
+
+	reader.Exit()
+	assert.Equal(t, 1, d.Reclaim())
+	assert.True(t, freed)
+}
+
+func TestLeaksReportsStuckCallbacks(t *testing.T) {
+	var d EpochDomain
+	reader := d.Enter()
+	d.FreeLater(func() {})
+
+	for i := 0; i < 3; i++ {
+		d.Reclaim()
+	}
+
+	assert.Empty(t, d.Leaks(3))
+	leaks := d.Leaks(2)
+	assert.Len(t, leaks, 1)
+	assert.Equal(t, uint64(3), leaks[0].Passes)
+
+	reader.Exit()
+	d.Reclaim()
+	assert.Empty(t, d.Leaks(0))
+}
@@ -0,0 +1,98 @@
+package seqmut
+
+// Vec3 is a 3-component vector.
+type Vec3 [3]float32
+
+// Mat4 is a 4x4 matrix in column-major order, as graphics APIs expect.
+type Mat4 [16]float32
+
+// Identity4 is the 4x4 identity matrix.
+var Identity4 = Mat4{
+	1, 0, 0, 0,
+	0, 1, 0, 0,
+	0, 0, 1, 0,
+	0, 0, 0, 1,
+}
+
+// Transform is the position, rotation and scale of an object.
+type Transform struct {
+	Position Vec3
+	// Unit quaternion, as x, y, z, w
+	Rotation [4]float32
+	Scale    Vec3
+}
+
+// IdentityTransform leaves objects where they are.
+var IdentityTransform = Transform{Rotation: [4]float32{0, 0, 0, 1}, Scale: Vec3{1, 1, 1}}
+
+// Matrix returns the model matrix of t: scale, then rotate, then translate.
+func (t Transform) Matrix() Mat4 {
+	x, y, z, w := t.Rotation[0], t.Rotation[1], t.Rotation[2], t.Rotation[3]
+	sx, sy, sz := t.Scale[0], t.Scale[1], t.Scale[2]
+	return Mat4{
+		(1 - 2*(y*y+z*z)) * sx, 2 * (x*y + z*w) * sx, 2 * (x*z - y*w) * sx, 0,
+		2 * (x*y - z*w) * sy, (1 - 2*(x*x+z*z)) * sy, 2 * (y*z + x*w) * sy, 0,
+		2 * (x*z + y*w) * sz, 2 * (y*z - x*w) * sz, (1 - 2*(x*x+y*y)) * sz, 0,
+		t.Position[0], t.Position[1], t.Position[2], 1,
+	}
+}
+
+// SeqVec3, SeqMat4 and SeqTransform hold a value published by a single
+// writer, typically the simulation thread, for any number of readers such as
+// the renderer, physics and audio, see FrameState. A reader never sees a
+// vector or matrix with components from different writes, and neither side
+// ever waits for the other.
+//
+// The zero values are ready to use, and hold the zero value of the type;
+// note that is not the identity.
+type SeqVec3 struct {
+	f FrameState[Vec3]
+}
+
+// Store publishes v. It must only be called from the one writing goroutine.
+func (s *SeqVec3) Store(v Vec3) {
+	s.f.Publish(v)
+}
+
+// Load returns the latest published value.
+func (s *SeqVec3) Load() Vec3 {
+	v, _ := s.f.Load()
+	return v
+}
+
+// See SeqVec3.
+type SeqMat4 struct {
+	f FrameState[Mat4]
+}
+
+// Store publishes m. It must only be called from the one writing goroutine.
+func (s *SeqMat4) Store(m Mat4) {
+	s.f.Publish(m)
+}
+
+// Load returns the latest published value.
+func (s *SeqMat4) Load() Mat4 {
+	m, _ := s.f.Load()
+	return m
+}
+
+// See SeqVec3.
+type SeqTransform struct {
+	f FrameState[Transform]
+}
+
+// Store publishes t. It must only be called from the one writing goroutine.
+func (s *SeqTransform) Store(t Transform) {
+	s.f.Publish(t)
+}
+
+// Load returns the latest published value.
+func (s *SeqTransform) Load() Transform {
+	t, _ := s.f.Load()
+	return t
+}
+
+// Matrix returns the model matrix of the latest published transform.
+func (s *SeqTransform) Matrix() Mat4 {
+	return s.Load().Matrix()
+}
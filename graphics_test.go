@@ -0,0 +1,67 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestTransformMatrix(t *testing.T) {
+	assert.Equal(t, Identity4, IdentityTransform.Matrix())
+
+	tr := IdentityTransform
+	tr.Position = Vec3{1, 2, 3}
+	tr.Scale = Vec3{2, 2, 2}
+	// 90 degrees around z: sin and cos of 45 degrees are both 1/sqrt(2)
+	tr.Rotation = [4]float32{0, 0, 0.70710677, 0.70710677}
+	m := tr.Matrix()
+
+	// x maps to 2y and y to -2x, then everything shifts by the position
+	assert.InDelta(t, 0, m[0], 1e-6)
+	assert.InDelta(t, 2, m[1], 1e-6)
+	assert.InDelta(t, -2, m[4], 1e-6)
+	assert.InDelta(t, 0, m[5], 1e-6)
+	assert.Equal(t, float32(2), m[10])
+	assert.Equal(t, [4]float32{1, 2, 3, 1}, [4]float32(m[12:16]))
+}
+
+func TestSeqMat4ReadersNeverSeeTornMatrices(t *testing.T) {
+	var s SeqMat4
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			m := s.Load()
+			for _, c := range m {
+				assert.Equal(t, m[0], c)
+			}
+		}
+	}()
+
+	for i := 0; i < 10000; i++ {
+		var m Mat4
+		for j := range m {
+			m[j] = float32(i)
+		}
+		s.Store(m)
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestSeqTransformMatrix(t *testing.T) {
+	var s SeqTransform
+	s.Store(IdentityTransform)
+	var v SeqVec3
+	v.Store(Vec3{1, 2, 3})
+
+	assert.Equal(t, Identity4, s.Matrix())
+	assert.Equal(t, Vec3{1, 2, 3}, v.Load())
+}
@@ -0,0 +1,64 @@
+package seqmut
+
+import "sync/atomic"
+
+type groupWrite struct {
+	fn       func()
+	done     bool
+	panicked interface{}
+}
+
+// GroupWrite runs fn under the write lock, but instead of queueing on the
+// mutex behind other writers it queues on the lock itself: whoever is holding
+// the lock applies all queued writes before it unlocks. A burst of writers
+// thus shows up to readers as one sequence jump rather than one per writer.
+//
+// fn may run on another goroutine. If it panics, the panic is re-raised in
+// the goroutine that called GroupWrite.
+func (rw *RWMutex) GroupWrite(fn func()) {
+	w := &groupWrite{fn: fn}
+
+	rw.groupMu.Lock()
+	rw.group = append(rw.group, w)
+	atomic.AddInt32(&rw.grouped, 1)
+	rw.groupMu.Unlock()
+
+	// The mutex is released only after the holder has drained the queue, so
+	// once we have it we know whether somebody else applied our write.
+	rw.acquire()
+	if !w.done {
+		rw.enter()
+		rw.Unlock()
+	} else {
+		rw.release()
+	}
+
+	if w.panicked != nil {
+		panic(w.panicked)
+	}
+}
+
+// drainGroup applies queued group writes; must be called with the lock held.
+func (rw *RWMutex) drainGroup() {
+	for atomic.LoadInt32(&rw.grouped) > 0 {
+		rw.groupMu.Lock()
+		batch := rw.group
+		rw.group = nil
+		atomic.AddInt32(&rw.grouped, -int32(len(batch)))
+		rw.groupMu.Unlock()
+
+		for _, w := range batch {
+			w.apply()
+		}
+	}
+}
+
+func (w *groupWrite) apply() {
+	defer func() {
+		if r := recover(); r != nil {
+			w.panicked = r
+		}
+		w.done = true
+	}()
+	w.fn()
+}
@@ -0,0 +1,86 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupWriteWithoutContention(t *testing.T) {
+	var rw RWMutex
+	v := 0
+
+	rw.GroupWrite(func() { v = 1 })
+
+	assert.Equal(t, 1, v)
+	assert.Equal(t, uint64(2), rw.sequence)
+}
+
+func TestGroupWritesAreAppliedByLockHolder(t *testing.T) {
+	var rw RWMutex
+	v := 0
+
+	rw.Lock()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rw.GroupWrite(func() { v++ })
+		}()
+	}
+	// Give the group writers a chance to queue up behind us
+	for {
+		rw.groupMu.Lock()
+		queued := len(rw.group)
+		rw.groupMu.Unlock()
+		if queued == 10 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	rw.Unlock()
+	wg.Wait()
+
+	assert.Equal(t, 10, v)
+	// One version jump for the holder, covering all ten queued writes
+	assert.Equal(t, uint64(2), rw.sequence)
+}
+
+func TestGroupWriteRepanicsInSubmitter(t *testing.T) {
+	var rw RWMutex
+
+	assert.Panics(t, func() { rw.GroupWrite(func() { panic("boom") }) })
+
+	stamp := rw.RStamp()
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestGroupWriteHammer(t *testing.T) {
+	n := 1000
+	if testing.Short() {
+		n = 50
+	}
+	var rw RWMutex
+	v := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%3 == 0 {
+				rw.Lock()
+				v++
+				rw.Unlock()
+			} else {
+				rw.GroupWrite(func() { v++ })
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, n, v)
+	assert.Equal(t, uint64(0), rw.sequence&1)
+}
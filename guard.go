@@ -0,0 +1,30 @@
+package seqmut
+
+// WriteGuard represents a held write lock, for use with defer:
+//
+//	g := rw.LockGuard()
+//	defer g.Unlock()
+//
+// which cannot leave the sequence odd forever by missing an Unlock on an
+// early return. In debug builds, unlocking a guard twice panics.
+type WriteGuard struct {
+	rw       *RWMutex
+	released bool
+}
+
+// LockGuard acquires the write lock and returns a guard that releases it.
+func (rw *RWMutex) LockGuard() *WriteGuard {
+	rw.Lock()
+	return &WriteGuard{rw: rw}
+}
+
+// Unlock releases the write lock.
+func (g *WriteGuard) Unlock() {
+	if debug {
+		if g.released {
+			panic("seqmut: Unlock of a WriteGuard that was already unlocked")
+		}
+		g.released = true
+	}
+	g.rw.Unlock()
+}
@@ -0,0 +1,17 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteGuardDoubleUnlockPanics(t *testing.T) {
+	var rw RWMutex
+	g := rw.LockGuard()
+	g.Unlock()
+
+	assert.Panics(t, g.Unlock)
+}
@@ -0,0 +1,27 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteGuardUnlocksOnEarlyReturn(t *testing.T) {
+	var rw RWMutex
+	write := func(fail bool) error {
+		g := rw.LockGuard()
+		defer g.Unlock()
+		if fail {
+			return errors.New("early")
+		}
+		return nil
+	}
+
+	stamp := rw.RStamp()
+	assert.Error(t, write(true))
+	assert.NoError(t, write(false))
+
+	assert.False(t, rw.Ok(stamp))
+	assert.True(t, rw.Ok(stamp))
+	assert.Equal(t, Stamp(4), rw.plain(stamp))
+}
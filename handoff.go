@@ -0,0 +1,41 @@
+package seqmut
+
+import "sync/atomic"
+
+const (
+	tokenHeld uint32 = iota
+	tokenAdopted
+	tokenReleased
+)
+
+// WriteToken represents ownership of the write lock. Unlike the lock itself,
+// it can be handed to another goroutine, which calls Adopt to take over the
+// write critical section without releasing and re-acquiring the lock, so no
+// other writer can interleave.
+type WriteToken struct {
+	rw    *RWMutex
+	state uint32
+}
+
+// LockToken acquires the write lock and returns a token for it.
+func (rw *RWMutex) LockToken() *WriteToken {
+	rw.Lock()
+	return &WriteToken{rw: rw}
+}
+
+// Adopt takes over ownership of the write lock from t, returning the new
+// owner's token. t can no longer be used; the lock stays held throughout.
+func (t *WriteToken) Adopt() *WriteToken {
+	if !atomic.CompareAndSwapUint32(&t.state, tokenHeld, tokenAdopted) {
+		panic("seqmut: Adopt of a WriteToken that is no longer held")
+	}
+	return &WriteToken{rw: t.rw}
+}
+
+// Unlock releases the write lock.
+func (t *WriteToken) Unlock() {
+	if !atomic.CompareAndSwapUint32(&t.state, tokenHeld, tokenReleased) {
+		panic("seqmut: Unlock of a WriteToken that is no longer held")
+	}
+	t.rw.Unlock()
+}
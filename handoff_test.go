@@ -0,0 +1,41 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteTokenHandoffKeepsLockHeld(t *testing.T) {
+	var rw RWMutex
+	tokens := make(chan *WriteToken)
+	done := make(chan bool)
+
+	go func() {
+		tok := (<-tokens).Adopt()
+		stamp := rw.RStamp()
+		assert.False(t, rw.Ok(stamp))
+		tok.Unlock()
+		done <- true
+	}()
+
+	tok := rw.LockToken()
+	seq := rw.sequence
+	tokens <- tok
+	<-done
+
+	// The whole handoff happened inside a single write critical section
+	assert.Equal(t, seq+1, rw.sequence)
+}
+
+func TestStaleWriteTokenCannotUnlock(t *testing.T) {
+	var rw RWMutex
+
+	tok := rw.LockToken()
+	adopted := tok.Adopt()
+
+	assert.Panics(t, func() { tok.Unlock() })
+	assert.Panics(t, func() { tok.Adopt() })
+
+	adopted.Unlock()
+	assert.Panics(t, func() { adopted.Unlock() })
+}
@@ -0,0 +1,66 @@
+package seqmut
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// HybridRWMutex is a sync.RWMutex with a sequence on top. Classic RLock
+// readers and optimistic RStamp/Ok readers can be mixed over the same data,
+// so call sites can be migrated to optimistic reads one at a time.
+//
+// Writers exclude both kinds of reader: RLock blocks while a writer is active,
+// and optimistic reads overlapping a writer are retried.
+//
+// HybridRWMutex has the full sync.RWMutex method set, so it can replace a
+// sync.RWMutex without touching call sites, making it easy to A/B-test the
+// two under a real workload.
+type HybridRWMutex struct {
+	rw       sync.RWMutex
+	sequence uint64
+}
+
+func (h *HybridRWMutex) RStamp() *Stamp {
+	return loadStamp(&h.sequence)
+}
+
+// See RWMutex.Ok
+func (h *HybridRWMutex) Ok(stamp *Stamp) (ok bool) {
+	return validate(&h.sequence, stamp)
+}
+
+func (h *HybridRWMutex) RLock() {
+	h.rw.RLock()
+}
+
+func (h *HybridRWMutex) RUnlock() {
+	h.rw.RUnlock()
+}
+
+func (h *HybridRWMutex) Lock() {
+	h.rw.Lock()
+	atomic.AddUint64(&h.sequence, 1)
+}
+
+func (h *HybridRWMutex) Unlock() {
+	atomic.AddUint64(&h.sequence, 1)
+	h.rw.Unlock()
+}
+
+func (h *HybridRWMutex) TryRLock() bool {
+	return h.rw.TryRLock()
+}
+
+func (h *HybridRWMutex) TryLock() bool {
+	if !h.rw.TryLock() {
+		return false
+	}
+	atomic.AddUint64(&h.sequence, 1)
+	return true
+}
+
+// RLocker returns a sync.Locker that takes the classic read lock, like
+// sync.RWMutex.RLocker.
+func (h *HybridRWMutex) RLocker() sync.Locker {
+	return h.rw.RLocker()
+}
@@ -0,0 +1,125 @@
+package seqmut
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Method set of sync.RWMutex, which HybridRWMutex must keep matching
+type syncRWMutex interface {
+	RLock()
+	RUnlock()
+	TryRLock() bool
+	Lock()
+	Unlock()
+	TryLock() bool
+	RLocker() sync.Locker
+}
+
+var (
+	_ syncRWMutex = (*sync.RWMutex)(nil)
+	_ syncRWMutex = (*HybridRWMutex)(nil)
+)
+
+func TestHybridTryLock(t *testing.T) {
+	var h HybridRWMutex
+
+	stamp := h.RStamp()
+	assert.True(t, h.TryLock())
+	assert.False(t, h.TryLock())
+	assert.False(t, h.TryRLock())
+	assert.False(t, h.Ok(stamp))
+	h.Unlock()
+
+	assert.True(t, h.TryRLock())
+	assert.False(t, h.TryLock())
+	h.RUnlock()
+}
+
+func TestHybridRLocker(t *testing.T) {
+	var h HybridRWMutex
+	rl := h.RLocker()
+
+	rl.Lock()
+	assert.False(t, h.TryLock())
+	assert.True(t, h.TryRLock())
+	h.RUnlock()
+	rl.Unlock()
+
+	assert.True(t, h.TryLock())
+	h.Unlock()
+}
+
+func TestHybridOptimisticReadersSeeWriters(t *testing.T) {
+	var h HybridRWMutex
+
+	stamp := h.RStamp()
+	h.Lock()
+	assert.False(t, h.Ok(stamp))
+	h.Unlock()
+
+	assert.False(t, h.Ok(stamp))
+	assert.True(t, h.Ok(stamp))
+}
+
+func TestHybridClassicReadersDoNotInvalidateStamps(t *testing.T) {
+	var h HybridRWMutex
+
+	stamp := h.RStamp()
+	h.RLock()
+	h.RUnlock()
+
+	assert.True(t, h.Ok(stamp))
+}
+
+func TestHybridMixedReadersHammer(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 5
+	}
+	var h HybridRWMutex
+	var activity int32
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			h.Lock()
+			atomic.AddInt32(&activity, 1)
+			atomic.AddInt32(&activity, -1)
+			h.Unlock()
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func(classic bool) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				var read int32
+				if classic {
+					h.RLock()
+					read = atomic.LoadInt32(&activity)
+					h.RUnlock()
+				} else {
+					stamp := h.RStamp()
+					for {
+						read = atomic.LoadInt32(&activity)
+						if h.Ok(stamp) {
+							break
+						}
+					}
+				}
+				if read != 0 {
+					panic(fmt.Sprintf("wlock(%d)", read))
+				}
+			}
+		}(r%2 == 0)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,46 @@
+package seqmut
+
+import "sync/atomic"
+
+// Intent is an announced intention to Lock, see SetIntent.
+type Intent struct {
+	rw      *RWMutex
+	retired atomic.Bool
+}
+
+// SetIntent announces that the caller is about to Lock. Readers in the middle
+// of an expensive critical section can poll Doomed and give up early, rather
+// than finish a read that Ok is going to reject anyway.
+//
+// Intent is only a hint, and Ok remains the sole arbiter of whether a read
+// succeeded. The caller must either take the lock through Intent.Lock, or
+// withdraw the intent with Cancel if it backs off; until then every read is
+// doomed.
+func (rw *RWMutex) SetIntent() *Intent {
+	atomic.AddInt32(&rw.intent, 1)
+	return &Intent{rw: rw}
+}
+
+// Lock takes the write lock that was announced. The intent is retired once
+// the lock is held, from when the odd sequence dooms readers on its own.
+func (i *Intent) Lock() {
+	i.rw.Lock()
+	i.Cancel()
+}
+
+// Cancel withdraws the intent. It does nothing if the intent was already
+// retired, by Lock or an earlier Cancel.
+func (i *Intent) Cancel() {
+	if i.retired.CompareAndSwap(false, true) {
+		atomic.AddInt32(&i.rw.intent, -1)
+	}
+}
+
+// Doomed returns true if a writer has announced intent to write, if stamp was
+// taken while a writer held the lock, or if the sequence has moved on since
+// stamp was taken. A doomed read should be abandoned and retried; a read that
+// is not doomed must still be checked with Ok.
+func (rw *RWMutex) Doomed(stamp *Stamp) bool {
+	return atomic.LoadInt32(&rw.intent) > 0 || (*stamp&1) == 1 ||
+		Stamp(atomic.LoadUint64(&rw.sequence)) != rw.checkOwner(*stamp)
+}
@@ -0,0 +1,91 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNotDoomedWithoutWriters(t *testing.T) {
+	var rw RWMutex
+
+	stamp := rw.RStamp()
+
+	assert.False(t, rw.Doomed(stamp))
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestDoomedOnceIntentAnnounced(t *testing.T) {
+	var rw RWMutex
+
+	stamp := rw.RStamp()
+	rw.SetIntent()
+
+	assert.True(t, rw.Doomed(stamp))
+}
+
+func TestDoomedWhileWriterHoldsLockAfterIntent(t *testing.T) {
+	var rw RWMutex
+
+	stamp := rw.RStamp()
+	rw.SetIntent().Lock()
+
+	assert.True(t, rw.Doomed(stamp))
+	assert.False(t, rw.Ok(stamp))
+
+	rw.Unlock()
+
+	// The retry picks up a fresh stamp, and with the intent consumed nothing dooms it
+	assert.False(t, rw.Ok(stamp))
+	assert.False(t, rw.Doomed(stamp))
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestLockWithoutIntentDoesNotGoNegative(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+	rw.Unlock()
+	stamp := rw.RStamp()
+
+	assert.False(t, rw.Doomed(stamp))
+}
+
+func TestDoomedWhenStampTakenDuringWrite(t *testing.T) {
+	var rw RWMutex
+
+	rw.SetIntent().Lock()
+	// The intent has been consumed, but the stamp is odd and can never validate
+	stamp := rw.RStamp()
+
+	assert.True(t, rw.Doomed(stamp))
+	rw.Unlock()
+}
+
+func TestCancelledIntentNoLongerDooms(t *testing.T) {
+	var rw RWMutex
+
+	stamp := rw.RStamp()
+	intent := rw.SetIntent()
+	assert.True(t, rw.Doomed(stamp))
+
+	intent.Cancel()
+	intent.Cancel()
+
+	assert.False(t, rw.Doomed(stamp))
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestOtherWritersDoNotConsumeIntent(t *testing.T) {
+	var rw RWMutex
+
+	intent := rw.SetIntent()
+	rw.Lock()
+	rw.Unlock()
+	stamp := rw.RStamp()
+
+	assert.True(t, rw.Doomed(stamp))
+	intent.Lock()
+	rw.Unlock()
+	stamp = rw.RStamp()
+	assert.False(t, rw.Doomed(stamp))
+}
@@ -0,0 +1,66 @@
+package seqmut
+
+import (
+	"strings"
+	"sync"
+)
+
+// Symbol identifies a string interned by an Interner. Symbols are dense,
+// starting at zero, in order of first interning.
+type Symbol uint32
+
+// Interner is a symbol table. Looking up a string that is already interned,
+// by far the most common case in compilers, parsers and telemetry pipelines,
+// is an optimistic read; only interning a new string takes a lock.
+//
+// The zero value is an empty table ready to use.
+type Interner struct {
+	// Serializes first-time interning, so each string gets exactly one symbol
+	mu      sync.Mutex
+	symbols SeqMap[string, Symbol]
+	names   SeqSlice[string]
+}
+
+// Intern returns the symbol for s, assigning the next one if s is new.
+func (in *Interner) Intern(s string) Symbol {
+	if sym, ok := in.symbols.Load(s); ok {
+		return sym
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if sym, ok := in.symbols.Load(s); ok {
+		return sym
+	}
+	// Don't pin whatever larger buffer s may be a slice of
+	s = strings.Clone(s)
+	sym := Symbol(in.names.Len())
+	in.names.Append(s)
+	in.symbols.Store(s, sym)
+	return sym
+}
+
+// Lookup returns the symbol for s, without interning it.
+func (in *Interner) Lookup(s string) (Symbol, bool) {
+	return in.symbols.Load(s)
+}
+
+// Name returns the string interned as sym. It panics if sym did not come
+// from this Interner.
+func (in *Interner) Name(sym Symbol) string {
+	s, ok := in.names.Get(int(sym))
+	if !ok {
+		panic("seqmut: unknown symbol")
+	}
+	return s
+}
+
+// Canonical returns the interned copy of s, interning it if need be, so that
+// equal strings share one allocation.
+func (in *Interner) Canonical(s string) string {
+	return in.Name(in.Intern(s))
+}
+
+// Len returns the number of interned strings.
+func (in *Interner) Len() int {
+	return in.names.Len()
+}
@@ -0,0 +1,59 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner(t *testing.T) {
+	var in Interner
+
+	a := in.Intern("alpha")
+	b := in.Intern("beta")
+
+	assert.Equal(t, Symbol(0), a)
+	assert.Equal(t, Symbol(1), b)
+	assert.Equal(t, a, in.Intern("alpha"))
+	assert.Equal(t, "beta", in.Name(b))
+	_, ok := in.Lookup("gamma")
+	assert.False(t, ok)
+	assert.Equal(t, 2, in.Len())
+	assert.Panics(t, func() { in.Name(7) })
+}
+
+func TestInternerCanonicalSharesStorage(t *testing.T) {
+	var in Interner
+	buf := []byte("key")
+
+	first := in.Canonical(string(buf))
+	second := in.Canonical(string(buf))
+
+	assert.True(t, unsafe.StringData(first) == unsafe.StringData(second))
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	var in Interner
+	var wg sync.WaitGroup
+	syms := make([][]Symbol, 4)
+	for w := range syms {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				syms[w] = append(syms[w], in.Intern(strconv.Itoa(i)))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 500, in.Len())
+	for w := 1; w < len(syms); w++ {
+		assert.Equal(t, syms[0], syms[w])
+	}
+	for i, sym := range syms[0] {
+		assert.Equal(t, strconv.Itoa(i), in.Name(sym))
+	}
+}
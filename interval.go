@@ -0,0 +1,175 @@
+package seqmut
+
+// IntervalEntry is an interval stored in an IntervalTree. Intervals are
+// half-open: they contain Start but not End.
+type IntervalEntry[V any] struct {
+	Start, End int64
+	Value      V
+}
+
+type intervalNode[V any] struct {
+	lock  NodeLock
+	start int64
+	end   int64
+	value V
+	// Upper bound on the end of any interval in this subtree. It only ever
+	// grows, which at worst makes queries look at a subtree needlessly.
+	maxEnd  int64
+	deleted bool
+	// Set once and never changed, so a child pointer read under a valid
+	// version stays valid
+	left, right *intervalNode[V]
+}
+
+// IntervalTree maps intervals to values, answering stabbing and overlap
+// queries with optimistic lock coupling (see NodeLock): queries lock nothing,
+// and restart if a node they read from changed underneath them. Inserts
+// write-lock their way down the tree hand over hand; deletes only lock the
+// node being removed.
+//
+// Nodes are never unlinked: a deleted interval stays behind as a tombstone
+// until the same interval is inserted again, and the tree is not rebalanced.
+// It suits query-heavy, mutation-light workloads such as schedules, rate
+// windows and address ranges, where it is the query side that must scale.
+//
+// The zero value is an empty tree ready to use.
+type IntervalTree[V any] struct {
+	// Guards root
+	rootLock NodeLock
+	root     *intervalNode[V]
+}
+
+// Insert adds the interval [start, end) with value v. It panics if end is not
+// after start.
+func (t *IntervalTree[V]) Insert(start, end int64, v V) {
+	if end <= start {
+		panic("seqmut: empty interval")
+	}
+	n := &intervalNode[V]{start: start, end: end, maxEnd: end, value: v}
+	// Nodes are never obsolete, so the OrRestart write locks cannot fail
+	t.rootLock.WriteLockOrRestart()
+	if t.root == nil {
+		t.root = n
+		t.rootLock.WriteUnlock()
+		return
+	}
+	cur := t.root
+	cur.lock.WriteLockOrRestart()
+	t.rootLock.WriteUnlock()
+	for {
+		if end > cur.maxEnd {
+			cur.maxEnd = end
+		}
+		if cur.deleted && cur.start == start && cur.end == end {
+			cur.value, cur.deleted = v, false
+			cur.lock.WriteUnlock()
+			return
+		}
+		child := &cur.right
+		if start < cur.start {
+			child = &cur.left
+		}
+		if *child == nil {
+			*child = n
+			cur.lock.WriteUnlock()
+			return
+		}
+		next := *child
+		next.lock.WriteLockOrRestart()
+		cur.lock.WriteUnlock()
+		cur = next
+	}
+}
+
+// Delete removes one interval [start, end), returning false if there was
+// none.
+func (t *IntervalTree[V]) Delete(start, end int64) bool {
+restart:
+	for {
+		rv, _ := t.rootLock.ReadLockOrRestart()
+		cur := t.root
+		if !t.rootLock.CheckOrRestart(rv) {
+			continue
+		}
+		for cur != nil {
+			v, ok := cur.lock.ReadLockOrRestart()
+			if !ok {
+				continue restart
+			}
+			match := !cur.deleted && cur.start == start && cur.end == end
+			next := cur.right
+			if start < cur.start {
+				next = cur.left
+			}
+			if !cur.lock.CheckOrRestart(v) {
+				continue restart
+			}
+			if match {
+				if !cur.lock.UpgradeToWriteLockOrRestart(v) {
+					continue restart
+				}
+				var zero V
+				cur.value, cur.deleted = zero, true
+				cur.lock.WriteUnlock()
+				return true
+			}
+			cur = next
+		}
+		return false
+	}
+}
+
+// Stab returns every interval containing point.
+func (t *IntervalTree[V]) Stab(point int64) []IntervalEntry[V] {
+	return t.Overlapping(point, point+1)
+}
+
+// Overlapping returns every interval that overlaps [lo, hi), each as it was
+// at some point during the query.
+func (t *IntervalTree[V]) Overlapping(lo, hi int64) []IntervalEntry[V] {
+	var out []IntervalEntry[V]
+	var stack []*intervalNode[V]
+restart:
+	for {
+		out, stack = out[:0], stack[:0]
+		rv, _ := t.rootLock.ReadLockOrRestart()
+		root := t.root
+		if !t.rootLock.CheckOrRestart(rv) {
+			continue
+		}
+		if root != nil {
+			stack = append(stack, root)
+		}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			v, ok := n.lock.ReadLockOrRestart()
+			if !ok {
+				continue restart
+			}
+			start, end, maxEnd, deleted, value := n.start, n.end, n.maxEnd, n.deleted, n.value
+			left, right := n.left, n.right
+			if !n.lock.CheckOrRestart(v) {
+				continue restart
+			}
+
+			if maxEnd <= lo {
+				// Nothing in this subtree reaches the query
+				continue
+			}
+			if left != nil {
+				stack = append(stack, left)
+			}
+			if start < hi {
+				if !deleted && end > lo {
+					out = append(out, IntervalEntry[V]{start, end, value})
+				}
+				if right != nil {
+					stack = append(stack, right)
+				}
+			}
+		}
+		return out
+	}
+}
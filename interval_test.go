@@ -0,0 +1,67 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func sortedValues(entries []IntervalEntry[string]) []string {
+	var vs []string
+	for _, e := range entries {
+		vs = append(vs, e.Value)
+	}
+	sort.Strings(vs)
+	return vs
+}
+
+func TestIntervalTreeQueries(t *testing.T) {
+	var tree IntervalTree[string]
+	tree.Insert(0, 10, "a")
+	tree.Insert(5, 15, "b")
+	tree.Insert(20, 30, "c")
+	tree.Insert(-5, 1, "d")
+
+	assert.Equal(t, []string{"a", "b"}, sortedValues(tree.Stab(7)))
+	assert.Equal(t, []string{"a", "d"}, sortedValues(tree.Stab(0)))
+	assert.Empty(t, tree.Stab(15))
+	assert.Equal(t, []string{"b", "c"}, sortedValues(tree.Overlapping(12, 21)))
+}
+
+func TestIntervalTreeDelete(t *testing.T) {
+	var tree IntervalTree[string]
+	tree.Insert(0, 10, "a")
+	tree.Insert(0, 5, "b")
+
+	assert.True(t, tree.Delete(0, 10))
+	assert.False(t, tree.Delete(0, 10))
+	assert.Equal(t, []string{"b"}, sortedValues(tree.Stab(1)))
+
+	tree.Insert(0, 10, "c")
+	assert.Equal(t, []string{"b", "c"}, sortedValues(tree.Stab(1)))
+}
+
+func TestIntervalTreeConcurrentInsertAndQuery(t *testing.T) {
+	var tree IntervalTree[int]
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				start := int64((i*7 + w*13) % 1000)
+				tree.Insert(start, start+10, w)
+			}
+		}(w)
+	}
+	for i := 0; i < 100; i++ {
+		for _, e := range tree.Stab(int64(i * 10)) {
+			assert.True(t, e.Start <= int64(i*10) && e.End > int64(i*10))
+		}
+	}
+	wg.Wait()
+
+	total := len(tree.Overlapping(-100, 2000))
+	assert.Equal(t, 1000, total)
+}
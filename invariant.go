@@ -0,0 +1,57 @@
+package seqmut
+
+import (
+	"fmt"
+	"strings"
+)
+
+type invariant[T any] struct {
+	name  string
+	check func(v *T) error
+}
+
+// AddInvariant registers a check that must hold for every committed value.
+// In builds with -tags seqmutdebug, every write runs the registered checks
+// just before it commits, and panics with a before/after diff of the value
+// if one fails, so a corrupting write is caught where it happens rather than
+// at some distant read. In regular builds invariants are not checked.
+//
+// The write is committed before panicking, as any other would be, so the
+// broken value is visible to readers from that point on.
+func (p *Protected[T]) AddInvariant(name string, check func(v *T) error) {
+	p.rw.acquire()
+	p.invariants = append(p.invariants, invariant[T]{name: name, check: check})
+	p.rw.release()
+}
+
+// brokenInvariant runs the invariants against the value being committed,
+// returning a description of the first that fails, or "" if they all hold.
+func (p *Protected[T]) brokenInvariant() string {
+	for _, inv := range p.invariants {
+		if err := inv.check(&p.value); err != nil {
+			return fmt.Sprintf("seqmut: invariant %q broken by write: %v\n%s", inv.name, err, diff(*p.before, p.value))
+		}
+	}
+	return ""
+}
+
+// diff renders the space-separated tokens that differ between the %+v forms
+// of two values.
+func diff(before, after interface{}) string {
+	b := strings.Split(fmt.Sprintf("%+v", before), " ")
+	a := strings.Split(fmt.Sprintf("%+v", after), " ")
+	var out strings.Builder
+	for i := 0; i < len(b) || i < len(a); i++ {
+		var bs, as string
+		if i < len(b) {
+			bs = b[i]
+		}
+		if i < len(a) {
+			as = a[i]
+		}
+		if bs != as {
+			fmt.Fprintf(&out, "- %s\n+ %s\n", bs, as)
+		}
+	}
+	return out.String()
+}
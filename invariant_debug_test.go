@@ -0,0 +1,41 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBrokenInvariantPanicsAtCommit(t *testing.T) {
+	var p Protected[pair]
+	p.AddInvariant("balanced", balanced)
+
+	p.Update(func(v *pair) {
+		v.a++
+		v.b++
+	})
+	assert.PanicsWithValue(t, "seqmut: invariant \"balanced\" broken by write: a != b\n- {a:1\n+ {a:2\n",
+		func() { p.Update(func(v *pair) { v.a++ }) })
+
+	// The lock was released on the way out
+	p.Store(pair{})
+	assert.Equal(t, pair{}, p.Load())
+}
+
+func TestBrokenInvariantStillCommits(t *testing.T) {
+	var p Protected[pair]
+	p.RetainSnapshots()
+	var bus VersionBus
+	observed := bus.Topic("pairs").Observe()
+	p.BumpOnCommit(bus.Topic("pairs"))
+	p.AddInvariant("balanced", balanced)
+
+	assert.Panics(t, func() { p.Update(func(v *pair) { v.a++ }) })
+
+	// The broken write was published like any other before the panic
+	stale, _ := p.ReadStale()
+	assert.Equal(t, pair{a: 1}, stale)
+	assert.False(t, observed.Current())
+}
@@ -0,0 +1,30 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func balanced(v *pair) error {
+	if v.a != v.b {
+		return errors.New("a != b")
+	}
+	return nil
+}
+
+func TestInvariantsAreOnlyCheckedInDebugBuilds(t *testing.T) {
+	if debug {
+		t.Skip("regular builds only")
+	}
+	var p Protected[pair]
+	p.AddInvariant("balanced", balanced)
+
+	assert.NotPanics(t, func() { p.Update(func(v *pair) { v.a++ }) })
+}
+
+func TestDiffShowsChangedFields(t *testing.T) {
+	d := diff(pair{1, 2}, pair{1, 3})
+
+	assert.Equal(t, "- b:2}\n+ b:3}\n", d)
+}
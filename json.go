@@ -0,0 +1,82 @@
+package seqmut
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Values up to this size are copied out optimistically and encoded from the copy.
+const jsonCopyLimit = 64 << 10
+
+// MarshalJSON encodes a consistent snapshot of the guarded value, so that
+// debug endpoints and audit logs never emit torn state.
+//
+// How the snapshot is taken depends on T. Small values without pointers,
+// slices, maps or interfaces are copied out optimistically and encoded from
+// the copy. Larger such values without strings are encoded in place, retrying
+// until the encoding validates. Anything else cannot be walked safely against
+// a racing writer, so writers are held off while it is encoded.
+func (p *Protected[T]) MarshalJSON() ([]byte, error) {
+	var zero T
+	typ := reflect.TypeOf(&zero).Elem()
+	kind := indirectionOf(typ)
+
+	switch {
+	case kind <= indirectStrings && typ.Size() <= jsonCopyLimit:
+		return json.Marshal(p.Load())
+	case kind == indirectNone:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		stamp := p.rw.RStamp()
+		for {
+			buf.Reset()
+			err := enc.Encode(&p.value)
+			if p.rw.Ok(stamp) {
+				return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), err
+			}
+		}
+	default:
+		p.rw.acquire()
+		defer p.rw.release()
+		return json.Marshal(&p.value)
+	}
+}
+
+type indirection int
+
+const (
+	// Nothing but numbers, bools and aggregates of them
+	indirectNone indirection = iota
+	// Strings, whose contents are immutable, but whose headers can tear
+	indirectStrings
+	// Pointers, slices, maps, interfaces and the like
+	indirectAny
+)
+
+var indirections sync.Map // reflect.Type -> indirection
+
+// indirectionOf classifies what a value of type typ may refer to outside itself.
+func indirectionOf(typ reflect.Type) indirection {
+	if k, ok := indirections.Load(typ); ok {
+		return k.(indirection)
+	}
+	var k indirection
+	switch typ.Kind() {
+	case reflect.String:
+		k = indirectStrings
+	case reflect.Array:
+		k = indirectionOf(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if fk := indirectionOf(typ.Field(i).Type); fk > k {
+				k = fk
+			}
+		}
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		k = indirectAny
+	}
+	indirections.Store(typ, k)
+	return k
+}
@@ -0,0 +1,63 @@
+package seqmut
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type point struct {
+	X, Y int
+}
+
+type label struct {
+	Name string
+	At   point
+}
+
+type bigPlain struct {
+	Values [jsonCopyLimit/8 + 1]int64
+}
+
+func TestMarshalJSONOfSmallValue(t *testing.T) {
+	p := NewProtected(label{Name: "origin", At: point{1, 2}})
+
+	data, err := json.Marshal(p)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Name":"origin","At":{"X":1,"Y":2}}`, string(data))
+}
+
+func TestMarshalJSONOfLargePlainValue(t *testing.T) {
+	var p Protected[bigPlain]
+	p.Update(func(v *bigPlain) { v.Values[3] = 7 })
+
+	data, err := p.MarshalJSON()
+
+	assert.NoError(t, err)
+	var out bigPlain
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, int64(7), out.Values[3])
+}
+
+func TestMarshalJSONOfValueWithMaps(t *testing.T) {
+	p := NewProtected(map[string][]int{"a": {1, 2}})
+
+	data, err := p.MarshalJSON()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"a":[1,2]}`, string(data))
+}
+
+func TestIndirectionOf(t *testing.T) {
+	assert.Equal(t, indirectNone, indirectionOf(typeOf[point]()))
+	assert.Equal(t, indirectNone, indirectionOf(typeOf[[4]point]()))
+	assert.Equal(t, indirectStrings, indirectionOf(typeOf[label]()))
+	assert.Equal(t, indirectAny, indirectionOf(typeOf[[]int]()))
+	assert.Equal(t, indirectAny, indirectionOf(typeOf[outer]()))
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
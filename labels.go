@@ -0,0 +1,32 @@
+package seqmut
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// ProfileLabel is the pprof label ReadLabeled tags retrying readers with.
+const ProfileLabel = "seqmut.lock"
+
+// ReadLabeled is Read, tagging the goroutine with the pprof label
+// ProfileLabel=name while it is retrying, so CPU profiles attribute time spent
+// spinning on a contended lock to that lock. ctx carries the goroutine's
+// current labels, which are restored when ReadLabeled returns.
+//
+// Reads that succeed first time never touch the labels, so the cost is only
+// paid under contention.
+func ReadLabeled(ctx context.Context, l OptimisticLocker, name string, fn func()) {
+	stamp := l.RStamp()
+	fn()
+	if l.Ok(stamp) {
+		return
+	}
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels(ProfileLabel, name)))
+	defer pprof.SetGoroutineLabels(ctx)
+	for {
+		fn()
+		if l.Ok(stamp) {
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package seqmut
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func goroutineProfile() string {
+	var buf bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	return buf.String()
+}
+
+func TestReadLabeledTagsRetries(t *testing.T) {
+	var rw RWMutex
+	label := `"seqmut.lock":"orders"`
+	var profiles []string
+
+	ReadLabeled(context.Background(), &rw, "orders", func() {
+		profiles = append(profiles, goroutineProfile())
+		if len(profiles) == 1 {
+			rw.Lock()
+			rw.Unlock()
+		}
+	})
+
+	assert.Len(t, profiles, 2)
+	assert.False(t, strings.Contains(profiles[0], label))
+	assert.True(t, strings.Contains(profiles[1], label))
+	assert.False(t, strings.Contains(goroutineProfile(), label))
+}
@@ -0,0 +1,50 @@
+package seqmut
+
+import "sync/atomic"
+
+// LazyValue is a value initialized on first use. Initialization runs once,
+// under the write lock; every access after that is a validated optimistic
+// read, so unlike a sync.Once guarding a struct, the value can still be
+// updated later and readers always see all of its fields consistently.
+type LazyValue[T any] struct {
+	p    Protected[T]
+	init func() T
+	done atomic.Bool
+}
+
+// NewLazyValue returns a LazyValue that will be initialized by init.
+func NewLazyValue[T any](init func() T) *LazyValue[T] {
+	return &LazyValue[T]{init: init}
+}
+
+func (l *LazyValue[T]) ensure() {
+	if l.done.Load() {
+		return
+	}
+	l.p.lock()
+	defer l.p.unlock()
+	if !l.done.Load() {
+		l.p.value = l.init()
+		l.done.Store(true)
+	}
+}
+
+// Get returns a copy of the value, initializing it first if need be.
+func (l *LazyValue[T]) Get() T {
+	l.ensure()
+	return l.p.Load()
+}
+
+// Read runs fn as an optimistic critical section over the value, see
+// Protected.Read, initializing it first if need be.
+func (l *LazyValue[T]) Read(fn func(v *T)) {
+	l.ensure()
+	l.p.Read(fn)
+}
+
+// Update runs fn with exclusive access to the value, initializing it first if
+// need be.
+func (l *LazyValue[T]) Update(fn func(v *T)) {
+	l.ensure()
+	l.p.Update(fn)
+}
@@ -0,0 +1,33 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestLazyValueInitializesOnce(t *testing.T) {
+	calls := 0
+	l := NewLazyValue(func() pair { calls++; return pair{a: 1, b: 1} })
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, pair{a: 1, b: 1}, l.Get())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestLazyValueUpdateAfterInit(t *testing.T) {
+	l := NewLazyValue(func() pair { return pair{a: 1, b: 1} })
+
+	l.Update(func(p *pair) { p.a, p.b = 2, 2 })
+
+	var sum int
+	l.Read(func(p *pair) { sum = p.a + p.b })
+	assert.Equal(t, 4, sum)
+}
@@ -0,0 +1,15 @@
+package seqmut
+
+import "sync"
+
+// NewWithLocker returns an RWMutex that uses l, rather than its own mutex, for
+// mutual exclusion between writers. This lets existing code that already
+// guards a struct with a mutex add optimistic readers on top: keep using l as
+// before in places that don't touch reader-visible state, and switch the
+// writers that do over to the returned RWMutex.
+//
+// Writers that take l directly do not bump the sequence, so optimistic
+// readers will not notice their writes.
+func NewWithLocker(l sync.Locker) *RWMutex {
+	return &RWMutex{locker: l}
+}
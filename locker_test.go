@@ -0,0 +1,52 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewWithLockerUsesSuppliedLocker(t *testing.T) {
+	var mu sync.Mutex
+	rw := NewWithLocker(&mu)
+
+	rw.Lock()
+	assert.False(t, mu.TryLock())
+	rw.Unlock()
+
+	assert.True(t, mu.TryLock())
+	mu.Unlock()
+}
+
+func TestNewWithLockerExcludesDirectLockHolders(t *testing.T) {
+	var mu sync.Mutex
+	rw := NewWithLocker(&mu)
+
+	mu.Lock()
+	acquired := make(chan bool)
+	go func() {
+		rw.Lock()
+		acquired <- true
+		rw.Unlock()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired write lock while locker was held")
+	case <-time.After(10 * time.Millisecond):
+	}
+	mu.Unlock()
+	<-acquired
+}
+
+func TestNewWithLockerBumpsSequence(t *testing.T) {
+	rw := NewWithLocker(&sync.Mutex{})
+
+	stamp := rw.RStamp()
+	rw.Lock()
+	rw.Unlock()
+
+	assert.False(t, rw.Ok(stamp))
+	assert.True(t, rw.Ok(stamp))
+}
@@ -0,0 +1,212 @@
+package seqmut
+
+import (
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MetricKind is the kind of a column in a MetricsVector.
+type MetricKind uint8
+
+const (
+	// Counter columns are summed with Add
+	Counter MetricKind = iota
+	// Gauge columns are replaced with Set
+	Gauge
+)
+
+// MetricColumn describes one column of a MetricsVector.
+type MetricColumn struct {
+	Name string
+	Kind MetricKind
+}
+
+// MetricsSample is one row of a MetricsVector scrape.
+type MetricsSample struct {
+	Labels []string
+	Values []float64
+}
+
+// metricsShards spreads updates over this many copies of the table.
+const metricsShards = 16
+
+// scrapeAttempts is how many optimistic attempts Scrape makes before locking
+// the table instead.
+const scrapeAttempts = 8
+
+// MetricsVector is a table of metrics with a fixed set of columns and a row
+// per label set, such as per endpoint and status code. Updates are a short
+// write to one of several shards of the table, picked at random to spread
+// contention; Scrape reads every shard as of a single moment, so the whole
+// table is consistent, with no skew between, say, a request counter and an
+// error counter scraped a few microseconds apart.
+type MetricsVector struct {
+	labelNames []string
+	columns    []MetricColumn
+
+	// Serializes adding rows
+	mu     sync.Mutex
+	rows   SeqMap[string, int]
+	labels SeqSlice[[]string]
+	shards [metricsShards]metricsShard
+	locks  []OptimisticLocker
+}
+
+type metricsShard struct {
+	rw RWMutex
+	// Row-major; replaced as a whole when rows are added
+	values atomic.Pointer[[]float64]
+	// Keep shards on separate cache lines
+	_ [64]byte
+}
+
+// MetricsRow is a handle on one row of a MetricsVector. Handlers should look
+// their rows up once and keep them.
+type MetricsRow struct {
+	m   *MetricsVector
+	row int
+}
+
+// NewMetricsVector returns an empty vector with the given label names and
+// columns.
+func NewMetricsVector(labelNames []string, columns ...MetricColumn) *MetricsVector {
+	m := &MetricsVector{labelNames: labelNames, columns: columns}
+	for i := range m.shards {
+		m.locks = append(m.locks, &m.shards[i].rw)
+	}
+	return m
+}
+
+// Columns returns the columns of the vector.
+func (m *MetricsVector) Columns() []MetricColumn {
+	return m.columns
+}
+
+// Row returns the row for a set of label values, one per label name, adding
+// it if need be.
+func (m *MetricsVector) Row(labels ...string) MetricsRow {
+	if len(labels) != len(m.labelNames) {
+		panic("seqmut: wrong number of labels for metrics vector")
+	}
+	key := strings.Join(labels, "\xff")
+	if row, ok := m.rows.Load(key); ok {
+		return MetricsRow{m, row}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if row, ok := m.rows.Load(key); ok {
+		return MetricsRow{m, row}
+	}
+	row := m.labels.Len()
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.rw.Lock()
+		values := append(s.load(), make([]float64, len(m.columns))...)
+		s.values.Store(&values)
+		s.rw.Unlock()
+	}
+	m.labels.Append(append([]string(nil), labels...))
+	m.rows.Store(key, row)
+	return MetricsRow{m, row}
+}
+
+func (s *metricsShard) load() []float64 {
+	if p := s.values.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Add adds delta to a counter column. It panics if the column is a gauge.
+func (r MetricsRow) Add(column int, delta float64) {
+	r.m.checkKind(column, Counter)
+	s := &r.m.shards[rand.N(metricsShards)]
+	s.rw.Lock()
+	s.load()[r.row*len(r.m.columns)+column] += delta
+	s.rw.Unlock()
+}
+
+// Set sets a gauge column. Gauges always live in the first shard, and are
+// zero in the others, so summing the shards still gives their value. It
+// panics if the column is a counter.
+func (r MetricsRow) Set(column int, v float64) {
+	r.m.checkKind(column, Gauge)
+	s := &r.m.shards[0]
+	s.rw.Lock()
+	s.load()[r.row*len(r.m.columns)+column] = v
+	s.rw.Unlock()
+}
+
+// Update calls fn with the row's values, one per column, and applies
+// whatever it changes in a single write, so a scrape sees all of the changes
+// or none of them, as with a request counted along with its error. Gauges in
+// values hold their value, and fn may set them; counters hold only part of
+// their total, so fn must only add to them. values must not be used once fn
+// returns.
+func (r MetricsRow) Update(fn func(values []float64)) {
+	s := &r.m.shards[0]
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	n := len(r.m.columns)
+	fn(s.load()[r.row*n : (r.row+1)*n : (r.row+1)*n])
+}
+
+func (m *MetricsVector) checkKind(column int, kind MetricKind) {
+	if m.columns[column].Kind != kind {
+		panic("seqmut: metric column " + m.columns[column].Name + " is not a " + kind.String())
+	}
+}
+
+// String returns "counter" or "gauge".
+func (k MetricKind) String() string {
+	if k == Gauge {
+		return "gauge"
+	}
+	return "counter"
+}
+
+// Scrape returns every row, summed over the shards, as of a single moment.
+// If updates keep it from getting a consistent optimistic read it briefly
+// locks the table instead.
+func (m *MetricsVector) Scrape() []MetricsSample {
+	var labels [][]string
+	for _, l := range m.labels.All() {
+		labels = append(labels, l)
+	}
+	// Rows added after this point are left out; they are only ever appended
+	n := len(labels) * len(m.columns)
+	sums := make([]float64, n)
+	sum := func() {
+		clear(sums)
+		for i := range m.shards {
+			values := m.shards[i].load()
+			for j := range min(n, len(values)) {
+				sums[j] += values[j]
+			}
+		}
+	}
+
+	stamps := BeginAll(m.locks...)
+	ok := false
+	for attempt := 0; attempt < scrapeAttempts && !ok; attempt++ {
+		sum()
+		ok = OkAll(stamps)
+	}
+	if !ok {
+		for i := range m.shards {
+			m.shards[i].rw.Lock()
+		}
+		sum()
+		for i := range m.shards {
+			m.shards[i].rw.Unlock()
+		}
+	}
+
+	samples := make([]MetricsSample, len(labels))
+	for i, l := range labels {
+		samples[i] = MetricsSample{Labels: l, Values: sums[i*len(m.columns) : (i+1)*len(m.columns)]}
+	}
+	return samples
+}
@@ -0,0 +1,98 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+const (
+	colRequests = iota
+	colErrors
+	colInFlight
+)
+
+func newEndpointMetrics() *MetricsVector {
+	return NewMetricsVector([]string{"endpoint"},
+		MetricColumn{"requests", Counter},
+		MetricColumn{"errors", Counter},
+		MetricColumn{"in_flight", Gauge})
+}
+
+func TestMetricsVectorScrape(t *testing.T) {
+	m := newEndpointMetrics()
+	users := m.Row("/users")
+	orders := m.Row("/orders")
+	for i := 0; i < 100; i++ {
+		users.Add(colRequests, 1)
+	}
+	users.Add(colErrors, 3)
+	users.Set(colInFlight, 7)
+	users.Set(colInFlight, 5)
+	orders.Add(colRequests, 2)
+
+	assert.Equal(t, users, m.Row("/users"))
+	assert.Equal(t, []MetricsSample{
+		{Labels: []string{"/users"}, Values: []float64{100, 3, 5}},
+		{Labels: []string{"/orders"}, Values: []float64{2, 0, 0}},
+	}, m.Scrape())
+	assert.Panics(t, func() { m.Row("/users", "GET") })
+}
+
+func TestMetricsRowChecksColumnKinds(t *testing.T) {
+	m := newEndpointMetrics()
+	row := m.Row("/users")
+
+	assert.Panics(t, func() { row.Set(colRequests, 1) })
+	assert.Panics(t, func() { row.Add(colInFlight, 1) })
+	assert.Equal(t, []float64{0, 0, 0}, m.Scrape()[0].Values)
+}
+
+func TestMetricsRowUpdate(t *testing.T) {
+	m := newEndpointMetrics()
+	users := m.Row("/users")
+	m.Row("/orders")
+	users.Add(colRequests, 5)
+	users.Update(func(values []float64) {
+		assert.Len(t, values, 3)
+		values[colRequests]++
+		values[colErrors]++
+		values[colInFlight] = 2
+	})
+
+	assert.Equal(t, []MetricsSample{
+		{Labels: []string{"/users"}, Values: []float64{6, 1, 2}},
+		{Labels: []string{"/orders"}, Values: []float64{0, 0, 0}},
+	}, m.Scrape())
+}
+
+func TestMetricsVectorScrapeIsConsistent(t *testing.T) {
+	m := newEndpointMetrics()
+	row := m.Row("/users")
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Every other request fails; both counters move in one write
+			row.Update(func(values []float64) {
+				values[colRequests] += 2
+				values[colErrors]++
+			})
+			row.Add(colRequests, 0)
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		values := m.Scrape()[0].Values
+		assert.Equal(t, values[colRequests], 2*values[colErrors])
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,58 @@
+package seqmut
+
+// MultiStamp holds stamps for an optimistic read spanning several locks, see
+// BeginAll.
+type MultiStamp struct {
+	locks  []OptimisticLocker
+	stamps []*Stamp
+}
+
+// BeginAll begins an optimistic read over several locks at once. Use it in
+// place of separate RStamp calls, and OkAll in place of separate Ok calls:
+//
+//	stamps := seqmut.BeginAll(&a, &b)
+//	for {
+//		x, y = readA(), readB()
+//		if seqmut.OkAll(stamps) {
+//			break
+//		}
+//	}
+//
+// The combined read is only consistent if every stamp is taken before any of
+// the guarded data is read, and every stamp is validated after all of it has
+// been; then there was a moment, between the last stamp and the first
+// validation, at which no lock had a write in progress or committed one.
+// Taking and validating stamps lock by lock, interleaved with the reads,
+// gives no such moment.
+func BeginAll(locks ...OptimisticLocker) *MultiStamp {
+	m := &MultiStamp{locks: locks, stamps: make([]*Stamp, len(locks))}
+	for i, l := range locks {
+		m.stamps[i] = l.RStamp()
+	}
+	return m
+}
+
+// OkAll validates a read begun with BeginAll, returning false if it raced a
+// writer on any of the locks. Every stamp is validated even once one has
+// failed, so all of them are refreshed for the retry.
+func OkAll(m *MultiStamp) bool {
+	ok := true
+	for i, l := range m.locks {
+		if !l.Ok(m.stamps[i]) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// ReadAll runs fn as an optimistic critical section over all of locks,
+// retrying until it completes without a racing writer on any of them.
+func ReadAll(fn func(), locks ...OptimisticLocker) {
+	stamps := BeginAll(locks...)
+	for {
+		fn()
+		if OkAll(stamps) {
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestOkAllFailsIfAnyLockWasWritten(t *testing.T) {
+	var a, b RWMutex
+	stamps := BeginAll(&a, &b)
+	assert.True(t, OkAll(stamps))
+
+	b.Lock()
+	b.Unlock()
+
+	assert.False(t, OkAll(stamps))
+	// Both stamps were refreshed
+	assert.True(t, OkAll(stamps))
+}
+
+func TestOkAllFailsWhileAWriterIsActive(t *testing.T) {
+	var a, b RWMutex
+	a.Lock()
+	stamps := BeginAll(&a, &b)
+
+	assert.False(t, OkAll(stamps))
+	a.Unlock()
+	assert.False(t, OkAll(stamps))
+	assert.True(t, OkAll(stamps))
+}
+
+func TestReadAllSeesConsistentTransfers(t *testing.T) {
+	var a, b RWMutex
+	balanceA, balanceB := 100, 0
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			// Move one unit from a to b, holding both locks
+			a.Lock()
+			b.Lock()
+			if balanceA == 0 {
+				balanceA, balanceB = balanceB, 0
+			} else {
+				balanceA--
+				balanceB++
+			}
+			b.Unlock()
+			a.Unlock()
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		var total int
+		ReadAll(func() { total = balanceA + balanceB }, &a, &b)
+		assert.Equal(t, 100, total)
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,26 @@
+package seqmut
+
+// LockNonInvalidating acquires the write lock for mutual exclusion with other
+// writers, but does not bump the sequence, so optimistic readers do not
+// notice. It is ONLY for mutations that no reader can observe, such as
+// maintaining writer-private free lists alongside the guarded state. Any
+// reader-visible change made under it can tear readers.
+//
+// Release with UnlockNonInvalidating. Building with -tags seqmutdebug makes
+// mismatched lock and unlock calls panic.
+func (rw *RWMutex) LockNonInvalidating() {
+	rw.acquire()
+	if debug {
+		rw.nonInvalidating = true
+	}
+}
+
+func (rw *RWMutex) UnlockNonInvalidating() {
+	if debug {
+		if !rw.nonInvalidating {
+			panic("seqmut: UnlockNonInvalidating without LockNonInvalidating")
+		}
+		rw.nonInvalidating = false
+	}
+	rw.release()
+}
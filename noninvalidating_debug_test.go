@@ -0,0 +1,21 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDebugDetectsMismatchedNonInvalidatingUnlock(t *testing.T) {
+	var rw RWMutex
+
+	rw.LockNonInvalidating()
+	assert.Panics(t, func() { rw.Unlock() })
+	rw.UnlockNonInvalidating()
+
+	rw.Lock()
+	assert.Panics(t, func() { rw.UnlockNonInvalidating() })
+	rw.Unlock()
+}
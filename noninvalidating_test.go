@@ -0,0 +1,38 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNonInvalidatingWriteDoesNotDisturbReaders(t *testing.T) {
+	var rw RWMutex
+
+	stamp := rw.RStamp()
+	rw.LockNonInvalidating()
+	assert.True(t, rw.Ok(stamp))
+	rw.UnlockNonInvalidating()
+
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestNonInvalidatingLockExcludesWriters(t *testing.T) {
+	var rw RWMutex
+
+	rw.LockNonInvalidating()
+	acquired := make(chan bool)
+	go func() {
+		rw.Lock()
+		acquired <- true
+		rw.Unlock()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("writer acquired lock while non-invalidating lock was held")
+	case <-time.After(10 * time.Millisecond):
+	}
+	rw.UnlockNonInvalidating()
+	<-acquired
+}
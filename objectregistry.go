@@ -0,0 +1,90 @@
+package seqmut
+
+import (
+	"sort"
+	"sync"
+)
+
+// RegistryEvent describes a change to an ObjectRegistry.
+type RegistryEvent[T any] struct {
+	Name  string
+	Value T
+	// True if Name was deregistered; Value is then the value it had
+	Removed bool
+}
+
+// ObjectRegistry is a registry of named objects, such as plugins or handlers:
+// the string to T map that long-lived services otherwise build by hand around
+// a sync.RWMutex. Get is an optimistic read; Register and Deregister take a
+// lock and notify subscribers.
+//
+// The zero value is an empty registry ready to use.
+type ObjectRegistry[T any] struct {
+	// Serializes changes, and is handed over to notifyMu before subscribers
+	// run, so they see changes in the order they happened
+	mu       sync.Mutex
+	notifyMu sync.Mutex
+	objects  SeqMap[string, T]
+
+	subscribers subscribers[RegistryEvent[T]]
+}
+
+// Get returns the object registered under name.
+func (r *ObjectRegistry[T]) Get(name string) (T, bool) {
+	return r.objects.Load(name)
+}
+
+// Register registers v under name, replacing any earlier registration, and
+// notifies subscribers.
+func (r *ObjectRegistry[T]) Register(name string, v T) {
+	r.mu.Lock()
+	r.objects.Store(name, v)
+	r.notify(RegistryEvent[T]{Name: name, Value: v})
+}
+
+// Deregister removes name, returning false if it was not registered.
+func (r *ObjectRegistry[T]) Deregister(name string) bool {
+	r.mu.Lock()
+	v, ok := r.objects.Load(name)
+	if !ok {
+		r.mu.Unlock()
+		return false
+	}
+	r.objects.Delete(name)
+	r.notify(RegistryEvent[T]{Name: name, Value: v, Removed: true})
+	return true
+}
+
+// List returns every registration as of a single point in time, ordered by
+// name.
+func (r *ObjectRegistry[T]) List() []RegistryEvent[T] {
+	var list []RegistryEvent[T]
+	for name, v := range r.objects.All() {
+		list = append(list, RegistryEvent[T]{Name: name, Value: v})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Subscribe calls fn, in order, for every subsequent change. fn runs
+// synchronously in the goroutine making the change, with the notification of
+// further changes held off, so it must not change the registry itself; it
+// may cancel subscriptions, including its own. The returned function ends the
+// subscription.
+func (r *ObjectRegistry[T]) Subscribe(fn func(RegistryEvent[T])) (cancel func()) {
+	return r.subscribers.add(fn)
+}
+
+// notify passes e to the subscribers. It must be called holding mu, which it
+// releases once notifications are queued up behind notifyMu, so subscribers
+// run without any lock they could need themselves.
+func (r *ObjectRegistry[T]) notify(e RegistryEvent[T]) {
+	subscribers := r.subscribers.list()
+
+	r.notifyMu.Lock()
+	defer r.notifyMu.Unlock()
+	r.mu.Unlock()
+	for _, sub := range subscribers {
+		sub.fn(e)
+	}
+}
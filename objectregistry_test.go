@@ -0,0 +1,54 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestObjectRegistry(t *testing.T) {
+	var r ObjectRegistry[int]
+	r.Register("b", 2)
+	r.Register("a", 1)
+	r.Register("b", 3)
+
+	v, ok := r.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, []RegistryEvent[int]{{Name: "a", Value: 1}, {Name: "b", Value: 3}}, r.List())
+
+	assert.True(t, r.Deregister("a"))
+	assert.False(t, r.Deregister("a"))
+	_, ok = r.Get("a")
+	assert.False(t, ok)
+}
+
+func TestObjectRegistrySubscribe(t *testing.T) {
+	var r ObjectRegistry[string]
+	var events []RegistryEvent[string]
+	cancel := r.Subscribe(func(e RegistryEvent[string]) { events = append(events, e) })
+
+	r.Register("json", "codec")
+	r.Deregister("json")
+	cancel()
+	r.Register("gob", "codec")
+
+	assert.Equal(t, []RegistryEvent[string]{
+		{Name: "json", Value: "codec"},
+		{Name: "json", Value: "codec", Removed: true},
+	}, events)
+}
+
+func TestObjectRegistrySubscriberCancelsItself(t *testing.T) {
+	var r ObjectRegistry[int]
+	calls := 0
+	var cancel func()
+	cancel = r.Subscribe(func(RegistryEvent[int]) {
+		calls++
+		cancel()
+	})
+
+	r.Register("a", 1)
+	r.Register("b", 2)
+
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,86 @@
+package seqmut
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+const (
+	nodeObsolete uint64 = 1 << iota
+	nodeLocked
+)
+
+// NodeLock is a per-node version lock for building data structures with
+// optimistic lock coupling (Leis et al., "The ART of Practical
+// Synchronization"). Readers descend a structure recording each node's
+// version and validating it once they have read what they need from the node,
+// restarting the whole operation from the root whenever a check fails.
+// Writers lock only the nodes they modify.
+//
+// The OrRestart methods return ok == false when the caller must restart. The
+// zero value is an unlocked, live node.
+type NodeLock struct {
+	version uint64
+}
+
+// ReadLockOrRestart returns the version to validate reads of the node
+// against, waiting out a writer if one holds the node. It fails if the node
+// has been made obsolete.
+func (n *NodeLock) ReadLockOrRestart() (version uint64, ok bool) {
+	for {
+		version = atomic.LoadUint64(&n.version)
+		if version&nodeLocked == 0 {
+			break
+		}
+		runtime.Gosched()
+	}
+	return version, version&nodeObsolete == 0
+}
+
+// CheckOrRestart validates everything read from the node since
+// ReadLockOrRestart returned version. Check before acting on anything read,
+// in particular before following a pointer to a child.
+func (n *NodeLock) CheckOrRestart(version uint64) (ok bool) {
+	return atomic.LoadUint64(&n.version) == version
+}
+
+// ReadUnlockOrRestart is CheckOrRestart for the final check on a node.
+func (n *NodeLock) ReadUnlockOrRestart(version uint64) (ok bool) {
+	return n.CheckOrRestart(version)
+}
+
+// UpgradeToWriteLockOrRestart turns an optimistic read of the node into a
+// write lock, failing if the node changed since version was read.
+func (n *NodeLock) UpgradeToWriteLockOrRestart(version uint64) (ok bool) {
+	return atomic.CompareAndSwapUint64(&n.version, version, version|nodeLocked)
+}
+
+// WriteLockOrRestart write-locks the node, failing only if it is obsolete.
+func (n *NodeLock) WriteLockOrRestart() (ok bool) {
+	for {
+		version, ok := n.ReadLockOrRestart()
+		if !ok {
+			return false
+		}
+		if n.UpgradeToWriteLockOrRestart(version) {
+			return true
+		}
+	}
+}
+
+// WriteUnlock releases the write lock, giving the node a new version.
+func (n *NodeLock) WriteUnlock() {
+	atomic.AddUint64(&n.version, nodeLocked)
+}
+
+// WriteUnlockObsolete releases the write lock and marks the node obsolete, for
+// nodes that have been unlinked from the structure. Readers still holding a
+// reference will restart instead of trusting its contents.
+func (n *NodeLock) WriteUnlockObsolete() {
+	atomic.AddUint64(&n.version, nodeLocked|nodeObsolete)
+}
+
+// IsObsolete returns true if the node was released with WriteUnlockObsolete.
+func (n *NodeLock) IsObsolete() bool {
+	return atomic.LoadUint64(&n.version)&nodeObsolete != 0
+}
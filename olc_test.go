@@ -0,0 +1,101 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNodeLockOptimisticRead(t *testing.T) {
+	var n NodeLock
+
+	v, ok := n.ReadLockOrRestart()
+	assert.True(t, ok)
+	assert.True(t, n.CheckOrRestart(v))
+	assert.True(t, n.ReadUnlockOrRestart(v))
+}
+
+func TestNodeLockWriteInvalidatesReaders(t *testing.T) {
+	var n NodeLock
+
+	v, _ := n.ReadLockOrRestart()
+	assert.True(t, n.WriteLockOrRestart())
+	assert.False(t, n.CheckOrRestart(v))
+	n.WriteUnlock()
+
+	assert.False(t, n.CheckOrRestart(v))
+	v2, ok := n.ReadLockOrRestart()
+	assert.True(t, ok)
+	assert.NotEqual(t, v, v2)
+}
+
+func TestNodeLockUpgrade(t *testing.T) {
+	var n NodeLock
+
+	v, _ := n.ReadLockOrRestart()
+	assert.True(t, n.UpgradeToWriteLockOrRestart(v))
+	// A second upgrade from the same version loses
+	assert.False(t, n.UpgradeToWriteLockOrRestart(v))
+	n.WriteUnlock()
+
+	assert.False(t, n.UpgradeToWriteLockOrRestart(v))
+}
+
+func TestNodeLockObsolete(t *testing.T) {
+	var n NodeLock
+
+	v, _ := n.ReadLockOrRestart()
+	n.WriteLockOrRestart()
+	n.WriteUnlockObsolete()
+
+	assert.True(t, n.IsObsolete())
+	assert.False(t, n.CheckOrRestart(v))
+	_, ok := n.ReadLockOrRestart()
+	assert.False(t, ok)
+	assert.False(t, n.WriteLockOrRestart())
+}
+
+// Optimistic lock coupling over a tiny linked list: the textbook pattern
+type olcNode struct {
+	lock  NodeLock
+	value int
+	next  *olcNode
+}
+
+func olcSum(head *olcNode) int {
+restart:
+	sum := 0
+	node := head
+	v, ok := node.lock.ReadLockOrRestart()
+	if !ok {
+		goto restart
+	}
+	for node != nil {
+		sum += node.value
+		next := node.next
+		if next == nil {
+			if !node.lock.ReadUnlockOrRestart(v) {
+				goto restart
+			}
+			break
+		}
+		nv, ok := next.lock.ReadLockOrRestart()
+		if !ok || !node.lock.ReadUnlockOrRestart(v) {
+			goto restart
+		}
+		node, v = next, nv
+	}
+	return sum
+}
+
+func TestNodeLockCoupledTraversal(t *testing.T) {
+	list := &olcNode{value: 1, next: &olcNode{value: 2, next: &olcNode{value: 3}}}
+
+	assert.Equal(t, 6, olcSum(list))
+
+	second := list.next
+	second.lock.WriteLockOrRestart()
+	second.value = 20
+	second.lock.WriteUnlock()
+
+	assert.Equal(t, 24, olcSum(list))
+}
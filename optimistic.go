@@ -0,0 +1,55 @@
+package seqmut
+
+import "sync"
+
+// OptimisticLocker is implemented by every lock in this package that supports
+// optimistic reads. Library code can accept an OptimisticLocker to let callers
+// pick the implementation, or substitute one in tests and benchmarks.
+type OptimisticLocker interface {
+	sync.Locker
+	// RStamp begins an optimistic read
+	RStamp() *Stamp
+	// Ok validates an optimistic read, updating the stamp for a retry if it failed
+	Ok(stamp *Stamp) bool
+}
+
+var (
+	_ OptimisticLocker = (*RWMutex)(nil)
+	_ OptimisticLocker = (*SingleWriterSeqLock)(nil)
+	_ OptimisticLocker = (*HybridRWMutex)(nil)
+	_ OptimisticLocker = (*ExternalSeqLock)(nil)
+)
+
+// Read runs fn as an optimistic critical section under l, retrying until it
+// completes without a racing writer.
+func Read(l OptimisticLocker, fn func()) {
+	stamp := l.RStamp()
+	for {
+		fn()
+		if l.Ok(stamp) {
+			return
+		}
+	}
+}
+
+// OptimisticThenLocked is the read-mostly get-or-create idiom: readFn runs as
+// an optimistic critical section, see Read, and if it reports found, that is
+// the answer. Otherwise readFn runs again under the write lock, since another
+// writer may have got there in between, and only if it still misses does
+// writeFn run, under the same lock. It returns the final result of readFn.
+//
+// readFn must not act on what it reads until it has returned true, or until
+// OptimisticThenLocked has returned.
+func OptimisticThenLocked(l OptimisticLocker, readFn func() (found bool), writeFn func()) (found bool) {
+	Read(l, func() { found = readFn() })
+	if found {
+		return true
+	}
+	l.Lock()
+	defer l.Unlock()
+	if readFn() {
+		return true
+	}
+	writeFn()
+	return false
+}
@@ -0,0 +1,72 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOptimisticLockerImplementations(t *testing.T) {
+	impls := map[string]OptimisticLocker{
+		"RWMutex":             &RWMutex{},
+		"SingleWriterSeqLock": &SingleWriterSeqLock{},
+		"HybridRWMutex":       &HybridRWMutex{},
+	}
+
+	for name, l := range impls {
+		t.Run(name, func(t *testing.T) {
+			v := 0
+			l.Lock()
+			v = 1
+			l.Unlock()
+
+			var read int
+			Read(l, func() { read = v })
+			assert.Equal(t, 1, read)
+
+			stamp := l.RStamp()
+			l.Lock()
+			assert.False(t, l.Ok(stamp))
+			l.Unlock()
+			assert.False(t, l.Ok(stamp))
+			assert.True(t, l.Ok(stamp))
+		})
+	}
+}
+
+func TestOptimisticThenLocked(t *testing.T) {
+	var rw RWMutex
+	value := 0
+	writes := 0
+	getOrCreate := func() int {
+		var v int
+		OptimisticThenLocked(&rw, func() bool {
+			v = value
+			return v != 0
+		}, func() {
+			writes++
+			value = 42
+			v = value
+		})
+		return v
+	}
+
+	assert.Equal(t, 42, getOrCreate())
+	assert.Equal(t, 42, getOrCreate())
+	assert.Equal(t, 1, writes)
+}
+
+func TestOptimisticThenLockedRechecksUnderLock(t *testing.T) {
+	var rw RWMutex
+	reads := 0
+
+	found := OptimisticThenLocked(&rw, func() bool {
+		reads++
+		// Another writer fills the entry in after the optimistic miss
+		return reads > 1
+	}, func() {
+		t.Fatal("writeFn ran after the locked re-check hit")
+	})
+
+	assert.True(t, found)
+	assert.Equal(t, 2, reads)
+}
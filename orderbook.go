@@ -0,0 +1,189 @@
+package seqmut
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// Side is a side of an OrderBook.
+type Side uint8
+
+const (
+	Bid Side = iota
+	Ask
+)
+
+// PriceLevel is the aggregate of the resting orders at one price.
+type PriceLevel struct {
+	Price    int64
+	Quantity int64
+	Orders   int
+}
+
+// BookSnapshot is the top of both sides of an OrderBook at one point in time.
+// Bids are ordered best (highest) first, asks best (lowest) first.
+type BookSnapshot struct {
+	Bids, Asks []PriceLevel
+}
+
+// OrderBook is a price-level order book, as built from an exchange's
+// order-by-order feed. Each side has its own sequence lock: adds, cancels and
+// executions lock the side they touch, while top-of-book reads are
+// optimistic and never block the feed handler.
+//
+// Prices are integers, in ticks. Messages must be applied in feed order;
+// changes to the same order must not run concurrently.
+type OrderBook struct {
+	sides  [2]bookSide
+	orders SeqMap[uint64, restingOrder]
+}
+
+type bookSide struct {
+	rw RWMutex
+	// Best first. The slice header is replaced when levels are added or
+	// removed, and quantities are updated in place, all under rw.
+	levels atomic.Pointer[[]PriceLevel]
+}
+
+type restingOrder struct {
+	side     Side
+	price    int64
+	quantity int64
+}
+
+func (s *bookSide) load() []PriceLevel {
+	if p := s.levels.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// find returns the index of price, or where it would be inserted.
+func (s *bookSide) find(side Side, price int64) (int, bool) {
+	levels := s.load()
+	i := sort.Search(len(levels), func(i int) bool {
+		if side == Bid {
+			return levels[i].Price <= price
+		}
+		return levels[i].Price >= price
+	})
+	return i, i < len(levels) && levels[i].Price == price
+}
+
+// change adjusts the level at price by quantity and orders, creating or
+// removing it as needed; must hold rw.
+func (s *bookSide) change(side Side, price, quantity int64, orders int) {
+	i, ok := s.find(side, price)
+	levels := s.load()
+	if !ok {
+		levels = append(levels[:i:i], append([]PriceLevel{{Price: price}}, levels[i:]...)...)
+		s.levels.Store(&levels)
+	}
+	levels[i].Quantity += quantity
+	levels[i].Orders += orders
+	if levels[i].Orders == 0 {
+		levels = append(levels[:i:i], levels[i+1:]...)
+		s.levels.Store(&levels)
+	}
+}
+
+// Add adds a resting order. It returns false, changing nothing, if there
+// already is an order with id, if quantity is not positive, or if side is
+// not Bid or Ask.
+func (b *OrderBook) Add(id uint64, side Side, price, quantity int64) bool {
+	if quantity <= 0 || side > Ask {
+		return false
+	}
+	if _, loaded := b.orders.LoadOrStore(id, restingOrder{side, price, quantity}); loaded {
+		return false
+	}
+	s := &b.sides[side]
+	s.rw.Lock()
+	s.change(side, price, quantity, 1)
+	s.rw.Unlock()
+	return true
+}
+
+// Cancel removes a resting order, returning false if there is no such order.
+func (b *OrderBook) Cancel(id uint64) bool {
+	o, ok := b.orders.LoadAndDelete(id)
+	if !ok {
+		return false
+	}
+	s := &b.sides[o.side]
+	s.rw.Lock()
+	s.change(o.side, o.price, -o.quantity, -1)
+	s.rw.Unlock()
+	return true
+}
+
+// Execute fills up to quantity of a resting order, removing the order once it
+// is completely filled, and returns the quantity filled. It returns false,
+// changing nothing, if there is no such order or quantity is not positive.
+func (b *OrderBook) Execute(id uint64, quantity int64) (filled int64, ok bool) {
+	if quantity <= 0 {
+		return 0, false
+	}
+	o, ok := b.orders.Load(id)
+	if !ok {
+		return 0, false
+	}
+	filled = min(quantity, o.quantity)
+	orders := 0
+	if filled == o.quantity {
+		b.orders.Delete(id)
+		orders = -1
+	} else {
+		o.quantity -= filled
+		b.orders.Store(id, o)
+	}
+	s := &b.sides[o.side]
+	s.rw.Lock()
+	s.change(o.side, o.price, -filled, orders)
+	s.rw.Unlock()
+	return filled, true
+}
+
+// top copies up to n of the best levels of the side into dst; n < 0 copies
+// them all. Must be validated by the caller.
+func (s *bookSide) top(dst []PriceLevel, n int) []PriceLevel {
+	levels := s.load()
+	if n < 0 || n > len(levels) {
+		n = len(levels)
+	}
+	return append(dst[:0], levels[:n]...)
+}
+
+// Best returns the best price level on side.
+func (b *OrderBook) Best(side Side) (PriceLevel, bool) {
+	levels := b.Top(side, 1)
+	if len(levels) == 0 {
+		return PriceLevel{}, false
+	}
+	return levels[0], true
+}
+
+// Top returns up to n of the best price levels on side, best first.
+func (b *OrderBook) Top(side Side, n int) []PriceLevel {
+	s := &b.sides[side]
+	var levels []PriceLevel
+	stamp := s.rw.RStamp()
+	for {
+		levels = s.top(levels, n)
+		if s.rw.Ok(stamp) {
+			return levels
+		}
+	}
+}
+
+// Snapshot returns up to n levels of each side, n < 0 meaning all of them,
+// consistent across both sides: both are as they were at a single moment,
+// rather than each side at a different time as with two calls to Top.
+func (b *OrderBook) Snapshot(n int) BookSnapshot {
+	var snap BookSnapshot
+	ReadAll(func() {
+		snap.Bids = b.sides[Bid].top(snap.Bids, n)
+		snap.Asks = b.sides[Ask].top(snap.Asks, n)
+	}, &b.sides[Bid].rw, &b.sides[Ask].rw)
+	return snap
+}
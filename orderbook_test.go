@@ -0,0 +1,103 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestOrderBookLevels(t *testing.T) {
+	var b OrderBook
+	b.Add(1, Bid, 100, 10)
+	b.Add(2, Bid, 101, 5)
+	b.Add(3, Bid, 100, 7)
+	b.Add(4, Ask, 103, 4)
+	b.Add(5, Ask, 102, 1)
+
+	assert.Equal(t, []PriceLevel{{101, 5, 1}, {100, 17, 2}}, b.Top(Bid, -1))
+	assert.Equal(t, []PriceLevel{{102, 1, 1}}, b.Top(Ask, 1))
+	best, ok := b.Best(Bid)
+	assert.True(t, ok)
+	assert.Equal(t, int64(101), best.Price)
+}
+
+func TestOrderBookCancelAndExecute(t *testing.T) {
+	var b OrderBook
+	b.Add(1, Ask, 50, 10)
+	b.Add(2, Ask, 50, 10)
+	b.Add(3, Ask, 51, 10)
+
+	filled, ok := b.Execute(1, 4)
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), filled)
+	assert.Equal(t, []PriceLevel{{50, 16, 2}, {51, 10, 1}}, b.Top(Ask, -1))
+
+	filled, _ = b.Execute(1, 100)
+	assert.Equal(t, int64(6), filled)
+	assert.True(t, b.Cancel(2))
+	assert.False(t, b.Cancel(2))
+	_, ok = b.Execute(2, 1)
+	assert.False(t, ok)
+
+	assert.Equal(t, []PriceLevel{{51, 10, 1}}, b.Top(Ask, -1))
+	_, ok = b.Best(Bid)
+	assert.False(t, ok)
+}
+
+func TestOrderBookRejectsInvalidOrders(t *testing.T) {
+	var b OrderBook
+	assert.True(t, b.Add(1, Bid, 100, 10))
+
+	assert.False(t, b.Add(1, Ask, 101, 5))
+	assert.False(t, b.Add(2, Bid, 100, 0))
+	assert.False(t, b.Add(3, Bid, 100, -5))
+	assert.False(t, b.Add(4, Side(2), 100, 5))
+	for _, quantity := range []int64{0, -3} {
+		filled, ok := b.Execute(1, quantity)
+		assert.False(t, ok)
+		assert.Equal(t, int64(0), filled)
+	}
+
+	assert.Equal(t, []PriceLevel{{100, 10, 1}}, b.Top(Bid, -1))
+	assert.Empty(t, b.Top(Ask, -1))
+	assert.False(t, b.Cancel(2))
+	assert.True(t, b.Cancel(1))
+	assert.Empty(t, b.Top(Bid, -1))
+}
+
+func TestOrderBookSnapshotIsConsistentAcrossSides(t *testing.T) {
+	var b OrderBook
+	b.Add(1, Bid, 99, 1)
+	b.Add(2, Ask, 101, 1)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Shift the whole book up by one tick, holding both sides so
+			// the move commits as one
+			b.sides[Bid].rw.Lock()
+			b.sides[Ask].rw.Lock()
+			bid, ask := b.sides[Bid].load()[0], b.sides[Ask].load()[0]
+			b.sides[Bid].change(Bid, bid.Price, -1, -1)
+			b.sides[Bid].change(Bid, bid.Price+1, 1, 1)
+			b.sides[Ask].change(Ask, ask.Price, -1, -1)
+			b.sides[Ask].change(Ask, ask.Price+1, 1, 1)
+			b.sides[Ask].rw.Unlock()
+			b.sides[Bid].rw.Unlock()
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		snap := b.Snapshot(1)
+		assert.Equal(t, snap.Bids[0].Price+2, snap.Asks[0].Price)
+	}
+	close(done)
+	wg.Wait()
+}
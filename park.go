@@ -0,0 +1,55 @@
+package seqmut
+
+import "sync/atomic"
+
+// OkOrPark is like Ok, but if the read failed because a writer is holding
+// the lock, it blocks until that writer calls Unlock instead of handing back
+// a stamp the caller would just spin on. Use it when writers hold the lock
+// for long stretches and burning CPU in the retry loop is worse than the
+// latency of being woken up.
+//
+// As with Ok, a false return means the stamp has been updated and the critical
+// section must be retried.
+func (rw *RWMutex) OkOrPark(stamp *Stamp) (ok bool) {
+	if rw.Ok(stamp) {
+		return true
+	}
+	if (*stamp & 1) == 1 {
+		rw.park(uint64(rw.plain(stamp)))
+		*stamp = Stamp(atomic.LoadUint64(&rw.sequence)) ^ rw.ownerTag()
+	}
+	return false
+}
+
+// park blocks until the sequence moves past the odd value seen by the caller.
+func (rw *RWMutex) park(seen uint64) {
+	// Register before re-checking the sequence: Unlock bumps the sequence before
+	// looking at parked, so either we see the bump or Unlock sees us.
+	atomic.AddInt32(&rw.parked, 1)
+	defer atomic.AddInt32(&rw.parked, -1)
+
+	rw.parkMu.Lock()
+	if atomic.LoadUint64(&rw.sequence) != seen {
+		rw.parkMu.Unlock()
+		return
+	}
+	if rw.wake == nil {
+		rw.wake = make(chan struct{})
+	}
+	wake := rw.wake
+	rw.parkMu.Unlock()
+
+	<-wake
+}
+
+func (rw *RWMutex) wakeParked() {
+	if atomic.LoadInt32(&rw.parked) == 0 {
+		return
+	}
+	rw.parkMu.Lock()
+	if rw.wake != nil {
+		close(rw.wake)
+		rw.wake = nil
+	}
+	rw.parkMu.Unlock()
+}
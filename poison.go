@@ -0,0 +1,44 @@
+package seqmut
+
+import (
+	"math"
+	"reflect"
+)
+
+// poisonPattern is written over the numbers in poisoned memory.
+const poisonPattern uint64 = 0xdbdbdbdbdbdbdbdb
+
+// poison overwrites *p with values that stand out in a debugger or a failing
+// test: numbers become 0xdb... patterns, floats NaN, strings a marker and
+// booleans true. References are set to nil rather than garbage, which the GC
+// would choke on, so following one fails with a nil dereference.
+func poison[T any](p *T) {
+	poisonValue(reflect.ValueOf(p).Elem())
+}
+
+func poisonValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(poisonPattern >> (64 - v.Type().Bits())))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(poisonPattern >> (64 - v.Type().Bits()))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(math.NaN())
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex(math.NaN(), math.NaN()))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.String:
+		v.SetString("seqmut: poisoned")
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			poisonValue(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			poisonValue(settable(v.Field(i)))
+		}
+	default:
+		v.SetZero()
+	}
+}
@@ -0,0 +1,31 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+type poisonable struct {
+	count  int32
+	ratio  float64
+	name   string
+	done   bool
+	next   *poisonable
+	counts [2]uint8
+	tags   []string
+}
+
+func TestPoison(t *testing.T) {
+	v := poisonable{count: 1, name: "n", next: &poisonable{}, tags: []string{"a"}}
+
+	poison(&v)
+
+	assert.Equal(t, int32(-0x24242425), v.count)
+	assert.True(t, math.IsNaN(v.ratio))
+	assert.Equal(t, "seqmut: poisoned", v.name)
+	assert.True(t, v.done)
+	assert.Nil(t, v.next)
+	assert.Equal(t, [2]uint8{0xdb, 0xdb}, v.counts)
+	assert.Nil(t, v.tags)
+}
@@ -0,0 +1,57 @@
+package seqmut
+
+import "errors"
+
+// PoolState is the state of a connection pool.
+type PoolState struct {
+	// Connections open and idle, and open and handed out
+	Idle, InUse int
+	// Callers blocked waiting for a connection
+	Waiters int
+	// Connections ever opened and ever closed
+	Created, Closed uint64
+}
+
+// Open returns the number of open connections.
+func (s PoolState) Open() int {
+	return s.Idle + s.InUse
+}
+
+// SeqPoolState guards a PoolState, so health endpoints and pool sizing logic
+// read all of its fields as of the same moment, while the pool changes
+// several at once with Apply; handing out an idle connection, for instance,
+// moves it from Idle to InUse in one step.
+//
+// In debug builds every Apply checks that the counts are not negative and
+// that Created minus Closed matches the open connections.
+type SeqPoolState struct {
+	p Protected[PoolState]
+}
+
+// NewSeqPoolState returns the state of an empty pool.
+func NewSeqPoolState() *SeqPoolState {
+	s := &SeqPoolState{}
+	s.p.AddInvariant("pool accounting", checkPoolState)
+	return s
+}
+
+func checkPoolState(s *PoolState) error {
+	if s.Idle < 0 || s.InUse < 0 || s.Waiters < 0 {
+		return errors.New("negative count")
+	}
+	if s.Created-s.Closed != uint64(s.Open()) {
+		return errors.New("created minus closed does not match open connections")
+	}
+	return nil
+}
+
+// Load returns the current state.
+func (s *SeqPoolState) Load() PoolState {
+	return s.p.Load()
+}
+
+// Apply runs fn with exclusive access to the state; readers see either none
+// or all of its changes.
+func (s *SeqPoolState) Apply(fn func(s *PoolState)) {
+	s.p.Update(fn)
+}
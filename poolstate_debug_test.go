@@ -0,0 +1,15 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeqPoolStateChecksAccounting(t *testing.T) {
+	assert.Panics(t, func() { NewSeqPoolState().Apply(func(p *PoolState) { p.Idle++ }) })
+	assert.Panics(t, func() { NewSeqPoolState().Apply(func(p *PoolState) { p.Waiters-- }) })
+	assert.NotPanics(t, func() { NewSeqPoolState().Apply(func(p *PoolState) { p.Created, p.Idle = 1, 1 }) })
+}
@@ -0,0 +1,54 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestSeqPoolStateApply(t *testing.T) {
+	s := NewSeqPoolState()
+	s.Apply(func(p *PoolState) {
+		p.Created += 2
+		p.Idle += 2
+	})
+	s.Apply(func(p *PoolState) {
+		p.Idle--
+		p.InUse++
+	})
+
+	assert.Equal(t, PoolState{Idle: 1, InUse: 1, Created: 2}, s.Load())
+	assert.Equal(t, 2, s.Load().Open())
+}
+
+func TestSeqPoolStateReadsAreConsistent(t *testing.T) {
+	s := NewSeqPoolState()
+	s.Apply(func(p *PoolState) { p.Created, p.Idle = 4, 4 })
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Check a connection out, or back in
+			s.Apply(func(p *PoolState) {
+				if i%2 == 0 {
+					p.Idle, p.InUse = p.Idle-1, p.InUse+1
+				} else {
+					p.Idle, p.InUse = p.Idle+1, p.InUse-1
+				}
+			})
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, 4, s.Load().Open())
+	}
+	close(done)
+	wg.Wait()
+}
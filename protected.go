@@ -0,0 +1,236 @@
+package seqmut
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Protected holds a value of type T guarded by an RWMutex. Readers take
+// optimistic copies, writers mutate in place under the write lock.
+//
+// The zero value is ready to use and holds the zero value of T.
+type Protected[T any] struct {
+	rw    RWMutex
+	value T
+
+	// Holds a *snapshot[T] of the last committed value, if RetainSnapshots was called
+	retained atomic.Value
+
+	// Checked at every commit in debug builds, see AddInvariant
+	invariants []invariant[T]
+	// Copy of the value taken at lock time, for reporting broken invariants;
+	// only allocated in debug builds, by the first write once there are any
+	before *T
+
+	// Write-ahead log for UpdateLogged, if any
+	wal WAL
+
+	// Fed by Load and Read retries, see CountRetries
+	counter *RetryCounter
+
+	// Recent commit times, if RecordCommitTimes was called
+	commits atomic.Pointer[commitLog]
+
+	// Bumped after every commit, see BumpOnCommit
+	topics []*Topic
+
+	// Set by Freeze, after which writes are refused
+	sealed atomic.Bool
+	// Set while the last write to finish panicked part way through fn
+	poisoned atomic.Bool
+}
+
+func NewProtected[T any](initial T) *Protected[T] {
+	return &Protected[T]{value: initial}
+}
+
+// Load returns a consistent copy of the guarded value. The copy is shallow;
+// anything T points to is shared with the writers.
+func (p *Protected[T]) Load() T {
+	var v T
+	stamp := p.rw.RStamp()
+	for {
+		v = p.value
+		if p.rw.Ok(stamp) {
+			return v
+		}
+		p.counter.add()
+	}
+}
+
+// Read runs fn as an optimistic critical section over the guarded value,
+// re-running it until it completes without a racing writer. fn may observe
+// torn state on attempts that end up being retried, so it must not act on
+// what it reads until Read returns.
+func (p *Protected[T]) Read(fn func(v *T)) {
+	stamp := p.rw.RStamp()
+	for {
+		fn(&p.value)
+		if p.rw.Ok(stamp) {
+			return
+		}
+		p.counter.add()
+	}
+}
+
+// CountRetries makes Load, Read, View and ReadInto count their retries in c;
+// pass nil to stop counting. It must not be called concurrently with reads.
+func (p *Protected[T]) CountRetries(c *RetryCounter) {
+	p.counter = c
+}
+
+// Snapshot returns a deep copy of the guarded value, see Clone. Following
+// pointers and iterating maps cannot be done safely against a racing writer,
+// so unlike Load this excludes writers while copying, without disturbing
+// optimistic readers.
+func (p *Protected[T]) Snapshot() T {
+	v, _ := p.SnapshotWith(Clone[T])
+	return v
+}
+
+// SnapshotWith is Snapshot with a caller-supplied deep copy function, for
+// types that need a specific one. It also returns the stamp of the copied
+// version.
+func (p *Protected[T]) SnapshotWith(clone func(v T) T) (T, Stamp) {
+	p.rw.acquire()
+	defer p.rw.release()
+	return clone(p.value), Stamp(atomic.LoadUint64(&p.rw.sequence))
+}
+
+// Store replaces the guarded value, which clears any poison left by a
+// panicking writer. It panics with ErrSealed if the value has been frozen.
+func (p *Protected[T]) Store(v T) {
+	p.rw.acquire()
+	if p.sealed.Load() {
+		p.rw.release()
+		panic(ErrSealed)
+	}
+	p.enter()
+	p.value = v
+	p.poisoned.Store(false)
+	p.unlock()
+}
+
+// Update runs fn with exclusive access to the guarded value. If fn panics,
+// the value is left poisoned, see TryUpdate. Update panics with ErrSealed if
+// the value has been frozen.
+func (p *Protected[T]) Update(fn func(v *T)) {
+	if err := p.write(fn, false); err != nil {
+		panic(err)
+	}
+}
+
+// TryUpdate is Update, except that it returns ErrSealed rather than
+// panicking if the value has been frozen, and refuses with ErrPoisoned if an
+// earlier writer panicked in fn, since the value may have been left half
+// updated. Store a known good value to clear the poison.
+func (p *Protected[T]) TryUpdate(fn func(v *T)) error {
+	return p.write(fn, true)
+}
+
+// Poisoned reports whether the last write panicked part way through.
+func (p *Protected[T]) Poisoned() bool {
+	return p.poisoned.Load()
+}
+
+// Freeze makes the guarded value read-only: from then on, Store and Update
+// panic with ErrSealed, and writes that return an error fail with it.
+func (p *Protected[T]) Freeze() {
+	p.rw.acquire()
+	p.sealed.Store(true)
+	p.rw.release()
+}
+
+// write runs fn as a write, refusing with ErrSealed once the value is frozen
+// and, if checkPoison is set, with ErrPoisoned after a panicking write.
+func (p *Protected[T]) write(fn func(v *T), checkPoison bool) error {
+	p.rw.acquire()
+	if err := p.refuse(checkPoison); err != nil {
+		p.rw.release()
+		return err
+	}
+	p.enter()
+	return p.apply(fn)
+}
+
+// refuse returns why a write must not go ahead, if it must not; the mutex
+// must be held.
+func (p *Protected[T]) refuse(checkPoison bool) error {
+	if p.sealed.Load() {
+		return ErrSealed
+	}
+	if checkPoison && p.poisoned.Load() {
+		return ErrPoisoned
+	}
+	return nil
+}
+
+// apply runs fn inside an entered write and ends the write, poisoning the
+// value if fn panics.
+func (p *Protected[T]) apply(fn func(v *T)) error {
+	done := false
+	defer func() {
+		if !done {
+			p.poisoned.Store(true)
+		}
+		p.unlock()
+	}()
+	fn(&p.value)
+	done = true
+	return nil
+}
+
+// LoadStamped returns a consistent copy of the guarded value together with
+// the stamp it was read at. The stamp changes on every write, so it can be
+// used as a version token.
+func (p *Protected[T]) LoadStamped() (T, Stamp) {
+	var v T
+	stamp := p.rw.RStamp()
+	for {
+		v = p.value
+		if p.rw.Ok(stamp) {
+			return v, p.rw.plain(stamp)
+		}
+	}
+}
+
+// lock starts a write.
+func (p *Protected[T]) lock() {
+	p.rw.acquire()
+	p.enter()
+}
+
+// enter starts the write critical section; the mutex must already be held.
+func (p *Protected[T]) enter() {
+	p.rw.enter()
+	if debug && len(p.invariants) > 0 {
+		if p.before == nil {
+			p.before = new(T)
+		}
+		*p.before = Clone(p.value)
+	}
+}
+
+// unlock ends a write, first publishing the committed value if snapshots are
+// being retained. If the write broke an invariant, it panics once the write
+// has been committed.
+func (p *Protected[T]) unlock() {
+	var broken string
+	if debug && len(p.invariants) > 0 {
+		broken = p.brokenInvariant()
+	}
+	if p.retained.Load() != nil {
+		p.retained.Store(&snapshot[T]{value: Clone(p.value), stamp: Stamp(atomic.LoadUint64(&p.rw.sequence) + 1)})
+	}
+	if c := p.commits.Load(); c != nil {
+		c.record(Stamp(atomic.LoadUint64(&p.rw.sequence)+1), time.Now())
+	}
+	topics := p.topics
+	p.rw.Unlock()
+	for _, t := range topics {
+		t.Bump()
+	}
+	if broken != "" {
+		panic(broken)
+	}
+}
@@ -0,0 +1,110 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+type pair struct {
+	a, b int
+}
+
+func TestProtectedZeroValue(t *testing.T) {
+	var p Protected[pair]
+
+	assert.Equal(t, pair{}, p.Load())
+}
+
+func TestProtectedStoreThenLoad(t *testing.T) {
+	p := NewProtected(pair{1, 1})
+
+	p.Store(pair{2, 2})
+
+	assert.Equal(t, pair{2, 2}, p.Load())
+}
+
+func TestProtectedUpdateAndRead(t *testing.T) {
+	p := NewProtected(pair{1, 1})
+
+	p.Update(func(v *pair) { v.a++ })
+
+	var a int
+	p.Read(func(v *pair) { a = v.a })
+	assert.Equal(t, 2, a)
+}
+
+func TestProtectedNeverTorn(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 5
+	}
+	var p Protected[pair]
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			p.Update(func(v *pair) {
+				v.a++
+				v.b++
+			})
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				v := p.Load()
+				if v.a != v.b {
+					panic("torn read")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPanickingWriterPoisons(t *testing.T) {
+	var p Protected[pair]
+
+	assert.Panics(t, func() {
+		p.Update(func(v *pair) {
+			v.a++
+			panic("half way")
+		})
+	})
+
+	assert.True(t, p.Poisoned())
+	assert.Equal(t, ErrPoisoned, p.TryUpdate(func(v *pair) { v.b++ }))
+	assert.Equal(t, ErrPoisoned, p.UpdateLogged(nil, func(v *pair) { v.b++ }))
+	assert.Equal(t, pair{a: 1}, p.Load())
+
+	// Update carries on regardless, Store clears the poison
+	p.Update(func(v *pair) { v.b++ })
+	assert.True(t, p.Poisoned())
+	p.Store(pair{})
+	assert.False(t, p.Poisoned())
+	assert.NoError(t, p.TryUpdate(func(v *pair) { v.a++ }))
+	assert.Equal(t, pair{a: 1}, p.Load())
+}
+
+func TestFrozenRefusesWrites(t *testing.T) {
+	p := NewProtected(pair{a: 1})
+	_, stamp := p.LoadStamped()
+
+	p.Freeze()
+
+	assert.PanicsWithValue(t, ErrSealed, func() { p.Store(pair{}) })
+	assert.PanicsWithValue(t, ErrSealed, func() { p.Update(func(v *pair) { v.a++ }) })
+	assert.True(t, errors.Is(p.TryUpdate(func(v *pair) { v.a++ }), ErrSealed))
+	assert.True(t, errors.Is(p.UpdateLogged(nil, func(v *pair) { v.a++ }), ErrSealed))
+	v, after := p.LoadStamped()
+	assert.Equal(t, pair{a: 1}, v)
+	assert.Equal(t, stamp, after)
+}
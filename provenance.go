@@ -0,0 +1,72 @@
+package seqmut
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// In debug builds, every stamp handed out by RWMutex.RStamp carries an owner
+// word, identifying the lock it came from, XORed into its top bits, so that
+// Ok can catch a stamp being validated against the wrong lock. That "works"
+// silently otherwise, with no consistency guarantee at all. Copies of the
+// stamp carry the word along, so they are checked too.
+//
+// A lock recognizes its own stamps because, with its owner word taken back
+// out, they are within a plausible number of writes of its sequence, where a
+// stamp carrying another lock's word is not. Plain stamps, such as those
+// returned by LoadStamped or built from a sequence, are recognized the same
+// way without taking anything out, and are not checked any further. The word
+// is a hash of the lock's address, so two locks can collide and a mixup
+// between them go unnoticed, one time in 65535.
+//
+// In regular builds ownerBits is zero and all of this compiles away.
+
+// plausibleWrites bounds how far apart a stamp and the sequence of the lock
+// it came from can be.
+const plausibleWrites = 1 << 47
+
+// ownerTag returns the owner word rw puts in its stamps.
+func (rw *RWMutex) ownerTag() Stamp {
+	tag := Stamp(uint64(uintptr(unsafe.Pointer(rw)))*0x9e3779b97f4a7c15) & ownerBits
+	if tag == 0 {
+		tag = 1 << 48 & ownerBits
+	}
+	return tag
+}
+
+// plain returns a stamp rw handed out, and Ok has kept up to date, as a
+// plain sequence.
+func (rw *RWMutex) plain(stamp *Stamp) Stamp {
+	return *stamp ^ rw.ownerTag()
+}
+
+// checkOwner returns stamp as a plain sequence, taking out rw's owner word if
+// it carries it, and panics if it looks like it came from another lock.
+func (rw *RWMutex) checkOwner(stamp Stamp) Stamp {
+	if !debug {
+		return stamp
+	}
+	current := Stamp(atomic.LoadUint64(&rw.sequence))
+	plausible := func(s Stamp) bool {
+		return current-s < plausibleWrites || s-current < plausibleWrites
+	}
+	if plausible(stamp) {
+		return stamp
+	}
+	if s := stamp ^ rw.ownerTag(); plausible(s) {
+		return s
+	}
+	panic("seqmut: stamp from a different lock")
+}
+
+// okOwned is Ok for debug builds: it checks the owner of the stamp before
+// validating it, and gives the refreshed stamp rw's owner word.
+func (rw *RWMutex) okOwned(stamp *Stamp) bool {
+	s := rw.checkOwner(*stamp)
+	ok := validate(&rw.sequence, &s)
+	*stamp = s ^ rw.ownerTag()
+	if !ok {
+		atomic.AddUint64(&rw.retries, 1)
+	}
+	return ok
+}
@@ -0,0 +1,45 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOkWithStampFromAnotherLockPanics(t *testing.T) {
+	var a, b RWMutex
+	stamp := a.RStamp()
+
+	assert.True(t, a.Ok(stamp))
+	assert.Panics(t, func() { b.Ok(stamp) })
+}
+
+func TestOkWithCopiedStampFromAnotherLockPanics(t *testing.T) {
+	var a, b RWMutex
+	stamp := *a.RStamp()
+
+	assert.Panics(t, func() { b.Ok(&stamp) })
+	assert.Panics(t, func() { b.LockIfUnchanged(stamp) })
+}
+
+func TestStampsHandedBackAreUnowned(t *testing.T) {
+	var a, b Protected[int]
+	_, stamp := a.LoadStamped()
+
+	// A plain sequence, like any other Stamp built from one, is not checked
+	assert.Equal(t, Stamp(0), stamp)
+	assert.True(t, b.rw.Ok(&stamp))
+}
+
+func TestOwnedStampSurvivesRetries(t *testing.T) {
+	var a, b RWMutex
+	stamp := a.RStamp()
+	a.Lock()
+	a.Unlock()
+
+	assert.False(t, a.Ok(stamp))
+	assert.True(t, a.Ok(stamp))
+	assert.Panics(t, func() { b.Ok(stamp) })
+}
@@ -0,0 +1,89 @@
+package seqmut
+
+import (
+	"sync"
+	"time"
+)
+
+// Tick is one market data update for an instrument.
+type Tick struct {
+	Bid, Ask, Last             int64
+	BidSize, AskSize, LastSize int64
+	Time                       time.Time
+}
+
+// SeqQuote publishes ticks for an instrument so that readers never see a tick
+// mixing fields from two updates, such as a new bid with the previous ask.
+// It can also keep the last few ticks, readable together as one consistent
+// history.
+//
+// Publish is the fast path for a feed handler that is the only writer, and
+// is just two sequence increments around the copy. Where several goroutines
+// publish, use PublishConcurrent instead.
+type SeqQuote struct {
+	lock SingleWriterSeqLock
+	// Serializes PublishConcurrent callers
+	mu      sync.Mutex
+	tick    Tick
+	history []Tick
+	// Number of ticks published
+	count uint64
+}
+
+// NewSeqQuote returns a quote keeping the last history ticks; 0 keeps none
+// beyond the latest.
+func NewSeqQuote(history int) *SeqQuote {
+	return &SeqQuote{history: make([]Tick, history)}
+}
+
+// Publish makes t the latest tick. It must only be called from one
+// goroutine; building with -tags seqmutdebug detects concurrent publishers.
+func (q *SeqQuote) Publish(t Tick) {
+	q.lock.Lock()
+	q.tick = t
+	if len(q.history) > 0 {
+		q.history[q.count%uint64(len(q.history))] = t
+	}
+	q.count++
+	q.lock.Unlock()
+}
+
+// PublishConcurrent is Publish for quotes with more than one writer.
+func (q *SeqQuote) PublishConcurrent(t Tick) {
+	q.mu.Lock()
+	q.Publish(t)
+	q.mu.Unlock()
+}
+
+// Load returns the latest tick, and false if none has been published.
+func (q *SeqQuote) Load() (t Tick, ok bool) {
+	stamp := q.lock.RStamp()
+	for {
+		t, ok = q.tick, q.count > 0
+		if q.lock.Ok(stamp) {
+			return t, ok
+		}
+	}
+}
+
+// History returns the kept ticks, oldest first, all as of the same moment,
+// appended to dst.
+func (q *SeqQuote) History(dst []Tick) []Tick {
+	n := len(dst)
+	size := uint64(len(q.history))
+	stamp := q.lock.RStamp()
+	for {
+		dst = dst[:n]
+		count := q.count
+		start := uint64(0)
+		if count > size {
+			start = count - size
+		}
+		for i := start; i < count; i++ {
+			dst = append(dst, q.history[i%size])
+		}
+		if q.lock.Ok(stamp) {
+			return dst
+		}
+	}
+}
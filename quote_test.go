@@ -0,0 +1,64 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestSeqQuoteLoad(t *testing.T) {
+	q := NewSeqQuote(0)
+	_, ok := q.Load()
+	assert.False(t, ok)
+
+	q.Publish(Tick{Bid: 99, Ask: 101})
+	tick, ok := q.Load()
+	assert.True(t, ok)
+	assert.Equal(t, Tick{Bid: 99, Ask: 101}, tick)
+	assert.Empty(t, q.History(nil))
+}
+
+func TestSeqQuoteHistoryIsOldestFirst(t *testing.T) {
+	q := NewSeqQuote(3)
+	q.Publish(Tick{Last: 1})
+	q.Publish(Tick{Last: 2})
+	assert.Equal(t, []Tick{{Last: 1}, {Last: 2}}, q.History(nil))
+
+	for i := int64(3); i <= 5; i++ {
+		q.Publish(Tick{Last: i})
+	}
+	assert.Equal(t, []Tick{{Last: 3}, {Last: 4}, {Last: 5}}, q.History(nil))
+}
+
+func TestSeqQuoteReadersNeverSeeMixedTicks(t *testing.T) {
+	q := NewSeqQuote(4)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int64(0); ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				q.PublishConcurrent(Tick{Bid: i, Ask: i + 1, BidSize: i, AskSize: i})
+			}
+		}()
+	}
+
+	var history []Tick
+	for i := 0; i < 1000; i++ {
+		if tick, ok := q.Load(); ok {
+			assert.Equal(t, tick.Bid+1, tick.Ask)
+		}
+		history = q.History(history[:0])
+		for _, tick := range history {
+			assert.Equal(t, tick.BidSize, tick.AskSize)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package seqmut
+
+import (
+	"math"
+	"time"
+)
+
+// RateSnapshot is the state of a RateEstimator at one moment.
+type RateSnapshot struct {
+	// Total observed
+	Count uint64
+	// Time of the last observation
+	Last time.Time
+	// Exponentially weighted rate per second as of Last
+	Rate float64
+}
+
+// RateEstimator estimates the rate of events, such as requests per second for
+// load shedding, as an exponentially weighted moving average. Observe is a
+// short write; Rate and Snapshot read the count, timestamp and smoothed rate
+// together, so a decision is never based on a rate from one observation
+// decayed by the time since another.
+type RateEstimator struct {
+	rw    RWMutex
+	tau   float64
+	state RateSnapshot
+}
+
+// NewRateEstimator returns an estimator whose memory of past events halves
+// every halfLife.
+func NewRateEstimator(halfLife time.Duration) *RateEstimator {
+	return &RateEstimator{tau: halfLife.Seconds() / math.Ln2}
+}
+
+// Observe records n events happening now.
+func (r *RateEstimator) Observe(n uint64) {
+	r.ObserveAt(n, time.Now())
+}
+
+// ObserveAt records n events happening at t. Observations must arrive in time
+// order; one older than the last is counted as happening at the same time.
+func (r *RateEstimator) ObserveAt(n uint64, t time.Time) {
+	r.rw.Lock()
+	s := &r.state
+	if !s.Last.IsZero() {
+		s.Rate = r.decay(s.Rate, t.Sub(s.Last))
+	}
+	if t.After(s.Last) {
+		s.Last = t
+	}
+	s.Count += n
+	s.Rate += float64(n) / r.tau
+	r.rw.Unlock()
+}
+
+func (r *RateEstimator) decay(rate float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return rate
+	}
+	return rate * math.Exp(-elapsed.Seconds()/r.tau)
+}
+
+// Snapshot returns the state as of the last observation.
+func (r *RateEstimator) Snapshot() RateSnapshot {
+	var s RateSnapshot
+	stamp := r.rw.RStamp()
+	for {
+		s = r.state
+		if r.rw.Ok(stamp) {
+			return s
+		}
+	}
+}
+
+// Rate returns the estimated rate per second now.
+func (r *RateEstimator) Rate() float64 {
+	return r.RateAt(time.Now())
+}
+
+// RateAt returns the estimated rate per second at t, decaying the rate since
+// the last observation.
+func (r *RateEstimator) RateAt(t time.Time) float64 {
+	s := r.Snapshot()
+	return r.decay(s.Rate, t.Sub(s.Last))
+}
@@ -0,0 +1,35 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRateEstimatorConvergesOnSteadyRate(t *testing.T) {
+	r := NewRateEstimator(time.Second)
+	start := time.Unix(1000, 0)
+
+	// 100 events per second for a minute
+	for i := 0; i < 6000; i++ {
+		r.ObserveAt(1, start.Add(time.Duration(i)*10*time.Millisecond))
+	}
+
+	snap := r.Snapshot()
+	assert.Equal(t, uint64(6000), snap.Count)
+	assert.InDelta(t, 100, snap.Rate, 1)
+	assert.InDelta(t, 100, r.RateAt(snap.Last), 1)
+}
+
+func TestRateEstimatorDecaysWhenIdle(t *testing.T) {
+	r := NewRateEstimator(time.Second)
+	start := time.Unix(1000, 0)
+	for i := 0; i < 6000; i++ {
+		r.ObserveAt(1, start.Add(time.Duration(i)*10*time.Millisecond))
+	}
+	last := r.Snapshot().Last
+
+	assert.InDelta(t, 50, r.RateAt(last.Add(time.Second)), 1)
+	assert.InDelta(t, 25, r.RateAt(last.Add(2*time.Second)), 1)
+	assert.Equal(t, float64(0), NewRateEstimator(time.Second).RateAt(start))
+}
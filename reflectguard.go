@@ -0,0 +1,207 @@
+package seqmut
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ReflectGuard guards an arbitrary value and deep-copies it out for readers,
+// using a copy plan built once per type with reflection. It is slower than
+// code from seqmutgen, but needs no setup.
+//
+// The copy is optimistic. Before following any pointer, slice or interface
+// read from the guarded value, the copy checks that no writer has come along
+// since it started, so it never dereferences a torn header. Maps cannot be
+// read at all while being written, so types containing maps are copied with
+// writers held off instead. Values must not contain pointer cycles.
+type ReflectGuard[T any] struct {
+	rw    RWMutex
+	value T
+}
+
+// LoadInto deep-copies the guarded value into dst. Slices already in dst are
+// reused where they have the capacity, and pointers already in dst are copied
+// through, so repeated loads into the same destination do not allocate once
+// it has grown to fit.
+func (g *ReflectGuard[T]) LoadInto(dst *T) {
+	p := planFor(reflect.TypeOf(dst).Elem())
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(&g.value).Elem()
+
+	if p.exclusive {
+		g.rw.acquire()
+		defer g.rw.release()
+		p.copy(d, s, validator{})
+		return
+	}
+
+	stamp := g.rw.RStamp()
+	for {
+		seen := g.rw.plain(stamp)
+		if p.copy(d, s, validator{sequence: &g.rw.sequence, seen: seen}) && g.rw.Ok(stamp) {
+			return
+		}
+		if seen == g.rw.plain(stamp) {
+			// The copy bailed out early, so Ok never got to refresh the stamp
+			*stamp = Stamp(atomic.LoadUint64(&g.rw.sequence)) ^ g.rw.ownerTag()
+		}
+	}
+}
+
+// Load returns a deep copy of the guarded value.
+func (g *ReflectGuard[T]) Load() T {
+	var v T
+	g.LoadInto(&v)
+	return v
+}
+
+// Store replaces the guarded value.
+func (g *ReflectGuard[T]) Store(v T) {
+	g.rw.Lock()
+	g.value = v
+	g.rw.Unlock()
+}
+
+// Update runs fn with exclusive access to the guarded value.
+func (g *ReflectGuard[T]) Update(fn func(v *T)) {
+	g.rw.Lock()
+	defer g.rw.Unlock()
+	fn(&g.value)
+}
+
+// validator checks that no writer has come along since a copy started. The
+// zero validator, used when writers are held off, always passes.
+type validator struct {
+	sequence *uint64
+	seen     Stamp
+}
+
+func (v validator) unchanged() bool {
+	return v.sequence == nil || ((v.seen&1) == 0 && Stamp(atomic.LoadUint64(v.sequence)) == v.seen)
+}
+
+// copyPlan copies src into dst, checking the validator before dereferencing
+// anything read from src, and giving up with false as soon as it fails.
+type copyPlan struct {
+	copy      func(dst, src reflect.Value, v validator) bool
+	exclusive bool
+}
+
+var copyPlans sync.Map // reflect.Type -> *copyPlan
+
+func planFor(typ reflect.Type) *copyPlan {
+	if p, ok := copyPlans.Load(typ); ok {
+		return p.(*copyPlan)
+	}
+	p := buildPlan(typ, map[reflect.Type]*copyPlan{})
+	copyPlans.Store(typ, p)
+	return p
+}
+
+func buildPlan(typ reflect.Type, building map[reflect.Type]*copyPlan) *copyPlan {
+	if p, ok := building[typ]; ok {
+		// Recursive type; the plan is filled in by the time it runs
+		return p
+	}
+	p := &copyPlan{}
+	building[typ] = p
+
+	if indirectionOf(typ) <= indirectStrings {
+		p.copy = func(dst, src reflect.Value, _ validator) bool {
+			dst.Set(src)
+			return true
+		}
+		return p
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		fields := make([]*copyPlan, typ.NumField())
+		for i := range fields {
+			fields[i] = buildPlan(typ.Field(i).Type, building)
+			p.exclusive = p.exclusive || fields[i].exclusive
+		}
+		p.copy = func(dst, src reflect.Value, v validator) bool {
+			for i, f := range fields {
+				if !f.copy(settable(dst.Field(i)), settable(src.Field(i)), v) {
+					return false
+				}
+			}
+			return true
+		}
+	case reflect.Array:
+		elem := buildPlan(typ.Elem(), building)
+		p.exclusive = elem.exclusive
+		p.copy = func(dst, src reflect.Value, v validator) bool {
+			for i := 0; i < src.Len(); i++ {
+				if !elem.copy(dst.Index(i), src.Index(i), v) {
+					return false
+				}
+			}
+			return true
+		}
+	case reflect.Slice:
+		elem := buildPlan(typ.Elem(), building)
+		p.exclusive = elem.exclusive
+		p.copy = func(dst, src reflect.Value, v validator) bool {
+			isNil, n := src.IsNil(), src.Len()
+			if !v.unchanged() {
+				return false
+			}
+			if isNil {
+				dst.SetZero()
+				return true
+			}
+			if dst.Cap() < n {
+				dst.Set(reflect.MakeSlice(typ, n, n))
+			} else {
+				dst.SetLen(n)
+			}
+			for i := 0; i < n; i++ {
+				if !elem.copy(dst.Index(i), src.Index(i), v) {
+					return false
+				}
+			}
+			return true
+		}
+	case reflect.Pointer:
+		elem := buildPlan(typ.Elem(), building)
+		p.exclusive = elem.exclusive
+		p.copy = func(dst, src reflect.Value, v validator) bool {
+			isNil := src.IsNil()
+			if !v.unchanged() {
+				return false
+			}
+			if isNil {
+				dst.SetZero()
+				return true
+			}
+			if dst.IsNil() {
+				dst.Set(reflect.New(typ.Elem()))
+			}
+			return elem.copy(dst.Elem(), src.Elem(), v)
+		}
+	case reflect.Map:
+		p.exclusive = true
+		p.copy = func(dst, src reflect.Value, _ validator) bool {
+			c := cloner{pkg: ownerPkg(typ), seen: map[uintptr]reflect.Value{}}
+			c.deepCopy(dst, src)
+			return true
+		}
+	default:
+		// Interfaces, channels and funcs are copied shallowly
+		p.copy = func(dst, src reflect.Value, v validator) bool {
+			i := src.Interface()
+			if !v.unchanged() {
+				return false
+			}
+			if i == nil {
+				dst.SetZero()
+			} else {
+				dst.Set(reflect.ValueOf(i))
+			}
+			return true
+		}
+	}
+	return p
+}
@@ -0,0 +1,117 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+type route struct {
+	Path    string
+	Weights []int
+	Next    *route
+}
+
+type table struct {
+	Version int
+	Routes  []route
+	Extra   interface{}
+	hidden  []string
+}
+
+func TestReflectGuardDeepCopies(t *testing.T) {
+	var g ReflectGuard[table]
+	g.Store(table{
+		Version: 1,
+		Routes:  []route{{Path: "/a", Weights: []int{1, 2}, Next: &route{Path: "/b"}}},
+		Extra:   "x",
+		hidden:  []string{"h"},
+	})
+
+	v := g.Load()
+	g.Update(func(t *table) {
+		t.Routes[0].Weights[0] = 100
+		t.Routes[0].Next.Path = "/c"
+		t.hidden[0] = "changed"
+	})
+
+	assert.Equal(t, 1, v.Routes[0].Weights[0])
+	assert.Equal(t, "/b", v.Routes[0].Next.Path)
+	assert.Equal(t, "h", v.hidden[0])
+	assert.Equal(t, "x", v.Extra)
+}
+
+func TestReflectGuardReusesDestination(t *testing.T) {
+	var g ReflectGuard[table]
+	g.Store(table{Routes: []route{{Path: "/a", Weights: []int{1}, Next: &route{}}}})
+
+	var dst table
+	g.LoadInto(&dst)
+	allocs := testing.AllocsPerRun(100, func() { g.LoadInto(&dst) })
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestReflectGuardWithMapsHoldsOffWriters(t *testing.T) {
+	var g ReflectGuard[map[string][]int]
+	g.Store(map[string][]int{"a": {1}})
+
+	v := g.Load()
+	g.Update(func(m *map[string][]int) { (*m)["a"][0] = 2 })
+
+	assert.Equal(t, []int{1}, v["a"])
+	assert.True(t, planFor(typeOf[map[string][]int]()).exclusive)
+}
+
+func TestReflectGuardRecursiveType(t *testing.T) {
+	var g ReflectGuard[route]
+	g.Store(route{Path: "/1", Next: &route{Path: "/2", Next: &route{Path: "/3"}}})
+
+	v := g.Load()
+
+	assert.Equal(t, "/3", v.Next.Next.Path)
+}
+
+func TestReflectGuardNeverTorn(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 20
+	}
+	var g ReflectGuard[table]
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			g.Update(func(t *table) {
+				t.Version = i
+				t.Routes = make([]route, i%7)
+				for j := range t.Routes {
+					t.Routes[j].Weights = []int{i, i}
+				}
+			})
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dst table
+			for i := 0; i < n; i++ {
+				g.LoadInto(&dst)
+				if len(dst.Routes) != dst.Version%7 {
+					panic("torn routes")
+				}
+				for _, r := range dst.Routes {
+					if r.Weights[0] != dst.Version || r.Weights[1] != dst.Version {
+						panic("torn weights")
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,124 @@
+package seqmut
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// StalePolicy says what RefreshingValue.Get does while another goroutine is
+// refreshing a stale value.
+type StalePolicy int
+
+const (
+	// Return the stale value immediately
+	ServeStale StalePolicy = iota
+	// Wait for the refresh to finish
+	WaitForRefresh
+)
+
+type refreshEntry[T any] struct {
+	value   T
+	version uint64
+	expires time.Time
+	valid   bool
+}
+
+// refresh marks a refresh in progress for the entry at version.
+type refresh struct {
+	version uint64
+	done    chan struct{}
+	err     error
+}
+
+// RefreshingValue is a cached value that is fetched again once it goes stale,
+// without a stampede: exactly one goroutine refreshes, while the others serve
+// the stale value or wait, according to the policy. The value is guarded by
+// a Protected, so fresh reads are optimistic, and each refresh is tied to the
+// version it replaces, so a slow refresher can never overwrite a newer value
+// or be joined by goroutines that read an older one.
+type RefreshingValue[T any] struct {
+	entry    Protected[refreshEntry[T]]
+	inflight atomic.Pointer[refresh]
+	ttl      time.Duration
+	policy   StalePolicy
+	fetch    func() (T, error)
+}
+
+// NewRefreshingValue returns a value fetched by fetch on first use, and again
+// whenever it is older than ttl.
+func NewRefreshingValue[T any](ttl time.Duration, policy StalePolicy, fetch func() (T, error)) *RefreshingValue[T] {
+	return &RefreshingValue[T]{ttl: ttl, policy: policy, fetch: fetch}
+}
+
+// Get returns the value, refreshing it if it is stale. It returns the fetch
+// error of a refresh it ran or waited for, unless a stale value could be
+// served instead.
+func (r *RefreshingValue[T]) Get() (T, error) {
+	for {
+		e := r.entry.Load()
+		if e.valid && time.Now().Before(e.expires) {
+			return e.value, nil
+		}
+
+		mine := &refresh{version: e.version, done: make(chan struct{})}
+		if r.inflight.CompareAndSwap(nil, mine) {
+			return r.refresh(mine, e)
+		}
+
+		f := r.inflight.Load()
+		if f == nil {
+			// A refresh finished between our read and our claim
+			continue
+		}
+		if e.valid && r.policy == ServeStale {
+			return e.value, nil
+		}
+		<-f.done
+		if f.version != e.version {
+			// The refresh was of an older version, which Invalidate replaced
+			// while it was fetching, so its result was not stored
+			continue
+		}
+		if f.err != nil {
+			if e.valid {
+				return e.value, nil
+			}
+			return e.value, f.err
+		}
+	}
+}
+
+func (r *RefreshingValue[T]) refresh(f *refresh, stale refreshEntry[T]) (T, error) {
+	defer close(f.done)
+	defer r.inflight.Store(nil)
+
+	if current := r.entry.Load(); current.version != stale.version {
+		// Someone refreshed between our read and our claim
+		return current.value, nil
+	}
+	v, err := r.fetch()
+	if err != nil {
+		f.err = err
+		if stale.valid {
+			return stale.value, err
+		}
+		return v, err
+	}
+	r.entry.Update(func(e *refreshEntry[T]) {
+		// Unless invalidated while fetching, the value may predate that
+		if e.version == stale.version {
+			*e = refreshEntry[T]{value: v, version: stale.version + 1, expires: time.Now().Add(r.ttl), valid: true}
+		}
+	})
+	return v, nil
+}
+
+// Invalidate marks the value stale, so the next Get refreshes it. A refresh
+// already in flight does not store its result, since it may have been
+// fetched before whatever prompted the invalidation.
+func (r *RefreshingValue[T]) Invalidate() {
+	r.entry.Update(func(e *refreshEntry[T]) {
+		e.version++
+		e.expires = time.Time{}
+	})
+}
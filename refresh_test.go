@@ -0,0 +1,103 @@
+package seqmut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshingValueFetchesOnce(t *testing.T) {
+	var fetches int32
+	release := make(chan struct{})
+	r := NewRefreshingValue(time.Hour, WaitForRefresh, func() (int, error) {
+		<-release
+		return int(atomic.AddInt32(&fetches, 1)), nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := r.Get()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, v)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), fetches)
+}
+
+func TestRefreshingValueServesStaleDuringRefresh(t *testing.T) {
+	n := 0
+	started, release := make(chan struct{}), make(chan struct{})
+	r := NewRefreshingValue(time.Hour, ServeStale, func() (int, error) {
+		n++
+		if n == 2 {
+			close(started)
+			<-release
+		}
+		return n, nil
+	})
+	v, _ := r.Get()
+	assert.Equal(t, 1, v)
+	r.Invalidate()
+
+	done := make(chan int)
+	go func() {
+		v, _ := r.Get()
+		done <- v
+	}()
+	<-started
+	v, err := r.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	close(release)
+	assert.Equal(t, 2, <-done)
+	v, _ = r.Get()
+	assert.Equal(t, 2, v)
+}
+
+func TestRefreshingValueInvalidateDuringRefresh(t *testing.T) {
+	n := 0
+	started, release := make(chan struct{}), make(chan struct{})
+	r := NewRefreshingValue(time.Hour, WaitForRefresh, func() (int, error) {
+		n++
+		if n == 1 {
+			close(started)
+			<-release
+		}
+		return n, nil
+	})
+
+	done := make(chan int)
+	go func() {
+		v, _ := r.Get()
+		done <- v
+	}()
+	<-started
+	r.Invalidate()
+	close(release)
+	assert.Equal(t, 1, <-done)
+
+	// The in-flight result was not stored, so the invalidation still holds
+	v, err := r.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestRefreshingValueError(t *testing.T) {
+	fail := errors.New("down")
+	r := NewRefreshingValue(time.Hour, WaitForRefresh, func() (int, error) { return 0, fail })
+
+	_, err := r.Get()
+
+	assert.Equal(t, fail, err)
+}
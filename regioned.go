@@ -0,0 +1,66 @@
+package seqmut
+
+import "fmt"
+
+// Regioned is like Protected, but the guarded value is divided into named
+// regions, registered at construction, each with its own sequence. Readers
+// say which regions they read and writers which regions they write, so a
+// reader of one field doesn't retry when only another field changed.
+//
+// Declaring the regions correctly is up to the caller: a write that touches a
+// region it did not declare can tear readers of that region.
+type Regioned[T any] struct {
+	c       CategoryRWMutex
+	value   T
+	regions map[string]CategoryMask
+}
+
+// NewRegioned returns a Regioned value with the given regions. At most
+// MaxCategories regions can be registered.
+func NewRegioned[T any](initial T, regions ...string) *Regioned[T] {
+	if len(regions) > MaxCategories {
+		panic(fmt.Sprintf("seqmut: %d regions registered, at most %d supported", len(regions), MaxCategories))
+	}
+	r := &Regioned[T]{value: initial, regions: make(map[string]CategoryMask, len(regions))}
+	for i, name := range regions {
+		if _, dup := r.regions[name]; dup {
+			panic(fmt.Sprintf("seqmut: region %q registered twice", name))
+		}
+		r.regions[name] = 1 << i
+	}
+	return r
+}
+
+// Regions returns the mask for the named regions, for use with Read and
+// Update. Look masks up once and keep them; this is a map lookup per name.
+func (r *Regioned[T]) Regions(names ...string) CategoryMask {
+	var mask CategoryMask
+	for _, name := range names {
+		m, ok := r.regions[name]
+		if !ok {
+			panic(fmt.Sprintf("seqmut: unknown region %q", name))
+		}
+		mask |= m
+	}
+	return mask
+}
+
+// Read runs fn as an optimistic critical section over the regions in mask,
+// see Protected.Read. fn must only look at those regions.
+func (r *Regioned[T]) Read(mask CategoryMask, fn func(v *T)) {
+	stamp := r.c.RStamp(mask)
+	for {
+		fn(&r.value)
+		if r.c.Ok(stamp) {
+			return
+		}
+	}
+}
+
+// Update runs fn with exclusive access to the value. fn must only modify the
+// regions in mask.
+func (r *Regioned[T]) Update(mask CategoryMask, fn func(v *T)) {
+	r.c.Lock(mask)
+	defer r.c.Unlock()
+	fn(&r.value)
+}
@@ -0,0 +1,41 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type server struct {
+	Routes   []string
+	Requests int
+}
+
+func TestRegionedReadAndUpdate(t *testing.T) {
+	r := NewRegioned(server{}, "routes", "stats")
+	routes, stats := r.Regions("routes"), r.Regions("stats")
+
+	r.Update(routes, func(s *server) { s.Routes = []string{"/a"} })
+	r.Update(stats, func(s *server) { s.Requests++ })
+
+	var got []string
+	r.Read(routes, func(s *server) { got = s.Routes })
+	assert.Equal(t, []string{"/a"}, got)
+}
+
+func TestRegionedWriteToOtherRegionDoesNotInvalidate(t *testing.T) {
+	r := NewRegioned(server{}, "routes", "stats")
+	routes, stats := r.Regions("routes"), r.Regions("stats")
+
+	stamp := r.c.RStamp(routes)
+	r.Update(stats, func(s *server) { s.Requests++ })
+	assert.True(t, r.c.Ok(stamp))
+
+	r.Update(r.Regions("routes", "stats"), func(s *server) {})
+	assert.False(t, r.c.Ok(stamp))
+}
+
+func TestRegionedRegistrationErrors(t *testing.T) {
+	assert.Panics(t, func() { NewRegioned(0, "a", "a") })
+	assert.Panics(t, func() { NewRegioned(0, "a", "b", "c", "d", "e", "f", "g", "h", "i") })
+	assert.Panics(t, func() { NewRegioned(0, "a").Regions("b") })
+}
@@ -0,0 +1,132 @@
+package seqmut
+
+import (
+	"iter"
+	"runtime"
+	"sort"
+	"sync"
+	"weak"
+)
+
+// Registry tracks named locks for diagnostics: status pages, metrics
+// collectors and watchdogs can enumerate it. It only holds weak references,
+// so registering a lock does not keep it alive; a lock that is garbage
+// collected drops out of the registry on its own.
+//
+// The zero value is ready to use. Most programs use DefaultRegistry.
+type Registry struct {
+	mu    sync.Mutex
+	locks map[string]weak.Pointer[RWMutex]
+}
+
+// DefaultRegistry is the registry used by Register, Statuses and WithName.
+var DefaultRegistry = &Registry{}
+
+type registration struct {
+	name string
+	ptr  weak.Pointer[RWMutex]
+}
+
+// Register makes rw visible under name. Registering a name again replaces the
+// earlier lock. The returned function removes the registration.
+func (r *Registry) Register(name string, rw *RWMutex) (unregister func()) {
+	ptr := weak.Make(rw)
+	r.mu.Lock()
+	if r.locks == nil {
+		r.locks = map[string]weak.Pointer[RWMutex]{}
+	}
+	r.locks[name] = ptr
+	r.mu.Unlock()
+
+	reg := registration{name, ptr}
+	cleanup := runtime.AddCleanup(rw, r.remove, reg)
+	return func() {
+		cleanup.Stop()
+		r.remove(reg)
+	}
+}
+
+func (r *Registry) remove(reg registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.locks[reg.name] == reg.ptr {
+		delete(r.locks, reg.name)
+	}
+}
+
+// All iterates over the live registered locks, ordered by name.
+func (r *Registry) All() iter.Seq2[string, *RWMutex] {
+	return func(yield func(string, *RWMutex) bool) {
+		r.mu.Lock()
+		names := make([]string, 0, len(r.locks))
+		locks := make(map[string]*RWMutex, len(r.locks))
+		for name, ptr := range r.locks {
+			if rw := ptr.Value(); rw != nil {
+				names = append(names, name)
+				locks[name] = rw
+			}
+		}
+		r.mu.Unlock()
+		sort.Strings(names)
+		for _, name := range names {
+			if !yield(name, locks[name]) {
+				return
+			}
+		}
+	}
+}
+
+// Statuses returns the status of every live registered lock, ordered by name.
+func (r *Registry) Statuses() []LockStatus {
+	var statuses []LockStatus
+	for name, rw := range r.All() {
+		s := rw.Status()
+		s.Name = name
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// Register registers rw under name in DefaultRegistry, see Registry.Register.
+func Register(name string, rw *RWMutex) (unregister func()) {
+	return DefaultRegistry.Register(name, rw)
+}
+
+// Statuses returns the statuses of the locks in DefaultRegistry.
+func Statuses() []LockStatus {
+	return DefaultRegistry.Statuses()
+}
+
+// Option configures an RWMutex created by New.
+type Option func(*options)
+
+type options struct {
+	name     string
+	registry *Registry
+}
+
+// WithName registers the lock under name, in DefaultRegistry unless
+// WithRegistry says otherwise.
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// WithRegistry makes WithName register the lock in r instead of
+// DefaultRegistry.
+func WithRegistry(r *Registry) Option {
+	return func(o *options) { o.registry = r }
+}
+
+// New returns an RWMutex configured by opts. Without options it is
+// equivalent to the zero value.
+func New(opts ...Option) *RWMutex {
+	o := options{registry: DefaultRegistry}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	rw := &RWMutex{}
+	if o.name != "" {
+		o.registry.Register(o.name, rw)
+	}
+	return rw
+}
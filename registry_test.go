@@ -0,0 +1,60 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	var a, b RWMutex
+	unregisterB := Register("b", &b)
+	unregisterA := Register("a", &a)
+	a.Lock()
+	a.Unlock()
+
+	statuses := Statuses()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "a", statuses[0].Name)
+	assert.Equal(t, Stamp(2), statuses[0].Sequence)
+	assert.Equal(t, "b", statuses[1].Name)
+
+	unregisterA()
+	unregisterB()
+	assert.Empty(t, Statuses())
+	runtime.KeepAlive(&a)
+	runtime.KeepAlive(&b)
+}
+
+func TestNewWithNameInUserRegistry(t *testing.T) {
+	var r Registry
+	rw := New(WithName("config"), WithRegistry(&r))
+
+	var names []string
+	for name, l := range r.All() {
+		names = append(names, name)
+		assert.True(t, l == rw)
+	}
+
+	assert.Equal(t, []string{"config"}, names)
+	assert.Empty(t, Statuses())
+	runtime.KeepAlive(rw)
+}
+
+func TestRegistryDoesNotKeepLocksAlive(t *testing.T) {
+	var r Registry
+	New(WithName("garbage"), WithRegistry(&r))
+
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+		r.mu.Lock()
+		n := len(r.locks)
+		r.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("collected lock still registered")
+}
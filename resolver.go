@@ -0,0 +1,171 @@
+package seqmut
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// ResolveFunc resolves host, returning its addresses and how long they may
+// be cached for.
+type ResolveFunc func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error)
+
+// ResolverCache caches name resolutions. Hits are optimistic reads and never
+// lock. Misses and refreshes of expired hosts are deduplicated the same way
+// as in TokenCache: every caller that noticed races to LockIfUnchanged on the
+// version of the host's entry it read, and exactly one wins and resolves.
+// The rest keep serving the expired addresses while it does, or wait for it
+// if there are none yet. Expired entries are removed in batches by Sweep, or
+// by the sweeper started with StartSweeper.
+type ResolverCache struct {
+	entries SeqMap[string, *hostEntry]
+	resolve ResolveFunc
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+type hostEntry struct {
+	refresh refresher
+	addrs   []netip.Addr
+	expires time.Time
+	// False until the first resolution succeeds
+	valid bool
+}
+
+// expired reports whether a sweep may remove the entry: it has addresses,
+// they have expired, and nobody is refreshing them.
+func (e *hostEntry) expired(now time.Time) bool {
+	var valid, resolving bool
+	var expires time.Time
+	stamp := e.refresh.rw.RStamp()
+	for {
+		valid, resolving, expires = e.valid, e.refresh.refreshing != nil, e.expires
+		if e.refresh.rw.Ok(stamp) {
+			return valid && !resolving && !now.Before(expires)
+		}
+	}
+}
+
+// NewResolverCache returns an empty cache resolving misses with resolve.
+func NewResolverCache(resolve ResolveFunc) *ResolverCache {
+	return &ResolverCache{resolve: resolve}
+}
+
+// Lookup returns the addresses of host, resolving them if they are not
+// cached. If they have expired and another goroutine is already resolving
+// host again, the expired addresses are returned rather than waiting. The
+// returned slice is shared and must not be modified.
+//
+// A resolution is shared, so it is not cancelled when the caller running it
+// gives up; the resolver should apply its own timeout. If it fails, expired
+// addresses are returned along with the error, and an entry that never
+// resolved is dropped. A resolver that panics fails the resolution for
+// everyone waiting on it, rather than leaving them waiting forever.
+func (c *ResolverCache) Lookup(ctx context.Context, host string) ([]netip.Addr, error) {
+	e, ok := c.entries.Load(host)
+	if !ok {
+		e, _ = c.entries.LoadOrStore(host, &hostEntry{})
+	}
+	var addrs, resolved []netip.Addr
+	var ttl time.Duration
+	dropped := false
+	// Even if the resolver panics
+	defer func() {
+		if dropped {
+			c.drop(host, e)
+		}
+	}()
+	err := e.refresh.get(ctx,
+		func() (fresh, usable bool) {
+			addrs = e.addrs
+			return e.valid && time.Now().Before(e.expires), e.valid
+		},
+		func(ctx context.Context) (err error) {
+			resolved, ttl, err = c.resolve(ctx, host)
+			return err
+		},
+		func(err error) {
+			if err == nil {
+				e.addrs, e.expires, e.valid = resolved, time.Now().Add(ttl), true
+			}
+			addrs, dropped = e.addrs, !e.valid
+		})
+	return addrs, err
+}
+
+// drop removes e from the cache, unless host has been given a new entry.
+func (c *ResolverCache) drop(host string, e *hostEntry) {
+	c.entries.Apply(func(view MutableView[string, *hostEntry]) {
+		if current, ok := view.Load(host); ok && current == e {
+			view.Delete(host)
+		}
+	})
+}
+
+// Forget removes host from the cache.
+func (c *ResolverCache) Forget(host string) {
+	c.entries.Delete(host)
+}
+
+// Len returns the number of cached hosts, including expired ones not yet
+// swept.
+func (c *ResolverCache) Len() int {
+	return c.entries.Len()
+}
+
+// Sweep removes expired entries, returning how many it removed. Entries being
+// refreshed are left alone.
+func (c *ResolverCache) Sweep() int {
+	now := time.Now()
+	var expired []string
+	for host, e := range c.entries.All() {
+		if e.expired(now) {
+			expired = append(expired, host)
+		}
+	}
+	removed := 0
+	for len(expired) > 0 {
+		batch := expired[:min(len(expired), sweepBatch)]
+		expired = expired[len(batch):]
+		c.entries.Apply(func(view MutableView[string, *hostEntry]) {
+			for _, host := range batch {
+				// Check again, the entry may have been refreshed since the scan
+				if e, ok := view.Load(host); ok && e.expired(now) {
+					view.Delete(host)
+					removed++
+				}
+			}
+		})
+	}
+	return removed
+}
+
+// StartSweeper starts a goroutine calling Sweep every interval, until Close.
+func (c *ResolverCache) StartSweeper(interval time.Duration) {
+	c.stop = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.Sweep()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the sweeper, if one was started, and waits for it to exit.
+func (c *ResolverCache) Close() {
+	if c.stop != nil {
+		close(c.stop)
+		c.wg.Wait()
+		c.stop = nil
+	}
+}
@@ -0,0 +1,173 @@
+package seqmut
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResolverCacheDeduplicatesMisses(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	addr := netip.MustParseAddr("192.0.2.1")
+	c := NewResolverCache(func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error) {
+		calls.Add(1)
+		<-release
+		return []netip.Addr{addr}, time.Minute, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addrs, err := c.Lookup(context.Background(), "example.com")
+			assert.NoError(t, err)
+			assert.Equal(t, []netip.Addr{addr}, addrs)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), calls.Load())
+	_, err := c.Lookup(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestResolverCacheExpiryAndErrors(t *testing.T) {
+	fail := errors.New("nxdomain")
+	var calls atomic.Int64
+	c := NewResolverCache(func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error) {
+		calls.Add(1)
+		if host == "missing.example" {
+			return nil, 0, fail
+		}
+		return []netip.Addr{netip.MustParseAddr("192.0.2.1")}, time.Millisecond, nil
+	})
+
+	_, err := c.Lookup(context.Background(), "missing.example")
+	assert.Equal(t, fail, err)
+	assert.Equal(t, 0, c.Len())
+
+	c.Lookup(context.Background(), "short.example")
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, 1, c.Sweep())
+	c.Lookup(context.Background(), "short.example")
+	assert.Equal(t, int64(3), calls.Load())
+}
+
+func TestResolverCacheServesExpiredWhileRefreshing(t *testing.T) {
+	old, fresh := netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("192.0.2.2")
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int64
+	c := NewResolverCache(func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error) {
+		if calls.Add(1) == 1 {
+			return []netip.Addr{old}, time.Millisecond, nil
+		}
+		close(started)
+		<-release
+		return []netip.Addr{fresh}, time.Minute, nil
+	})
+	c.Lookup(context.Background(), "example.com")
+	time.Sleep(5 * time.Millisecond)
+
+	done := make(chan []netip.Addr)
+	go func() {
+		addrs, _ := c.Lookup(context.Background(), "example.com")
+		done <- addrs
+	}()
+	<-started
+	addrs, err := c.Lookup(context.Background(), "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Addr{old}, addrs)
+
+	close(release)
+	assert.Equal(t, []netip.Addr{fresh}, <-done)
+	assert.Equal(t, int64(2), calls.Load())
+}
+
+func TestResolverCachePanickingResolverReleasesWaiters(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int64
+	c := NewResolverCache(func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+			panic("resolver bug")
+		}
+		return []netip.Addr{netip.MustParseAddr("192.0.2.1")}, time.Minute, nil
+	})
+
+	go func() {
+		defer func() { recover() }()
+		c.Lookup(context.Background(), "example.com")
+	}()
+	<-started
+	waiter := make(chan error)
+	go func() {
+		_, err := c.Lookup(context.Background(), "example.com")
+		waiter <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, errRefreshPanicked, <-waiter)
+	_, err := c.Lookup(context.Background(), "example.com")
+	assert.NoError(t, err)
+}
+
+func TestResolverCacheWaiterHonoursContext(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	c := NewResolverCache(func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error) {
+		close(started)
+		<-release
+		return nil, 0, errors.New("unreachable")
+	})
+	go c.Lookup(context.Background(), "example.com")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := c.Lookup(ctx, "example.com")
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestResolverCacheResolutionOutlivesCancelledCaller(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	addr := netip.MustParseAddr("192.0.2.1")
+	c := NewResolverCache(func(ctx context.Context, host string) ([]netip.Addr, time.Duration, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		return []netip.Addr{addr}, time.Minute, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := c.Lookup(ctx, "example.com")
+		first <- err
+	}()
+	<-started
+	waiter := make(chan []netip.Addr)
+	go func() {
+		addrs, _ := c.Lookup(context.Background(), "example.com")
+		waiter <- addrs
+	}()
+	cancel()
+	close(release)
+
+	assert.NoError(t, <-first)
+	assert.Equal(t, []netip.Addr{addr}, <-waiter)
+}
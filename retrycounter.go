@@ -0,0 +1,53 @@
+package seqmut
+
+import "sync/atomic"
+
+// RetryCounter counts how many times optimistic reads had to be retried.
+// Point ReadCounted or Protected.CountRetries at one inside a benchmark and
+// publish the rate with Report, so performance CI can track validation
+// failures alongside ns/op. A nil *RetryCounter counts nothing.
+type RetryCounter struct {
+	n uint64
+}
+
+// MetricReporter is satisfied by *testing.B.
+type MetricReporter interface {
+	ReportMetric(n float64, unit string)
+}
+
+func (c *RetryCounter) add() {
+	if c != nil {
+		atomic.AddUint64(&c.n, 1)
+	}
+}
+
+// Load returns the number of retries counted so far.
+func (c *RetryCounter) Load() uint64 {
+	return atomic.LoadUint64(&c.n)
+}
+
+// Reset sets the count back to zero.
+func (c *RetryCounter) Reset() {
+	atomic.StoreUint64(&c.n, 0)
+}
+
+// Report publishes the count divided by ops as "retries/op", typically with
+// b.N as ops.
+func (c *RetryCounter) Report(r MetricReporter, ops int) {
+	if ops <= 0 {
+		return
+	}
+	r.ReportMetric(float64(c.Load())/float64(ops), "retries/op")
+}
+
+// ReadCounted is Read, counting every retry in c.
+func ReadCounted(l OptimisticLocker, c *RetryCounter, fn func()) {
+	stamp := l.RStamp()
+	for {
+		fn()
+		if l.Ok(stamp) {
+			return
+		}
+		c.add()
+	}
+}
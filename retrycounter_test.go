@@ -0,0 +1,82 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type metrics map[string]float64
+
+func (m metrics) ReportMetric(n float64, unit string) {
+	m[unit] = n
+}
+
+func TestReadCountedCountsRetries(t *testing.T) {
+	var rw RWMutex
+	var c RetryCounter
+	attempts := 0
+
+	ReadCounted(&rw, &c, func() {
+		attempts++
+		if attempts < 3 {
+			rw.Lock()
+			rw.Unlock()
+		}
+	})
+
+	assert.Equal(t, uint64(2), c.Load())
+	m := metrics{}
+	c.Report(m, 4)
+	assert.Equal(t, 0.5, m["retries/op"])
+	c.Reset()
+	assert.Equal(t, uint64(0), c.Load())
+}
+
+func TestProtectedCountRetries(t *testing.T) {
+	p := NewProtected(1)
+	var c RetryCounter
+	p.CountRetries(&c)
+	attempts := 0
+
+	p.Read(func(v *int) {
+		attempts++
+		if attempts == 1 {
+			p.Store(2)
+		}
+	})
+	assert.Equal(t, uint64(1), c.Load())
+
+	p.CountRetries(nil)
+	attempts = 0
+	p.Read(func(v *int) {
+		attempts++
+		if attempts == 1 {
+			p.Store(3)
+		}
+	})
+	assert.Equal(t, uint64(1), c.Load())
+}
+
+func BenchmarkProtectedLoadRetries(b *testing.B) {
+	p := NewProtected([8]int{})
+	var c RetryCounter
+	p.CountRetries(&c)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				p.Update(func(v *[8]int) { v[0]++ })
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Load()
+	}
+	c.Report(b, b.N)
+}
@@ -0,0 +1,26 @@
+package seqmut
+
+import "sync"
+
+var _ sync.Locker = (*RWMutex)(nil)
+
+type rlocker RWMutex
+
+// RLocker returns a sync.Locker whose Lock and Unlock take a pessimistic read
+// lock, for APIs such as sync.Cond that need a Locker rather than a retry
+// loop. A pessimistic reader excludes writers but does not bump the sequence,
+// so it does not disturb optimistic readers.
+//
+// Pessimistic readers share the writers' mutex, which means they also
+// exclude each other. Prefer the optimistic path wherever it can be used.
+func (rw *RWMutex) RLocker() sync.Locker {
+	return (*rlocker)(rw)
+}
+
+func (r *rlocker) Lock() {
+	(*RWMutex)(r).acquire()
+}
+
+func (r *rlocker) Unlock() {
+	(*RWMutex)(r).release()
+}
@@ -0,0 +1,59 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRLockerExcludesWriters(t *testing.T) {
+	var rw RWMutex
+	rl := rw.RLocker()
+
+	rl.Lock()
+	acquired := make(chan bool)
+	go func() {
+		rw.Lock()
+		acquired <- true
+		rw.Unlock()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("writer acquired lock while read locker was held")
+	case <-time.After(10 * time.Millisecond):
+	}
+	rl.Unlock()
+	<-acquired
+}
+
+func TestRLockerDoesNotInvalidateOptimisticReaders(t *testing.T) {
+	var rw RWMutex
+	rl := rw.RLocker()
+
+	stamp := rw.RStamp()
+	rl.Lock()
+	rl.Unlock()
+
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestRWMutexWorksWithSyncCond(t *testing.T) {
+	var rw RWMutex
+	cond := sync.NewCond(rw.RLocker())
+	ready := false
+
+	go func() {
+		rw.Lock()
+		ready = true
+		rw.Unlock()
+		cond.Broadcast()
+	}()
+
+	cond.L.Lock()
+	for !ready {
+		cond.Wait()
+	}
+	cond.L.Unlock()
+}
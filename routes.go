@@ -0,0 +1,170 @@
+package seqmut
+
+import (
+	"net/netip"
+	"sync"
+)
+
+type routeNode[V any] struct {
+	lock     NodeLock
+	value    V
+	hasValue bool
+	// Set once and never changed, as in IntervalTree
+	children [2]*routeNode[V]
+}
+
+// RouteTable maps CIDR prefixes to values and answers longest-prefix-match
+// lookups, for proxies and network daemons that route every packet or
+// request through it. It is a binary trie per address family; lookups descend
+// it with optimistic lock coupling (see NodeLock) and lock nothing, while
+// Insert and Delete only lock the nodes they change.
+//
+// Update applies a batch of changes that lookups see all at once or not at
+// all, at the cost of lookups retrying while a batch is applied.
+//
+// Nodes are never removed; a deleted route leaves its path in the trie.
+//
+// The zero value is an empty table ready to use.
+type RouteTable[V any] struct {
+	// IPv4 and IPv6
+	roots [2]routeNode[V]
+	// Held for writing for the whole of an Update, and validated by lookups
+	batch RWMutex
+	// Serializes Updates
+	batchMu sync.Mutex
+}
+
+// routeKey returns the root and address bytes for addr.
+func (t *RouteTable[V]) routeKey(addr netip.Addr) (*routeNode[V], [16]byte) {
+	if addr.Is4() {
+		var key [16]byte
+		a4 := addr.As4()
+		copy(key[:], a4[:])
+		return &t.roots[0], key
+	}
+	return &t.roots[1], addr.As16()
+}
+
+func keyBit(key [16]byte, i int) int {
+	return int(key[i/8]>>(7-i%8)) & 1
+}
+
+// Insert routes prefix to v, replacing any earlier value for it.
+func (t *RouteTable[V]) Insert(prefix netip.Prefix, v V) {
+	t.set(prefix, v, true)
+}
+
+// Delete removes the route for exactly prefix, returning false if there was
+// none.
+func (t *RouteTable[V]) Delete(prefix netip.Prefix) bool {
+	var zero V
+	return t.set(prefix, zero, false)
+}
+
+// set installs or clears the value at prefix, returning whether there was
+// one before.
+func (t *RouteTable[V]) set(prefix netip.Prefix, v V, install bool) bool {
+	prefix = prefix.Masked()
+	n, key := t.routeKey(prefix.Addr().Unmap())
+	for i := 0; i < prefix.Bits(); i++ {
+		b := keyBit(key, i)
+		// Nodes are never obsolete, so the OrRestart write locks cannot fail
+		n.lock.WriteLockOrRestart()
+		child := n.children[b]
+		if child == nil {
+			if !install {
+				n.lock.WriteUnlock()
+				return false
+			}
+			child = &routeNode[V]{}
+			n.children[b] = child
+		}
+		n.lock.WriteUnlock()
+		n = child
+	}
+	n.lock.WriteLockOrRestart()
+	had := n.hasValue
+	n.value, n.hasValue = v, install
+	n.lock.WriteUnlock()
+	return had
+}
+
+// Lookup returns the value of the longest prefix containing addr, and that
+// prefix.
+func (t *RouteTable[V]) Lookup(addr netip.Addr) (v V, prefix netip.Prefix, ok bool) {
+	addr = addr.Unmap()
+	root, key := t.routeKey(addr)
+	bits := addr.BitLen()
+	var depth int
+	stamp := t.batch.RStamp()
+	for {
+		v, depth, ok = lookupRoute(root, key, bits)
+		if t.batch.Ok(stamp) {
+			break
+		}
+	}
+	if !ok {
+		return v, netip.Prefix{}, false
+	}
+	prefix, _ = addr.Prefix(depth)
+	return v, prefix, true
+}
+
+// lookupRoute is one optimistic descent, restarting from root whenever a
+// node changes underneath it.
+func lookupRoute[V any](root *routeNode[V], key [16]byte, bits int) (best V, depth int, ok bool) {
+restart:
+	for {
+		var zero V
+		best, depth, ok = zero, 0, false
+		n := root
+		version, _ := n.lock.ReadLockOrRestart()
+		for i := 0; ; i++ {
+			if n.hasValue {
+				best, depth, ok = n.value, i, true
+			}
+			var child *routeNode[V]
+			if i < bits {
+				child = n.children[keyBit(key, i)]
+			}
+			if child == nil {
+				if !n.lock.ReadUnlockOrRestart(version) {
+					continue restart
+				}
+				return best, depth, ok
+			}
+			childVersion, _ := child.lock.ReadLockOrRestart()
+			if !n.lock.ReadUnlockOrRestart(version) {
+				continue restart
+			}
+			n, version = child, childVersion
+		}
+	}
+}
+
+// RouteTx is a batch of route changes, see RouteTable.Update.
+type RouteTx[V any] struct {
+	t *RouteTable[V]
+}
+
+// Insert routes prefix to v.
+func (tx RouteTx[V]) Insert(prefix netip.Prefix, v V) {
+	tx.t.set(prefix, v, true)
+}
+
+// Delete removes the route for exactly prefix.
+func (tx RouteTx[V]) Delete(prefix netip.Prefix) bool {
+	var zero V
+	return tx.t.set(prefix, zero, false)
+}
+
+// Update applies the changes fn makes through tx as a single version of the
+// table: no lookup sees some of them without the others, such as a route
+// withdrawn before its replacement was installed.
+func (t *RouteTable[V]) Update(fn func(tx RouteTx[V])) {
+	t.batchMu.Lock()
+	defer t.batchMu.Unlock()
+	t.batch.Lock()
+	defer t.batch.Unlock()
+	fn(RouteTx[V]{t})
+}
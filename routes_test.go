@@ -0,0 +1,83 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"sync"
+	"testing"
+)
+
+func TestRouteTableLongestPrefixMatch(t *testing.T) {
+	var rt RouteTable[string]
+	rt.Insert(netip.MustParsePrefix("0.0.0.0/0"), "default")
+	rt.Insert(netip.MustParsePrefix("10.0.0.0/8"), "ten")
+	rt.Insert(netip.MustParsePrefix("10.1.2.0/24"), "ten-one-two")
+	rt.Insert(netip.MustParsePrefix("2001:db8::/32"), "doc")
+
+	v, prefix, ok := rt.Lookup(netip.MustParseAddr("10.1.2.3"))
+	assert.True(t, ok)
+	assert.Equal(t, "ten-one-two", v)
+	assert.Equal(t, netip.MustParsePrefix("10.1.2.0/24"), prefix)
+
+	v, _, _ = rt.Lookup(netip.MustParseAddr("10.9.9.9"))
+	assert.Equal(t, "ten", v)
+	v, prefix, _ = rt.Lookup(netip.MustParseAddr("192.168.0.1"))
+	assert.Equal(t, "default", v)
+	assert.Equal(t, netip.MustParsePrefix("0.0.0.0/0"), prefix)
+	v, _, _ = rt.Lookup(netip.MustParseAddr("::ffff:10.1.2.3"))
+	assert.Equal(t, "ten-one-two", v)
+
+	v, _, _ = rt.Lookup(netip.MustParseAddr("2001:db8::1"))
+	assert.Equal(t, "doc", v)
+	_, _, ok = rt.Lookup(netip.MustParseAddr("2001:db9::1"))
+	assert.False(t, ok)
+}
+
+func TestRouteTableDelete(t *testing.T) {
+	var rt RouteTable[int]
+	rt.Insert(netip.MustParsePrefix("10.0.0.0/8"), 1)
+	rt.Insert(netip.MustParsePrefix("10.1.0.0/16"), 2)
+
+	assert.True(t, rt.Delete(netip.MustParsePrefix("10.1.0.0/16")))
+	assert.False(t, rt.Delete(netip.MustParsePrefix("10.1.0.0/16")))
+	assert.False(t, rt.Delete(netip.MustParsePrefix("172.16.0.0/12")))
+
+	v, _, _ := rt.Lookup(netip.MustParseAddr("10.1.0.1"))
+	assert.Equal(t, 1, v)
+}
+
+func TestRouteTableUpdateIsAtomic(t *testing.T) {
+	var rt RouteTable[int]
+	a, b := netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("10.0.0.0/16")
+	rt.Insert(a, 0)
+	rt.Insert(b, 0)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Withdraw and reinstall both routes with a new value
+			rt.Update(func(tx RouteTx[int]) {
+				tx.Delete(b)
+				tx.Insert(a, i)
+				tx.Insert(b, i)
+			})
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		inner, prefix, ok := rt.Lookup(netip.MustParseAddr("10.0.0.1"))
+		assert.True(t, ok)
+		assert.Equal(t, b, prefix)
+		outer, _, _ := rt.Lookup(netip.MustParseAddr("10.1.0.1"))
+		assert.True(t, outer >= inner)
+	}
+	close(done)
+	wg.Wait()
+}
@@ -10,11 +10,47 @@ type Stamp uint64
 type RWMutex struct {
 	mut      sync.Mutex
 	sequence uint64
+	// If set, used for mutual exclusion between writers instead of mut
+	locker sync.Locker
+	// Number of writers that have announced they are about to Lock
+	intent int32
+
+	// Readers parked in OkOrPark waiting for the active writer to leave
+	parked int32
+	parkMu sync.Mutex
+	wake   chan struct{}
+
+	// Writes queued by GroupWrite for the current lock holder to apply
+	grouped int32
+	groupMu sync.Mutex
+	group   []*groupWrite
+
+	// Number of times Ok has sent a reader around the loop again. Contended
+	// readers write it, so it must stay at least a cache line away from
+	// sequence, which they poll, and from whatever follows the lock, such as
+	// a Protected value; see TestRetriesHaveTheirOwnCacheLine.
+	retries uint64
+	// Value of retries when the current writer acquired the lock
+	retriesAtLock uint64
+
+	// Set while held via LockNonInvalidating, only tracked in debug builds
+	nonInvalidating bool
+
+	// Thresholds registered with OnSequence, and the sequence when the
+	// current writer acquired the lock
+	watches        []sequenceWatch
+	sequenceAtLock uint64
+
+	// Stack of the current writer, only tracked in debug builds
+	writerStack atomic.Pointer[string]
 }
 
 func (rw *RWMutex) RStamp() *Stamp {
-	stamp := Stamp(atomic.LoadUint64(&rw.sequence))
-	return &stamp
+	stamp := loadStamp(&rw.sequence)
+	if debug {
+		*stamp ^= rw.ownerTag()
+	}
+	return stamp
 }
 
 // Used to end a critical section for the optimistic read lock.
@@ -22,19 +58,36 @@ func (rw *RWMutex) RStamp() *Stamp {
 // about your business. If it returns false, there was a racing writer, and
 // you need to retry; the stamp will have been updated to a new ticket to ride.
 func (rw *RWMutex) Ok(stamp *Stamp) (ok bool) {
-	current := rw.RStamp()
+	if debug {
+		return rw.okOwned(stamp)
+	}
+	if validate(&rw.sequence, stamp) {
+		return true
+	}
+	atomic.AddUint64(&rw.retries, 1)
+	return false
+}
+
+func loadStamp(sequence *uint64) *Stamp {
+	stamp := Stamp(atomic.LoadUint64(sequence))
+	return &stamp
+}
+
+func validate(sequence *uint64, stamp *Stamp) bool {
+	yieldPoint(YieldBeforeValidate)
+	current := Stamp(atomic.LoadUint64(sequence))
 
 	// If a writer was holding the mutex before we showed up, and is *still* holding it
 	// now that we're on our way out the door, the sequence will have remained the same
 	// from our perspective. To guard against this, we guarantee that the sequence is odd
 	// any time a writer is active, so we check that here before doing another fenced read
 	if (*stamp & 1) == 1 {
-		*stamp = *current
+		*stamp = current
 		return false
 	}
 
-	if *current != *stamp {
-		*stamp = *current
+	if current != *stamp {
+		*stamp = current
 		return false
 	}
 
@@ -42,11 +95,50 @@ func (rw *RWMutex) Ok(stamp *Stamp) (ok bool) {
 }
 
 func (rw *RWMutex) Lock() {
-	rw.mut.Lock()
-	atomic.AddUint64(&rw.sequence, 1)
+	rw.acquire()
+	rw.enter()
+}
+
+// enter starts the write critical section; the mutex must already be held.
+func (rw *RWMutex) enter() {
+	rw.sequenceAtLock = atomic.AddUint64(&rw.sequence, 1) - 1
+	yieldPoint(YieldAfterWriteBegin)
+	rw.retriesAtLock = atomic.LoadUint64(&rw.retries)
+	if debug {
+		rw.recordWriterStack()
+	}
 }
 
 func (rw *RWMutex) Unlock() {
-	atomic.AddUint64(&rw.sequence, 1)
+	if debug && rw.nonInvalidating {
+		panic("seqmut: Unlock of a lock taken with LockNonInvalidating")
+	}
+	rw.drainGroup()
+	if debug {
+		rw.writerStack.Store(nil)
+	}
+	yieldPoint(YieldBeforeWriteEnd)
+	seq := atomic.AddUint64(&rw.sequence, 1)
+	rw.wakeParked()
+	watches, from := rw.watches, rw.sequenceAtLock
+	rw.release()
+	if watches != nil {
+		notifyCrossings(watches, from, seq)
+	}
+}
+
+func (rw *RWMutex) acquire() {
+	if rw.locker != nil {
+		rw.locker.Lock()
+		return
+	}
+	rw.mut.Lock()
+}
+
+func (rw *RWMutex) release() {
+	if rw.locker != nil {
+		rw.locker.Unlock()
+		return
+	}
 	rw.mut.Unlock()
 }
@@ -0,0 +1,131 @@
+package seqmut
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrOutOfRange is returned by SeqBytes.WriteAt for writes past the end of
+// the buffer.
+var ErrOutOfRange = errors.New("seqmut: write out of range")
+
+// SeqBytes is a large byte buffer with consistent snapshots that still
+// complete under write pressure. A single copy-and-validate of a multi-MB
+// buffer almost always loses to some concurrent writer, so SeqBytes keeps a
+// sequence per chunk: writers only invalidate the chunks they touch, and a
+// snapshot re-copies just the chunks that changed while it was copying.
+type SeqBytes struct {
+	mu        sync.Mutex
+	buf       []byte
+	chunkSize int
+	sequences []uint64
+}
+
+// NewSeqBytes returns a zeroed buffer of size bytes, validated in chunks of
+// chunkSize bytes.
+func NewSeqBytes(size, chunkSize int) *SeqBytes {
+	if chunkSize < 1 {
+		panic("seqmut: chunk size must be positive")
+	}
+	return &SeqBytes{
+		buf:       make([]byte, size),
+		chunkSize: chunkSize,
+		sequences: make([]uint64, (size+chunkSize-1)/chunkSize),
+	}
+}
+
+// Len returns the size of the buffer.
+func (b *SeqBytes) Len() int {
+	return len(b.buf)
+}
+
+// WriteAt writes p at offset off, as a single write: a snapshot sees either
+// none or all of it. It implements io.WriterAt.
+func (b *SeqBytes) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off > int64(len(b.buf)) || int64(len(p)) > int64(len(b.buf))-off {
+		return 0, ErrOutOfRange
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b.update(int(off), int(off)+len(p), func(buf []byte) { copy(buf[off:], p) })
+	return len(p), nil
+}
+
+// Update runs fn with exclusive access to the whole buffer. Every chunk is
+// invalidated, so prefer WriteAt for small changes.
+func (b *SeqBytes) Update(fn func(buf []byte)) {
+	b.update(0, len(b.buf), fn)
+}
+
+// update runs fn with the chunks covering [lo, hi) marked as being written.
+// All of them go odd before any byte changes, and only go even again once
+// fn is done, which is what makes multi-chunk writes atomic to snapshots.
+func (b *SeqBytes) update(lo, hi int, fn func(buf []byte)) {
+	first, last := lo/b.chunkSize, (hi-1)/b.chunkSize
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := first; i <= last; i++ {
+		atomic.AddUint64(&b.sequences[i], 1)
+	}
+	fn(b.buf)
+	for i := first; i <= last; i++ {
+		atomic.AddUint64(&b.sequences[i], 1)
+	}
+}
+
+// SnapshotInto copies a consistent snapshot of the buffer into dst, which
+// must be at least Len bytes, and returns the number of bytes copied.
+//
+// Every chunk is copied once, then all of them are checked; chunks a writer
+// touched in the meantime are copied again, until a check finds none. At
+// that point no chunk has changed since it was copied, so together they form
+// the buffer as it was between the last copy and the first check.
+func (b *SeqBytes) SnapshotInto(dst []byte) int {
+	dst = dst[:len(b.buf)]
+	stamps := make([]uint64, len(b.sequences))
+	stale := make([]int, len(b.sequences))
+	for i := range stale {
+		stale[i] = i
+	}
+	for len(stale) > 0 {
+		for _, i := range stale {
+			b.copyChunk(dst, i, &stamps[i])
+		}
+		stale = stale[:0]
+		for i := range b.sequences {
+			if atomic.LoadUint64(&b.sequences[i]) != stamps[i] {
+				stale = append(stale, i)
+			}
+		}
+	}
+	return len(dst)
+}
+
+// copyChunk copies chunk i, retrying until the copy is not torn, and records
+// the sequence it is valid for.
+func (b *SeqBytes) copyChunk(dst []byte, i int, stamp *uint64) {
+	lo := i * b.chunkSize
+	hi := min(lo+b.chunkSize, len(b.buf))
+	for {
+		seq := atomic.LoadUint64(&b.sequences[i])
+		if seq&1 == 1 {
+			runtime.Gosched()
+			continue
+		}
+		_, ok := copyValidated(dst[lo:hi], b.buf[lo:hi], &b.sequences[i], Stamp(seq))
+		if ok && atomic.LoadUint64(&b.sequences[i]) == seq {
+			*stamp = seq
+			return
+		}
+	}
+}
+
+// Snapshot returns a consistent copy of the buffer, see SnapshotInto.
+func (b *SeqBytes) Snapshot() []byte {
+	dst := make([]byte, len(b.buf))
+	b.SnapshotInto(dst)
+	return dst
+}
@@ -0,0 +1,52 @@
+package seqmut
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestSeqBytesWriteAt(t *testing.T) {
+	b := NewSeqBytes(10, 4)
+
+	n, err := b.WriteAt([]byte("hello"), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	_, err = b.WriteAt([]byte("hello"), 6)
+	assert.Equal(t, ErrOutOfRange, err)
+
+	assert.Equal(t, []byte("\x00\x00\x00hello\x00\x00"), b.Snapshot())
+}
+
+// Writers fill the whole buffer with one byte value at a time, in writes
+// spanning many chunks; a consistent snapshot is always uniform.
+func TestSeqBytesSnapshotUnderWritePressure(t *testing.T) {
+	b := NewSeqBytes(1<<20, 4096)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for v := byte(1); ; v++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			b.Update(func(buf []byte) {
+				for i := range buf {
+					buf[i] = v
+				}
+			})
+		}
+	}()
+
+	dst := make([]byte, b.Len())
+	for i := 0; i < 20; i++ {
+		b.SnapshotInto(dst)
+		assert.Equal(t, b.Len(), bytes.Count(dst, dst[:1]))
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,118 @@
+package seqmut
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const seqLogSegment = 256
+
+// SeqLog is an in-memory append-only log. Entries are numbered from 0, and the
+// version of the log is the number of entries appended, so "the log as of
+// version v" is entries 0 up to v, which never change once appended. Readers
+// copy ranges of entries with optimistic reads, validated per segment of
+// the log, and tail readers wait for the version to move with Wait.
+//
+// The zero value is an empty log ready to use.
+type SeqLog[T any] struct {
+	// Serializes appends
+	mu sync.Mutex
+	// Published as a whole on every new segment
+	segments atomic.Pointer[[]*logSegment[T]]
+	version  atomic.Uint64
+	// Closed and replaced on every append, see Wait
+	wake atomic.Pointer[chan struct{}]
+}
+
+type logSegment[T any] struct {
+	rw      RWMutex
+	n       int
+	entries [seqLogSegment]T
+}
+
+// Append adds v to the end of the log, returning the new version.
+func (l *SeqLog[T]) Append(v T) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	version := l.version.Load()
+	segments := l.loadSegments()
+	if version%seqLogSegment == 0 {
+		segments = append(segments[:len(segments):len(segments)], &logSegment[T]{})
+		l.segments.Store(&segments)
+	}
+	s := segments[len(segments)-1]
+	s.rw.Lock()
+	s.entries[s.n] = v
+	s.n++
+	s.rw.Unlock()
+	l.version.Store(version + 1)
+	if wake := l.wake.Swap(nil); wake != nil {
+		close(*wake)
+	}
+	return version + 1
+}
+
+// Version returns the number of entries in the log.
+func (l *SeqLog[T]) Version() uint64 {
+	return l.version.Load()
+}
+
+func (l *SeqLog[T]) loadSegments() []*logSegment[T] {
+	if p := l.segments.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Read appends entries from up to, but not including, to to dst. Bounds
+// beyond the current version are clamped, so Read(dst, 0, v) returns the
+// log as of version v, or as much of it as exists.
+func (l *SeqLog[T]) Read(dst []T, from, to uint64) []T {
+	to = min(to, l.Version())
+	segments := l.loadSegments()
+	for from < to {
+		s := segments[from/seqLogSegment]
+		lo := int(from % seqLogSegment)
+		hi := int(min(to-from+uint64(lo), seqLogSegment))
+		n := len(dst)
+		stamp := s.rw.RStamp()
+		for {
+			dst = append(dst[:n], s.entries[lo:min(hi, max(s.n, lo))]...)
+			if s.rw.Ok(stamp) {
+				break
+			}
+		}
+		from += uint64(hi - lo)
+	}
+	return dst
+}
+
+// Wait blocks until the version is past after, returning the new version, or
+// until ctx is done. A tail reader calls it with the version it has read up
+// to, then reads the new entries.
+func (l *SeqLog[T]) Wait(ctx context.Context, after uint64) (uint64, error) {
+	for {
+		if v := l.Version(); v > after {
+			return v, nil
+		}
+		wake := l.wake.Load()
+		if wake == nil {
+			ch := make(chan struct{})
+			if !l.wake.CompareAndSwap(nil, &ch) {
+				continue
+			}
+			wake = &ch
+		}
+		// Appends bump the version before swapping the channel out, so
+		// either this sees the new version or the append closes wake
+		if v := l.Version(); v > after {
+			return v, nil
+		}
+		select {
+		case <-*wake:
+		case <-ctx.Done():
+			return l.Version(), ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package seqmut
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSeqLogReadRanges(t *testing.T) {
+	var l SeqLog[int]
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, uint64(i+1), l.Append(i))
+	}
+
+	entries := l.Read(nil, 250, 520)
+	assert.Len(t, entries, 270)
+	for i, v := range entries {
+		assert.Equal(t, 250+i, v)
+	}
+	assert.Len(t, l.Read(nil, 0, 5000), 1000)
+	assert.Empty(t, l.Read(nil, 1000, 2000))
+	assert.Equal(t, []int{-1, 0, 1}, l.Read([]int{-1}, 0, 2))
+}
+
+func TestSeqLogWait(t *testing.T) {
+	var l SeqLog[string]
+	got := make(chan []string)
+	go func() {
+		var seen []string
+		var version uint64
+		for len(seen) < 3 {
+			v, _ := l.Wait(context.Background(), version)
+			seen = l.Read(seen, version, v)
+			version = v
+		}
+		got <- seen
+	}()
+
+	for _, s := range []string{"a", "b", "c"} {
+		time.Sleep(time.Millisecond)
+		l.Append(s)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, <-got)
+}
+
+func TestSeqLogWaitCancelled(t *testing.T) {
+	var l SeqLog[int]
+	l.Append(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	v, err := l.Wait(ctx, 1)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, uint64(1), v)
+}
@@ -0,0 +1,308 @@
+package seqmut
+
+import (
+	"hash/maphash"
+	"iter"
+	"sync/atomic"
+)
+
+// SeqMap is a hash map with optimistic lookups and write-locked mutation.
+//
+// Go's built-in map cannot be read concurrently with a write at all, so
+// SeqMap uses its own open-addressing table. Growing the table swaps in a
+// whole new table, so readers never see a table change size under them.
+//
+// The zero value is an empty map ready to use.
+type SeqMap[K comparable, V any] struct {
+	rw    RWMutex
+	seed  maphash.Seed
+	table atomic.Pointer[seqMapTable[K, V]]
+	// Number of live entries and of tombstones left by deletes
+	count int
+	dead  int
+}
+
+type seqMapTable[K comparable, V any] struct {
+	slots []seqMapSlot[K, V]
+	mask  uint64
+}
+
+const (
+	slotEmpty uint32 = iota
+	slotFull
+	slotDeleted
+)
+
+// seqMapSlot is one entry of the table. Readers may look at a slot while it
+// is being written, so a slot's state is published atomically, after its
+// hash, key and value, and a slot's hash and key never change once it is
+// full: deleting leaves them in place, and tombstones are not reused until a
+// resize builds a new table. A reader that sees a full slot therefore never
+// compares against a torn key.
+type seqMapSlot[K comparable, V any] struct {
+	state uint32
+	hash  uint64
+	key   K
+	value V
+}
+
+const seqMapMinSize = 8
+
+// Load returns the value stored for key, if any.
+func (m *SeqMap[K, V]) Load(key K) (value V, ok bool) {
+	stamp := m.rw.RStamp()
+	for {
+		value, ok = m.lookup(key)
+		if m.rw.Ok(stamp) {
+			return value, ok
+		}
+	}
+}
+
+// Len returns the number of entries in the map.
+func (m *SeqMap[K, V]) Len() int {
+	var n int
+	stamp := m.rw.RStamp()
+	for {
+		n = m.count
+		if m.rw.Ok(stamp) {
+			return n
+		}
+	}
+}
+
+// Store sets the value for key.
+func (m *SeqMap[K, V]) Store(key K, value V) {
+	m.rw.Lock()
+	m.store(key, value)
+	m.rw.Unlock()
+}
+
+// Delete removes key from the map.
+func (m *SeqMap[K, V]) Delete(key K) {
+	m.rw.Lock()
+	m.delete(key)
+	m.rw.Unlock()
+}
+
+// LoadOrStore returns the existing value for key if there is one. Otherwise
+// it stores value and returns it. loaded is true if the value was loaded.
+func (m *SeqMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	if actual, loaded = m.Load(key); loaded {
+		return actual, true
+	}
+	m.rw.Lock()
+	defer m.rw.Unlock()
+	if actual, loaded = m.lookup(key); loaded {
+		return actual, true
+	}
+	m.store(key, value)
+	return value, false
+}
+
+// LoadAndDelete removes key, returning the value it had, if any.
+func (m *SeqMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+	if value, loaded = m.lookup(key); loaded {
+		m.delete(key)
+	}
+	return value, loaded
+}
+
+// MutableView is the map as seen from inside Apply. It must not be used once
+// Apply returns.
+type MutableView[K comparable, V any] struct {
+	m *SeqMap[K, V]
+}
+
+// Load returns the value stored for key, including changes made by the
+// batch so far.
+func (v MutableView[K, V]) Load(key K) (value V, ok bool) {
+	return v.m.lookup(key)
+}
+
+// Store sets the value for key.
+func (v MutableView[K, V]) Store(key K, value V) {
+	v.m.store(key, value)
+}
+
+// Delete removes key from the map.
+func (v MutableView[K, V]) Delete(key K) {
+	v.m.delete(key)
+}
+
+// Len returns the number of entries in the map.
+func (v MutableView[K, V]) Len() int {
+	return v.m.count
+}
+
+// Apply runs fn with exclusive access to the map, applying all of its
+// changes in a single write. Bulk loads then cost readers at most one retry
+// rather than one per key, and readers never see the batch half applied.
+func (m *SeqMap[K, V]) Apply(fn func(view MutableView[K, V])) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+	fn(MutableView[K, V]{m})
+}
+
+// All returns an iterator over a consistent snapshot of the map, taken when
+// iteration starts. Writes made during iteration are not seen, and don't
+// disturb it.
+func (m *SeqMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range m.snapshot() {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range calls f for each entry in a consistent snapshot of the map, stopping
+// if f returns false, like sync.Map.Range.
+func (m *SeqMap[K, V]) Range(f func(key K, value V) bool) {
+	m.All()(f)
+}
+
+func (m *SeqMap[K, V]) snapshot() []seqMapSlot[K, V] {
+	return m.snapshotWithin(-1)
+}
+
+// snapshotWithin is snapshot giving up on optimistic reads after attempts
+// tries, negative meaning never, and then holding writers off while it
+// copies, without disturbing optimistic readers.
+func (m *SeqMap[K, V]) snapshotWithin(attempts int) []seqMapSlot[K, V] {
+	var entries []seqMapSlot[K, V]
+	stamp := m.rw.RStamp()
+	for attempt := 0; attempt != attempts; attempt++ {
+		entries = m.collect(entries[:0])
+		if m.rw.Ok(stamp) {
+			return entries
+		}
+	}
+	m.rw.acquire()
+	defer m.rw.release()
+	return m.collect(entries[:0])
+}
+
+func (m *SeqMap[K, V]) collect(entries []seqMapSlot[K, V]) []seqMapSlot[K, V] {
+	if t := m.table.Load(); t != nil {
+		for i := range t.slots {
+			if s := &t.slots[i]; atomic.LoadUint32(&s.state) == slotFull {
+				entries = append(entries, seqMapSlot[K, V]{state: slotFull, hash: s.hash, key: s.key, value: s.value})
+			}
+		}
+	}
+	return entries
+}
+
+// hash must only be called once the first table has been published; the seed
+// is picked lazily, so the zero value is usable, but never changes after that.
+func (m *SeqMap[K, V]) hash(key K) uint64 {
+	return maphash.Comparable(m.seed, key)
+}
+
+// lookup may run concurrently with writers, so it must stay in bounds even on
+// a torn view of the table.
+func (m *SeqMap[K, V]) lookup(key K) (value V, ok bool) {
+	t := m.table.Load()
+	if t == nil {
+		return value, false
+	}
+	h := m.hash(key)
+	i := h & t.mask
+	for n := 0; n < len(t.slots); n++ {
+		s := &t.slots[i]
+		switch state := atomic.LoadUint32(&s.state); {
+		case state == slotEmpty:
+			return value, false
+		case state == slotFull && s.hash == h && s.key == key:
+			return s.value, true
+		}
+		i = (i + 1) & t.mask
+	}
+	return value, false
+}
+
+// find returns the slot holding key, or nil; must hold the write lock.
+func (m *SeqMap[K, V]) find(h uint64, key K) *seqMapSlot[K, V] {
+	t := m.table.Load()
+	if t == nil {
+		return nil
+	}
+	for i := h & t.mask; ; i = (i + 1) & t.mask {
+		s := &t.slots[i]
+		if s.state == slotEmpty {
+			return nil
+		}
+		if s.state == slotFull && s.hash == h && s.key == key {
+			return s
+		}
+	}
+}
+
+func (m *SeqMap[K, V]) store(key K, value V) {
+	if m.table.Load() == nil {
+		m.seed = maphash.MakeSeed()
+		m.resize(seqMapMinSize)
+	}
+	h := m.hash(key)
+	if s := m.find(h, key); s != nil {
+		s.value = value
+		return
+	}
+	t := m.table.Load()
+	if (m.count+m.dead+1)*4 > len(t.slots)*3 {
+		size := len(t.slots)
+		if (m.count+1)*2 > size {
+			size *= 2
+		}
+		m.resize(size)
+		t = m.table.Load()
+	}
+	m.insert(t, h, key, value)
+	m.count++
+}
+
+func (m *SeqMap[K, V]) delete(key K) {
+	if m.table.Load() == nil {
+		return
+	}
+	s := m.find(m.hash(key), key)
+	if s == nil {
+		return
+	}
+	// The key and hash stay put: optimistic readers may be comparing against
+	// them, and zeroing a string or interface key could tear it under them.
+	var zero V
+	atomic.StoreUint32(&s.state, slotDeleted)
+	s.value = zero
+	m.count--
+	m.dead++
+}
+
+func (m *SeqMap[K, V]) resize(size int) {
+	t := &seqMapTable[K, V]{slots: make([]seqMapSlot[K, V], size), mask: uint64(size - 1)}
+	if old := m.table.Load(); old != nil {
+		for i := range old.slots {
+			if s := &old.slots[i]; s.state == slotFull {
+				m.insert(t, s.hash, s.key, s.value)
+			}
+		}
+	}
+	m.dead = 0
+	m.table.Store(t)
+}
+
+// insert puts key in the first empty slot of its probe sequence, skipping
+// tombstones, whose keys readers may still be comparing against.
+func (m *SeqMap[K, V]) insert(t *seqMapTable[K, V], h uint64, key K, value V) {
+	i := h & t.mask
+	for t.slots[i].state != slotEmpty {
+		i = (i + 1) & t.mask
+	}
+	s := &t.slots[i]
+	s.hash, s.key, s.value = h, key, value
+	atomic.StoreUint32(&s.state, slotFull)
+}
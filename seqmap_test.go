@@ -0,0 +1,250 @@
+package seqmut
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSeqMapZeroValue(t *testing.T) {
+	var m SeqMap[string, int]
+
+	_, ok := m.Load("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+	m.Delete("a")
+}
+
+func TestSeqMapStoreLoadDelete(t *testing.T) {
+	var m SeqMap[string, int]
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("a", 3)
+
+	v, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, m.Len())
+
+	m.Delete("a")
+	_, ok = m.Load("a")
+	assert.False(t, ok)
+	v, _ = m.Load("b")
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestSeqMapGrowsAndClearsTombstones(t *testing.T) {
+	var m SeqMap[int, int]
+
+	for i := 0; i < 1000; i++ {
+		m.Store(i, i*2)
+	}
+	for i := 0; i < 1000; i += 2 {
+		m.Delete(i)
+	}
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 1000; i += 2 {
+			m.Store(i, round)
+			m.Delete(i)
+		}
+	}
+
+	assert.Equal(t, 500, m.Len())
+	for i := 1; i < 1000; i += 2 {
+		v, ok := m.Load(i)
+		assert.True(t, ok)
+		assert.Equal(t, i*2, v)
+	}
+	assert.True(t, len(m.table.Load().slots) <= 2048)
+}
+
+func TestSeqMapAllAndRange(t *testing.T) {
+	var m SeqMap[string, int]
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("c", 3)
+
+	seen := map[string]int{}
+	for k, v := range m.All() {
+		seen[k] = v
+	}
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, seen)
+
+	n := 0
+	m.Range(func(k string, v int) bool {
+		n++
+		return false
+	})
+	assert.Equal(t, 1, n)
+}
+
+func TestSeqMapIterationIsUndisturbedByWrites(t *testing.T) {
+	var m SeqMap[int, int]
+	for i := 0; i < 10; i++ {
+		m.Store(i, i)
+	}
+
+	n := 0
+	for k := range m.All() {
+		m.Delete(k)
+		m.Store(k+100, k)
+		n++
+	}
+
+	assert.Equal(t, 10, n)
+}
+
+func TestSeqMapConcurrentReadersAndWriter(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 50
+	}
+	var m SeqMap[string, int]
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			m.Store(fmt.Sprint(i), i)
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if v, ok := m.Load(fmt.Sprint(i)); ok && v != i {
+					panic(fmt.Sprintf("read %d for key %d", v, i))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, n, m.Len())
+}
+
+func TestSeqMapConcurrentReadersAndDeletes(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 50
+	}
+	var m SeqMap[string, int]
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			key := fmt.Sprint(i % 16)
+			m.Store(key, i)
+			m.Delete(key)
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				// Deleted slots keep their keys, so lookups never see one torn
+				m.Load(fmt.Sprint(i % 16))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestSeqMapConcurrentReadersAndInterfaceKeys(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 50
+	}
+	var m SeqMap[any, int]
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			// Alternate key types so a reused slot would change dynamic type
+			var key any = fmt.Sprint(i)
+			if i%2 == 1 {
+				key = i
+			}
+			m.Store(key, i)
+			if i >= 8 {
+				m.Delete(fmt.Sprint(i - 8))
+				m.Delete(i - 8)
+			}
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if v, ok := m.Load(fmt.Sprint(i)); ok && v != i {
+					panic(fmt.Sprintf("read %d for key %q", v, fmt.Sprint(i)))
+				}
+				if v, ok := m.Load(i); ok && v != i {
+					panic(fmt.Sprintf("read %d for key %d", v, i))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8, m.Len())
+}
+
+func TestSeqMapLoadOrStoreAndLoadAndDelete(t *testing.T) {
+	var m SeqMap[string, int]
+
+	actual, loaded := m.LoadOrStore("a", 1)
+	assert.False(t, loaded)
+	assert.Equal(t, 1, actual)
+	actual, loaded = m.LoadOrStore("a", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+
+	v, loaded := m.LoadAndDelete("a")
+	assert.True(t, loaded)
+	assert.Equal(t, 1, v)
+	_, loaded = m.LoadAndDelete("a")
+	assert.False(t, loaded)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestSeqMapApply(t *testing.T) {
+	var m SeqMap[int, int]
+	m.Store(0, 0)
+	stamp := m.rw.RStamp()
+
+	m.Apply(func(view MutableView[int, int]) {
+		for i := 1; i <= 100; i++ {
+			view.Store(i, i)
+		}
+		view.Delete(0)
+		v, ok := view.Load(50)
+		assert.True(t, ok)
+		assert.Equal(t, 50, v)
+		assert.Equal(t, 100, view.Len())
+	})
+
+	// One write for the whole batch
+	assert.False(t, m.rw.Ok(stamp))
+	assert.Equal(t, Stamp(4), m.rw.plain(stamp))
+	assert.Equal(t, 100, m.Len())
+	_, ok := m.Load(0)
+	assert.False(t, ok)
+}
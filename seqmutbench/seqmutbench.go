@@ -0,0 +1,332 @@
+// Package seqmutbench runs reader/writer contention workloads against guarded
+// data structures. It ships workloads for seqmut.RWMutex, sync.RWMutex and
+// atomic.Value, and accepts any other Subject, so teams can benchmark their
+// actual data types under the same harness as the built-in ones.
+package seqmutbench
+
+import (
+	"fmt"
+	"runtime"
+	"seqmut"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Subject is a guarded data structure under test. Implementations must be
+// safe for concurrent use by many readers and writers.
+type Subject interface {
+	// Read performs one consistent read, spinning cs iterations (see Spin)
+	// inside the critical section
+	Read(cs int)
+	// Write performs one write, spinning cs iterations inside the critical
+	// section; n counts writes by the calling goroutine
+	Write(n uint64, cs int)
+}
+
+// Retrier is implemented by subjects that can report how many optimistic
+// reads they had to retry.
+type Retrier interface {
+	Retries() uint64
+}
+
+// Factory creates a subject guarding a payload of the given size in bytes.
+// Subjects that don't have a natural payload size can ignore it.
+type Factory func(payload int) Subject
+
+// Builtins are the implementations the harness compares out of the box.
+var Builtins = map[string]Factory{
+	"seqmut": func(n int) Subject { return &seqSubject{payload: make([]byte, n)} },
+	"sync":   func(n int) Subject { return &syncSubject{payload: make([]byte, n)} },
+	"atomic": func(n int) Subject {
+		s := &atomicSubject{size: n}
+		p := make([]byte, n)
+		s.v.Store(&p)
+		return s
+	},
+}
+
+// Spin burns n iterations, standing in for work done in a critical section.
+func Spin(n int) {
+	x := 0
+	for i := 0; i < n; i++ {
+		x += i
+	}
+	runtime.KeepAlive(x)
+}
+
+func checksum(p []byte) byte {
+	var c byte
+	for _, b := range p {
+		c ^= b
+	}
+	return c
+}
+
+type seqSubject struct {
+	rw      seqmut.RWMutex
+	payload []byte
+}
+
+func (s *seqSubject) Read(cs int) {
+	var c byte
+	stamp := s.rw.RStamp()
+	for {
+		c = checksum(s.payload)
+		Spin(cs)
+		if s.rw.Ok(stamp) {
+			break
+		}
+	}
+	runtime.KeepAlive(c)
+}
+
+func (s *seqSubject) Write(n uint64, cs int) {
+	s.rw.Lock()
+	for i := range s.payload {
+		s.payload[i] = byte(n)
+	}
+	Spin(cs)
+	s.rw.Unlock()
+}
+
+func (s *seqSubject) Retries() uint64 {
+	return s.rw.Retries()
+}
+
+type syncSubject struct {
+	rw      sync.RWMutex
+	payload []byte
+}
+
+func (s *syncSubject) Read(cs int) {
+	s.rw.RLock()
+	c := checksum(s.payload)
+	Spin(cs)
+	s.rw.RUnlock()
+	runtime.KeepAlive(c)
+}
+
+func (s *syncSubject) Write(n uint64, cs int) {
+	s.rw.Lock()
+	for i := range s.payload {
+		s.payload[i] = byte(n)
+	}
+	Spin(cs)
+	s.rw.Unlock()
+}
+
+// atomicSubject publishes full copies, the usual atomic.Value pattern.
+type atomicSubject struct {
+	mu   sync.Mutex
+	v    atomic.Value
+	size int
+}
+
+func (s *atomicSubject) Read(cs int) {
+	c := checksum(*s.v.Load().(*[]byte))
+	Spin(cs)
+	runtime.KeepAlive(c)
+}
+
+func (s *atomicSubject) Write(n uint64, cs int) {
+	s.mu.Lock()
+	p := make([]byte, s.size)
+	for i := range p {
+		p[i] = byte(n)
+	}
+	Spin(cs)
+	s.v.Store(&p)
+	s.mu.Unlock()
+}
+
+// Config describes one workload.
+type Config struct {
+	Readers         int
+	Writers         int
+	CriticalSection int
+	Payload         int
+	Duration        time.Duration
+}
+
+// Result is the outcome of running one workload against one subject.
+type Result struct {
+	Impl            string  `json:"impl"`
+	Readers         int     `json:"readers"`
+	Writers         int     `json:"writers"`
+	CriticalSection int     `json:"cs"`
+	Payload         int     `json:"payload"`
+	ReadsPerSec     float64 `json:"reads_per_sec"`
+	WritesPerSec    float64 `json:"writes_per_sec"`
+	RetriesPerRead  float64 `json:"retries_per_read"`
+}
+
+// Sweep is a set of workloads to run against a set of subjects: every
+// combination of the parameter lists, for every named subject.
+type Sweep struct {
+	Readers          []int
+	Writers          []int
+	CriticalSections []int
+	Payloads         []int
+	// Names of the subjects to run, looked up in Subjects, then in Builtins
+	Impls    []string
+	Subjects map[string]Factory
+	Duration time.Duration
+}
+
+// Run runs every combination in the sweep. It fails before running anything
+// if an implementation is neither in Subjects nor in Builtins.
+func (s Sweep) Run() ([]Result, error) {
+	factories := make(map[string]Factory, len(s.Impls))
+	for _, impl := range s.Impls {
+		factory, ok := s.Subjects[impl]
+		if !ok {
+			factory, ok = Builtins[impl]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown implementation %q", impl)
+		}
+		factories[impl] = factory
+	}
+
+	var results []Result
+	for _, payload := range s.Payloads {
+		for _, cs := range s.CriticalSections {
+			for _, writers := range s.Writers {
+				for _, readers := range s.Readers {
+					for _, impl := range s.Impls {
+						cfg := Config{Readers: readers, Writers: writers, CriticalSection: cs, Payload: payload, Duration: s.Duration}
+						results = append(results, Run(impl, factories[impl], cfg))
+					}
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// Run runs one workload against a fresh subject from factory.
+func Run(name string, factory Factory, cfg Config) Result {
+	subject := factory(cfg.Payload)
+	var stop int32
+	var reads, writes uint64
+	var wg sync.WaitGroup
+
+	for i := 0; i < cfg.Readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n uint64
+			for atomic.LoadInt32(&stop) == 0 {
+				subject.Read(cfg.CriticalSection)
+				n++
+			}
+			atomic.AddUint64(&reads, n)
+		}()
+	}
+	stopWriters := startWriters(subject, cfg.Writers, cfg.CriticalSection, &writes)
+
+	start := time.Now()
+	time.Sleep(cfg.Duration)
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+	stopWriters()
+	elapsed := time.Since(start).Seconds()
+
+	r := Result{
+		Impl:            name,
+		Readers:         cfg.Readers,
+		Writers:         cfg.Writers,
+		CriticalSection: cfg.CriticalSection,
+		Payload:         cfg.Payload,
+		ReadsPerSec:     float64(reads) / elapsed,
+		WritesPerSec:    float64(writes) / elapsed,
+	}
+	if retrier, ok := subject.(Retrier); ok && reads > 0 {
+		r.RetriesPerRead = float64(retrier.Retries()) / float64(reads)
+	}
+	return r
+}
+
+// Benchmark runs the read side of a workload in b.RunParallel while
+// cfg.Writers goroutines write in the background; cfg.Readers and
+// cfg.Duration are ignored in favour of the testing package's own settings.
+// If the subject is a Retrier, retries per read are reported as "retries/op".
+func Benchmark(b *testing.B, factory Factory, cfg Config) {
+	subject := factory(cfg.Payload)
+	var writes uint64
+	stopWriters := startWriters(subject, cfg.Writers, cfg.CriticalSection, &writes)
+	defer stopWriters()
+
+	var before uint64
+	retrier, isRetrier := subject.(Retrier)
+	if isRetrier {
+		before = retrier.Retries()
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			subject.Read(cfg.CriticalSection)
+		}
+	})
+	b.StopTimer()
+
+	if isRetrier && b.N > 0 {
+		b.ReportMetric(float64(retrier.Retries()-before)/float64(b.N), "retries/op")
+	}
+}
+
+// startWriters starts n writer goroutines and returns a function that stops
+// them and waits for them to exit.
+func startWriters(subject Subject, n, cs int, writes *uint64) (stop func()) {
+	var stopped int32
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n uint64
+			for atomic.LoadInt32(&stopped) == 0 {
+				subject.Write(n, cs)
+				n++
+				// Like the package benchmarks, writers give way between writes
+				runtime.Gosched()
+			}
+			atomic.AddUint64(writes, n)
+		}()
+	}
+	return func() {
+		atomic.StoreInt32(&stopped, 1)
+		wg.Wait()
+	}
+}
+
+// Scaling runs Benchmark once per GOMAXPROCS setting in procs, as
+// sub-benchmarks named procs=N. If procs is empty, it uses powers of two up
+// to runtime.NumCPU. Comparing ns/op and retries/op across the curve shows
+// where the single sequence word starts to bounce between cores and
+// sharding the data is warranted.
+func Scaling(b *testing.B, factory Factory, cfg Config, procs []int) {
+	if len(procs) == 0 {
+		procs = DefaultProcs()
+	}
+	for _, p := range procs {
+		b.Run(fmt.Sprintf("procs=%d", p), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(p))
+			Benchmark(b, factory, cfg)
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds()/float64(p), "reads/s/proc")
+		})
+	}
+}
+
+// DefaultProcs returns 1, 2, 4... up to and including runtime.NumCPU.
+func DefaultProcs() []int {
+	var procs []int
+	n := runtime.NumCPU()
+	for p := 1; p < n; p *= 2 {
+		procs = append(procs, p)
+	}
+	return append(procs, n)
+}
@@ -0,0 +1,87 @@
+package seqmutbench
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"seqmut"
+	"testing"
+	"time"
+)
+
+// A user-defined subject, as a team would plug in their own data type
+type counters struct {
+	p seqmut.Protected[[4]int]
+}
+
+func (c *counters) Read(cs int) {
+	v := c.p.Load()
+	Spin(cs)
+	if v[0] != v[3] {
+		panic("torn read")
+	}
+}
+
+func (c *counters) Write(n uint64, cs int) {
+	c.p.Update(func(v *[4]int) {
+		for i := range v {
+			v[i]++
+		}
+		Spin(cs)
+	})
+}
+
+func TestSweepRunsEveryCombination(t *testing.T) {
+	s := Sweep{
+		Readers:          []int{1, 2},
+		Writers:          []int{0, 1},
+		CriticalSections: []int{0},
+		Payloads:         []int{8},
+		Impls:            []string{"seqmut", "sync", "atomic", "counters"},
+		Subjects:         map[string]Factory{"counters": func(int) Subject { return &counters{} }},
+		Duration:         time.Millisecond,
+	}
+
+	results, err := s.Run()
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2*2*4)
+	for _, r := range results {
+		assert.True(t, r.ReadsPerSec > 0, "%+v", r)
+		if r.Writers == 0 {
+			assert.Equal(t, float64(0), r.WritesPerSec)
+			assert.Equal(t, float64(0), r.RetriesPerRead)
+		}
+	}
+}
+
+func TestSweepRejectsUnknownImpl(t *testing.T) {
+	s := Sweep{Readers: []int{1}, Writers: []int{0}, CriticalSections: []int{0}, Payloads: []int{8}, Impls: []string{"seqmut", "mystery"}}
+
+	results, err := s.Run()
+
+	assert.EqualError(t, err, `unknown implementation "mystery"`)
+	assert.Nil(t, results)
+}
+
+func BenchmarkBuiltins(b *testing.B) {
+	for _, impl := range []string{"seqmut", "sync", "atomic"} {
+		b.Run(impl, func(b *testing.B) {
+			Benchmark(b, Builtins[impl], Config{Writers: 1, Payload: 64})
+		})
+	}
+}
+
+func BenchmarkUserSubject(b *testing.B) {
+	Benchmark(b, func(int) Subject { return &counters{} }, Config{Writers: 1})
+}
+
+func TestDefaultProcsEndsAtNumCPU(t *testing.T) {
+	procs := DefaultProcs()
+
+	assert.Equal(t, 1, procs[0])
+	assert.Equal(t, runtime.NumCPU(), procs[len(procs)-1])
+}
+
+func BenchmarkScaling(b *testing.B) {
+	Scaling(b, Builtins["seqmut"], Config{Writers: 1, Payload: 64}, nil)
+}
@@ -0,0 +1,154 @@
+// Package seqmuthealth is a health-check registry. Components report their
+// own status under per-component write locks, and health endpoints read a
+// consistent snapshot of every component optimistically, without blocking
+// the components or each other.
+package seqmuthealth
+
+import (
+	"encoding/json"
+	"net/http"
+	"seqmut"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status is the health of a component.
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusUp
+	StatusDegraded
+	StatusDown
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusUp:
+		return "up"
+	case StatusDegraded:
+		return "degraded"
+	case StatusDown:
+		return "down"
+	}
+	return "unknown"
+}
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Entry is the last report of one component.
+type Entry struct {
+	Name      string        `json:"name"`
+	Status    Status        `json:"status"`
+	Latency   time.Duration `json:"latency_ns"`
+	LastError error         `json:"-"`
+	Updated   time.Time     `json:"updated"`
+}
+
+// Component is a handle for one component to report its health through.
+type Component struct {
+	rw    seqmut.RWMutex
+	entry Entry
+}
+
+// Report records the outcome of a health check of the component. A non-nil
+// err is kept as the last error until the next report with an error.
+func (c *Component) Report(status Status, latency time.Duration, err error) {
+	c.rw.Lock()
+	c.entry.Status = status
+	c.entry.Latency = latency
+	if err != nil {
+		c.entry.LastError = err
+	}
+	c.entry.Updated = time.Now()
+	c.rw.Unlock()
+}
+
+// Registry holds the components of a process. The zero value is ready to use.
+type Registry struct {
+	mu sync.Mutex
+	// Sorted by name; replaced wholesale when a component is added
+	components atomic.Pointer[[]*Component]
+}
+
+// Component returns the component called name, adding it on first use with
+// StatusUnknown.
+func (r *Registry) Component(name string) *Component {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var components []*Component
+	if p := r.components.Load(); p != nil {
+		components = *p
+	}
+	i := sort.Search(len(components), func(i int) bool { return components[i].entry.Name >= name })
+	if i < len(components) && components[i].entry.Name == name {
+		return components[i]
+	}
+	c := &Component{entry: Entry{Name: name}}
+	next := make([]*Component, 0, len(components)+1)
+	next = append(append(append(next, components[:i]...), c), components[i:]...)
+	r.components.Store(&next)
+	return c
+}
+
+// Snapshot returns every component's entry, ordered by name. The entries are
+// consistent with each other: no report landed between reading the first
+// and the last.
+func (r *Registry) Snapshot() []Entry {
+	p := r.components.Load()
+	if p == nil {
+		return nil
+	}
+	components := *p
+	entries := make([]Entry, len(components))
+	locks := make([]seqmut.OptimisticLocker, len(components))
+	for i, c := range components {
+		locks[i] = &c.rw
+	}
+	seqmut.ReadAll(func() {
+		for i, c := range components {
+			entries[i] = c.entry
+		}
+	}, locks...)
+	return entries
+}
+
+type report struct {
+	Status     Status   `json:"status"`
+	Components []result `json:"components"`
+}
+
+type result struct {
+	Entry
+	Error string `json:"error,omitempty"`
+}
+
+// Handler serves a JSON health report, suitable for /healthz. It responds
+// 200 if every component is up or degraded, and 503 otherwise.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rep := report{Status: StatusUp, Components: []result{}}
+		for _, e := range r.Snapshot() {
+			res := result{Entry: e}
+			if e.LastError != nil {
+				res.Error = e.LastError.Error()
+			}
+			rep.Components = append(rep.Components, res)
+			switch {
+			case e.Status == StatusDegraded && rep.Status == StatusUp:
+				rep.Status = StatusDegraded
+			case e.Status == StatusDown || e.Status == StatusUnknown:
+				rep.Status = StatusDown
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if rep.Status == StatusDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(rep)
+	})
+}
@@ -0,0 +1,83 @@
+package seqmuthealth
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotOrderedByName(t *testing.T) {
+	var r Registry
+	r.Component("db").Report(StatusUp, time.Millisecond, nil)
+	r.Component("cache").Report(StatusDegraded, 0, errors.New("slow"))
+
+	entries := r.Snapshot()
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "cache", entries[0].Name)
+	assert.Equal(t, StatusDegraded, entries[0].Status)
+	assert.EqualError(t, entries[0].LastError, "slow")
+	assert.Equal(t, "db", entries[1].Name)
+	assert.Equal(t, time.Millisecond, entries[1].Latency)
+	assert.True(t, r.Component("db") == r.Component("db"))
+}
+
+func TestSnapshotConsistentAcrossComponents(t *testing.T) {
+	var r Registry
+	a, b := r.Component("a"), r.Component("b")
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			// Both components report the same latency each round; a and b are
+			// only ever seen equal if the snapshot holds together
+			a.Report(StatusUp, time.Duration(i), nil)
+			b.Report(StatusUp, time.Duration(i), nil)
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		e := r.Snapshot()
+		assert.True(t, e[0].Latency == e[1].Latency || e[0].Latency == e[1].Latency+1, "%v %v", e[0].Latency, e[1].Latency)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestHandler(t *testing.T) {
+	var r Registry
+	r.Component("db").Report(StatusUp, 0, nil)
+	r.Component("queue").Report(StatusDown, 0, errors.New("unreachable"))
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, 503, rec.Code)
+	var body struct {
+		Status     string
+		Components []struct {
+			Name   string
+			Status string
+			Error  string
+		}
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "down", body.Status)
+	assert.Equal(t, "unreachable", body.Components[1].Error)
+
+	r.Component("queue").Report(StatusUp, 0, nil)
+	rec = httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, 200, rec.Code)
+}
@@ -0,0 +1,43 @@
+// Package seqmuthttp serves the state of registered seqmut locks over HTTP.
+//
+// To mount it at the conventional path:
+//
+//	http.Handle("/debug/seqmut", seqmuthttp.Handler())
+package seqmuthttp
+
+import (
+	"fmt"
+	"net/http"
+	"seqmut"
+	"text/tabwriter"
+)
+
+// Handler returns an http.Handler listing every lock in
+// seqmut.DefaultRegistry: its current version, whether a writer is active, and its
+// contention counters. In binaries built with -tags seqmutdebug it also shows
+// the active writer's stack.
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	statuses := seqmut.Statuses()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSEQUENCE\tWRITER\tRETRIES\tPARKED")
+	for _, s := range statuses {
+		writer := "-"
+		if s.WriterActive {
+			writer = "active"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\n", s.Name, s.Sequence, writer, s.Retries, s.Parked)
+	}
+	tw.Flush()
+
+	for _, s := range statuses {
+		if s.WriterStack != "" {
+			fmt.Fprintf(w, "\nwriter holding %s:\n%s", s.Name, s.WriterStack)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package seqmuthttp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"seqmut"
+	"strings"
+	"testing"
+)
+
+func TestHandlerListsRegisteredLocks(t *testing.T) {
+	var config, sessions seqmut.RWMutex
+	defer seqmut.Register("config", &config)()
+	defer seqmut.Register("sessions", &sessions)()
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/seqmut", nil))
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"NAME", "SEQUENCE", "WRITER", "RETRIES", "PARKED"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"config", "0", "-", "0", "0"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"sessions", "1", "active", "0", "0"}, strings.Fields(lines[2]))
+}
@@ -0,0 +1,29 @@
+// Package seqmutproto provides helpers for guarding protobuf messages with
+// seqmut, kept separate so the core package does not depend on protobuf.
+package seqmutproto
+
+import (
+	"google.golang.org/protobuf/proto"
+	"seqmut"
+	"strconv"
+)
+
+// Snapshot returns a deep copy of the guarded message, made with proto.Clone,
+// together with the stamp of the version it was copied at. The stamp can be
+// handed out in RPC responses as an optimistic-concurrency token, see Token.
+func Snapshot[M proto.Message](p *seqmut.Protected[M]) (M, seqmut.Stamp) {
+	return p.SnapshotWith(func(m M) M {
+		return proto.Clone(m).(M)
+	})
+}
+
+// Token formats a stamp as an opaque token, such as an etag.
+func Token(stamp seqmut.Stamp) string {
+	return strconv.FormatUint(uint64(stamp), 36)
+}
+
+// ParseToken parses a token produced by Token.
+func ParseToken(token string) (seqmut.Stamp, error) {
+	n, err := strconv.ParseUint(token, 36, 64)
+	return seqmut.Stamp(n), err
+}
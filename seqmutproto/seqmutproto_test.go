@@ -0,0 +1,41 @@
+package seqmutproto
+
+import (
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"seqmut"
+	"testing"
+)
+
+func TestSnapshotClonesMessage(t *testing.T) {
+	msg, err := structpb.NewStruct(map[string]interface{}{"name": "a"})
+	assert.NoError(t, err)
+	p := seqmut.NewProtected(msg)
+
+	snap, stamp := Snapshot(p)
+	p.Update(func(m **structpb.Struct) {
+		(*m).Fields["name"] = structpb.NewStringValue("b")
+	})
+
+	assert.True(t, proto.Equal(snap, mustStruct(t, map[string]interface{}{"name": "a"})))
+	_, now := p.LoadStamped()
+	assert.NotEqual(t, stamp, now)
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	stamp := seqmut.Stamp(123456)
+
+	parsed, err := ParseToken(Token(stamp))
+
+	assert.NoError(t, err)
+	assert.Equal(t, stamp, parsed)
+	_, err = ParseToken("not a token!")
+	assert.Error(t, err)
+}
+
+func mustStruct(t *testing.T, v map[string]interface{}) *structpb.Struct {
+	s, err := structpb.NewStruct(v)
+	assert.NoError(t, err)
+	return s
+}
@@ -0,0 +1,21 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package seqmutstore
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// msync synchronously flushes mem, which must be part of a shared mapping
+// and start on a page boundary, to the file it maps.
+func msync(mem []byte) error {
+	if len(mem) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&mem[0])), uintptr(len(mem)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
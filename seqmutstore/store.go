@@ -0,0 +1,205 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+// Package seqmutstore persists a guarded value to a memory-mapped file such
+// that a crash at any point leaves the last committed version intact.
+//
+// The file holds a header with the commit sequence and two slots. Commits
+// always go to the slot not currently published, are flushed to disk with
+// msync, and are then published by advancing the sequence, which determines
+// which slot is live. The sequence thus doubles as the commit marker on disk
+// and as the validation word for optimistic readers in the process.
+//
+// Store persists raw bytes; Value builds a seqmut.Protected[T] on top of it.
+package seqmutstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"seqmut"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	ErrTooLarge = errors.New("seqmutstore: value larger than slot capacity")
+	ErrCorrupt  = errors.New("seqmutstore: no intact slot in file")
+)
+
+const (
+	headerSize = 4096
+	// Length and checksum preceding the data in each slot
+	slotHeaderSize = 16
+)
+
+var magic = [8]byte{'S', 'Q', 'M', 'T', 'S', 'T', 'O', '1'}
+
+// Store is a persistent, versioned byte value.
+type Store struct {
+	mut      sync.Mutex
+	f        *os.File
+	mem      []byte
+	sequence *uint64
+	capacity int
+}
+
+// Open opens the store at path, creating it with room for values of up to
+// capacity bytes if it does not exist. An existing store keeps the capacity
+// it was created with.
+func Open(path string, capacity int) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s, err := open(f, capacity)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func open(f *os.File, capacity int) (*Store, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fresh := info.Size() == 0
+	if fresh {
+		if err := f.Truncate(int64(headerSize + 2*(slotHeaderSize+capacity))); err != nil {
+			return nil, err
+		}
+	} else {
+		capacity = int(info.Size()-headerSize)/2 - slotHeaderSize
+		if capacity < 0 {
+			return nil, ErrCorrupt
+		}
+	}
+
+	size := headerSize + 2*(slotHeaderSize+capacity)
+	mem, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{f: f, mem: mem, sequence: (*uint64)(unsafe.Pointer(&mem[8])), capacity: capacity}
+
+	if fresh {
+		copy(mem, magic[:])
+		s.writeSlot(0, nil)
+		if err := msync(mem); err != nil {
+			s.Close()
+			return nil, err
+		}
+	} else if [8]byte(mem[:8]) != magic {
+		s.Close()
+		return nil, ErrCorrupt
+	} else if err := s.recover(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// recover makes sure the published slot is intact. Slots are synced before
+// they are published, so this only fails over to the older slot if the
+// header and slot writes were reordered on the way to disk.
+func (s *Store) recover() error {
+	seq := atomic.LoadUint64(s.sequence)
+	if (seq & 1) == 1 {
+		return ErrCorrupt
+	}
+	if s.intact(slotOf(seq)) {
+		return nil
+	}
+	if seq >= 2 && s.intact(slotOf(seq-2)) {
+		atomic.StoreUint64(s.sequence, seq-2)
+		return msync(s.mem[:headerSize])
+	}
+	return ErrCorrupt
+}
+
+// Load returns a copy of the last committed value and the stamp it was
+// committed at.
+func (s *Store) Load() ([]byte, seqmut.Stamp) {
+	for {
+		seq := atomic.LoadUint64(s.sequence)
+		slot := s.slot(slotOf(seq))
+		n := binary.LittleEndian.Uint64(slot)
+		var out []byte
+		if n <= uint64(s.capacity) {
+			out = append([]byte(nil), slot[slotHeaderSize:slotHeaderSize+int(n)]...)
+		}
+		// The slot we read is only rewritten once the sequence has moved on
+		if atomic.LoadUint64(s.sequence) == seq && n <= uint64(s.capacity) {
+			return out, seqmut.Stamp(seq)
+		}
+	}
+}
+
+// Commit durably stores value as the new version, returning its stamp. When
+// Commit returns nil, the value survives a crash.
+func (s *Store) Commit(value []byte) (seqmut.Stamp, error) {
+	if len(value) > s.capacity {
+		return 0, ErrTooLarge
+	}
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	next := atomic.LoadUint64(s.sequence) + 2
+	s.writeSlot(slotOf(next), value)
+	if err := s.flushSlot(slotOf(next), len(value)); err != nil {
+		return 0, err
+	}
+	atomic.StoreUint64(s.sequence, next)
+	if err := msync(s.mem[:headerSize]); err != nil {
+		return 0, err
+	}
+	return seqmut.Stamp(next), nil
+}
+
+// Close unmaps and closes the store.
+func (s *Store) Close() error {
+	err := syscall.Munmap(s.mem)
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func slotOf(seq uint64) int {
+	return int(seq/2) % 2
+}
+
+func (s *Store) slot(i int) []byte {
+	start := headerSize + i*(slotHeaderSize+s.capacity)
+	return s.mem[start : start+slotHeaderSize+s.capacity]
+}
+
+// flushSlot msyncs the first n bytes of data in slot i, along with its
+// header. msync wants a page-aligned start, so the range is widened to the
+// page boundary below.
+func (s *Store) flushSlot(i int, n int) error {
+	start := headerSize + i*(slotHeaderSize+s.capacity)
+	end := start + slotHeaderSize + n
+	start &^= syscall.Getpagesize() - 1
+	return msync(s.mem[start:end])
+}
+
+func (s *Store) writeSlot(i int, value []byte) {
+	slot := s.slot(i)
+	copy(slot[slotHeaderSize:], value)
+	binary.LittleEndian.PutUint32(slot[8:], crc32.ChecksumIEEE(value))
+	binary.LittleEndian.PutUint64(slot, uint64(len(value)))
+}
+
+func (s *Store) intact(i int) bool {
+	slot := s.slot(i)
+	n := binary.LittleEndian.Uint64(slot)
+	if n > uint64(s.capacity) {
+		return false
+	}
+	return binary.LittleEndian.Uint32(slot[8:]) == crc32.ChecksumIEEE(slot[slotHeaderSize:slotHeaderSize+int(n)])
+}
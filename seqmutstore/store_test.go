@@ -0,0 +1,103 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package seqmutstore
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"seqmut"
+	"sync"
+	"testing"
+)
+
+func TestFreshStoreIsEmpty(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store"), 64)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	v, stamp := s.Load()
+
+	assert.Empty(t, v)
+	assert.Equal(t, seqmut.Stamp(0), stamp)
+}
+
+func TestCommitSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store")
+	s, err := Open(path, 64)
+	assert.NoError(t, err)
+	_, err = s.Commit([]byte("one"))
+	assert.NoError(t, err)
+	stamp, err := s.Commit([]byte("two"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Close())
+
+	s, err = Open(path, 0)
+	assert.NoError(t, err)
+	defer s.Close()
+	v, at := s.Load()
+
+	assert.Equal(t, "two", string(v))
+	assert.Equal(t, stamp, at)
+}
+
+func TestCommitRejectsOversizedValues(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store"), 4)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Commit([]byte("too long"))
+
+	assert.Equal(t, ErrTooLarge, err)
+}
+
+func TestTornSlotFallsBackToPreviousVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store")
+	s, err := Open(path, 64)
+	assert.NoError(t, err)
+	s.Commit([]byte("one"))
+	s.Commit([]byte("two"))
+	// Simulate the header reaching disk before the slot it points to
+	binary.LittleEndian.PutUint32(s.slot(slotOf(4))[8:], 0xdeadbeef)
+	s.Close()
+
+	s, err = Open(path, 0)
+	assert.NoError(t, err)
+	defer s.Close()
+	v, stamp := s.Load()
+
+	assert.Equal(t, "one", string(v))
+	assert.Equal(t, seqmut.Stamp(2), stamp)
+}
+
+func TestConcurrentLoadsNeverSeeTornValues(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store"), 64)
+	assert.NoError(t, err)
+	defer s.Close()
+	n := 100
+	if testing.Short() {
+		n = 10
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			v := make([]byte, 32)
+			for j := range v {
+				v[j] = byte(i)
+			}
+			s.Commit(v)
+		}
+	}()
+	for i := 0; i < n; i++ {
+		v, _ := s.Load()
+		for _, b := range v {
+			if b != v[0] {
+				t.Fatalf("torn value %v", v)
+			}
+		}
+	}
+	wg.Wait()
+}
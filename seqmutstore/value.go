@@ -0,0 +1,79 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package seqmutstore
+
+import (
+	"bytes"
+	"seqmut"
+	"sync"
+)
+
+// Value is a seqmut.Protected[T] whose every committed version is persisted
+// to a Store. Readers use the Protected as usual, optimistically and without
+// touching the file. Writers are serialized: each one applies its mutation to
+// a copy of the value, commits the encoded copy to the store, and only then
+// publishes it to readers, so readers never see a version that would not
+// survive a crash, and a failed commit changes nothing.
+type Value[T any] struct {
+	mut   sync.Mutex
+	p     seqmut.Protected[T]
+	store *Store
+	codec seqmut.Codec
+}
+
+// OpenValue opens the store at path as with Open, and decodes its last
+// committed value with codec. A fresh store holds the zero value of T.
+func OpenValue[T any](path string, capacity int, codec seqmut.Codec) (*Value[T], error) {
+	store, err := Open(path, capacity)
+	if err != nil {
+		return nil, err
+	}
+	v := &Value[T]{store: store, codec: codec}
+	if data, _ := store.Load(); len(data) > 0 {
+		var initial T
+		if err := codec.Decode(bytes.NewReader(data), &initial); err != nil {
+			store.Close()
+			return nil, err
+		}
+		v.p.Store(initial)
+	}
+	return v, nil
+}
+
+// Load returns a copy of the last committed value.
+func (v *Value[T]) Load() T {
+	return v.p.Load()
+}
+
+// Read calls fn with a consistent view of the last committed value; see
+// seqmut.Protected.Read.
+func (v *Value[T]) Read(fn func(v *T)) {
+	v.p.Read(fn)
+}
+
+// Update runs fn on a deep copy of the value (see seqmut.Clone), durably
+// commits the result and publishes it, returning the stamp it was committed
+// at in the store. If encoding or committing fails, the value is left as it
+// was and the error is returned.
+func (v *Value[T]) Update(fn func(v *T)) (seqmut.Stamp, error) {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	next := v.p.Snapshot()
+	fn(&next)
+	var buf bytes.Buffer
+	if err := v.codec.Encode(&buf, &next); err != nil {
+		return 0, err
+	}
+	stamp, err := v.store.Commit(buf.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	v.p.Store(next)
+	return stamp, nil
+}
+
+// Close closes the underlying store.
+func (v *Value[T]) Close() error {
+	return v.store.Close()
+}
@@ -0,0 +1,53 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package seqmutstore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"seqmut"
+	"testing"
+)
+
+type account struct {
+	Owner   string
+	Balance int
+}
+
+func TestValueSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store")
+	v, err := OpenValue[account](path, 256, seqmut.GobCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, account{}, v.Load())
+
+	_, err = v.Update(func(a *account) { a.Owner = "ada" })
+	assert.NoError(t, err)
+	stamp, err := v.Update(func(a *account) { a.Balance += 10 })
+	assert.NoError(t, err)
+	assert.NoError(t, v.Close())
+
+	v, err = OpenValue[account](path, 0, seqmut.GobCodec{})
+	assert.NoError(t, err)
+	defer v.Close()
+
+	assert.Equal(t, account{Owner: "ada", Balance: 10}, v.Load())
+	_, at := v.store.Load()
+	assert.Equal(t, stamp, at)
+}
+
+func TestFailedCommitLeavesValueUnchanged(t *testing.T) {
+	v, err := OpenValue[account](filepath.Join(t.TempDir(), "store"), 64, seqmut.GobCodec{})
+	assert.NoError(t, err)
+	defer v.Close()
+	v.Update(func(a *account) { a.Balance = 1 })
+
+	_, err = v.Update(func(a *account) {
+		for i := 0; i < 10; i++ {
+			a.Owner += "far too long for the slot "
+		}
+		a.Balance = 2
+	})
+
+	assert.Equal(t, ErrTooLarge, err)
+	assert.Equal(t, account{Balance: 1}, v.Load())
+}
@@ -0,0 +1,121 @@
+// Package seqmuttest provides test doubles for code built on package seqmut.
+package seqmuttest
+
+import (
+	"seqmut"
+	"sync"
+	"testing"
+)
+
+// Calls recorded by Fake.
+const (
+	CallRStamp = "RStamp"
+	CallOk     = "Ok"
+	CallFail   = "Ok=false"
+	CallLock   = "Lock"
+	CallUnlock = "Unlock"
+)
+
+var _ seqmut.OptimisticLocker = (*Fake)(nil)
+
+// Fake is a deterministic seqmut.OptimisticLocker for unit-testing retry
+// logic. It records every call, fails validation when told to rather than
+// when a writer races, and checks that the code under test used it in
+// balanced pairs.
+//
+// The zero value is ready to use.
+type Fake struct {
+	mu       sync.Mutex
+	writer   sync.Mutex
+	sequence uint64
+	calls    []string
+	fail     int
+	locked   bool
+	// Stamps handed out by RStamp that have not yet validated
+	open     map[*seqmut.Stamp]bool
+	problems []string
+}
+
+// FailNext makes the next n calls to Ok report a racing writer.
+func (f *Fake) FailNext(n int) {
+	f.mu.Lock()
+	f.fail += n
+	f.mu.Unlock()
+}
+
+func (f *Fake) RStamp() *seqmut.Stamp {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, CallRStamp)
+	stamp := seqmut.Stamp(f.sequence)
+	if f.open == nil {
+		f.open = map[*seqmut.Stamp]bool{}
+	}
+	f.open[&stamp] = true
+	return &stamp
+}
+
+func (f *Fake) Ok(stamp *seqmut.Stamp) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.open[stamp] {
+		f.problems = append(f.problems, "Ok called with a stamp that is not from RStamp or has already validated")
+	}
+	if f.fail > 0 || *stamp != seqmut.Stamp(f.sequence) {
+		if f.fail > 0 {
+			f.fail--
+		}
+		f.calls = append(f.calls, CallFail)
+		*stamp = seqmut.Stamp(f.sequence)
+		return false
+	}
+	f.calls = append(f.calls, CallOk)
+	delete(f.open, stamp)
+	return true
+}
+
+func (f *Fake) Lock() {
+	f.writer.Lock()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, CallLock)
+	f.locked = true
+	f.sequence += 2
+}
+
+func (f *Fake) Unlock() {
+	f.mu.Lock()
+	f.calls = append(f.calls, CallUnlock)
+	if !f.locked {
+		f.problems = append(f.problems, "Unlock of unlocked Fake")
+		f.mu.Unlock()
+		return
+	}
+	f.locked = false
+	f.mu.Unlock()
+	f.writer.Unlock()
+}
+
+// Calls returns the calls made so far, in order.
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// AssertBalanced fails t if the lock is held, was unlocked while not held,
+// or if any read begun with RStamp never validated.
+func (f *Fake) AssertBalanced(t testing.TB) {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range f.problems {
+		t.Error("seqmuttest: " + p)
+	}
+	if f.locked {
+		t.Error("seqmuttest: Fake is still locked")
+	}
+	if len(f.open) > 0 {
+		t.Errorf("seqmuttest: %d read(s) never validated", len(f.open))
+	}
+}
@@ -0,0 +1,60 @@
+package seqmuttest
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"seqmut"
+	"testing"
+)
+
+func TestFakeScriptedFailures(t *testing.T) {
+	var f Fake
+	f.FailNext(2)
+	attempts := 0
+
+	seqmut.Read(&f, func() { attempts++ })
+
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []string{CallRStamp, CallFail, CallFail, CallOk}, f.Calls())
+	f.AssertBalanced(t)
+}
+
+func TestFakeWriteInvalidatesStamp(t *testing.T) {
+	var f Fake
+	stamp := f.RStamp()
+	f.Lock()
+	f.Unlock()
+
+	assert.False(t, f.Ok(stamp))
+	assert.True(t, f.Ok(stamp))
+	assert.Equal(t, []string{CallRStamp, CallLock, CallUnlock, CallFail, CallOk}, f.Calls())
+	f.AssertBalanced(t)
+}
+
+func TestFakeReportsUnbalancedUse(t *testing.T) {
+	var f Fake
+	f.RStamp()
+	f.Lock()
+	f.Ok(new(seqmut.Stamp))
+
+	rec := &recorder{TB: t}
+	f.AssertBalanced(rec)
+
+	assert.Len(t, rec.errors, 3)
+	f.Unlock()
+}
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.errors = append(r.errors, args[0].(string))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,105 @@
+package seqmut
+
+import (
+	"iter"
+	"sync/atomic"
+)
+
+// SeqSlice is a growable slice with optimistic reads and write-locked
+// mutation. Every change of length publishes a fresh slice header, so a
+// reader never sees a header whose length and backing array disagree.
+//
+// The zero value is an empty slice ready to use.
+type SeqSlice[T any] struct {
+	rw    RWMutex
+	items atomic.Pointer[[]T]
+}
+
+// Len returns the number of elements.
+func (s *SeqSlice[T]) Len() int {
+	var n int
+	stamp := s.rw.RStamp()
+	for {
+		n = len(s.load())
+		if s.rw.Ok(stamp) {
+			return n
+		}
+	}
+}
+
+// Get returns the element at i, and false if i is out of range.
+func (s *SeqSlice[T]) Get(i int) (v T, ok bool) {
+	stamp := s.rw.RStamp()
+	for {
+		items := s.load()
+		var zero T
+		v, ok = zero, i >= 0 && i < len(items)
+		if ok {
+			v = items[i]
+		}
+		if s.rw.Ok(stamp) {
+			return v, ok
+		}
+	}
+}
+
+// Set replaces the element at i. It panics if i is out of range.
+func (s *SeqSlice[T]) Set(i int, v T) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.load()[i] = v
+}
+
+// Append adds elements to the end of the slice.
+func (s *SeqSlice[T]) Append(vs ...T) {
+	s.rw.Lock()
+	items := append(s.load(), vs...)
+	s.items.Store(&items)
+	s.rw.Unlock()
+}
+
+// All returns an iterator over a consistent snapshot of the slice, see
+// SeqMap.All.
+func (s *SeqSlice[T]) All() iter.Seq2[int, T] {
+	return s.Range(0, -1)
+}
+
+// Range returns an iterator over a consistent snapshot of the elements from
+// lo up to, but not including, hi. A negative hi means the end of the slice;
+// a negative lo means the start, and bounds beyond the end are clamped.
+func (s *SeqSlice[T]) Range(lo, hi int) iter.Seq2[int, T] {
+	lo = max(lo, 0)
+	return func(yield func(int, T) bool) {
+		for i, v := range s.snapshot(lo, hi) {
+			if !yield(lo+i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SeqSlice[T]) snapshot(lo, hi int) []T {
+	var out []T
+	stamp := s.rw.RStamp()
+	for {
+		items := s.load()
+		end := hi
+		if end < 0 || end > len(items) {
+			end = len(items)
+		}
+		out = out[:0]
+		if lo < end {
+			out = append(out, items[lo:end]...)
+		}
+		if s.rw.Ok(stamp) {
+			return out
+		}
+	}
+}
+
+func (s *SeqSlice[T]) load() []T {
+	if p := s.items.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSeqSliceZeroValue(t *testing.T) {
+	var s SeqSlice[int]
+
+	_, ok := s.Get(0)
+	assert.False(t, ok)
+	assert.Equal(t, 0, s.Len())
+	for range s.All() {
+		t.Fatal("empty slice yielded an element")
+	}
+}
+
+func TestSeqSliceAppendGetSet(t *testing.T) {
+	var s SeqSlice[string]
+
+	s.Append("a", "b")
+	s.Append("c")
+	s.Set(1, "B")
+
+	v, ok := s.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "B", v)
+	_, ok = s.Get(3)
+	assert.False(t, ok)
+	_, ok = s.Get(-1)
+	assert.False(t, ok)
+	assert.Equal(t, 3, s.Len())
+	assert.Panics(t, func() { s.Set(3, "d") })
+}
+
+func TestSeqSliceAllAndRange(t *testing.T) {
+	var s SeqSlice[int]
+	s.Append(10, 11, 12, 13)
+
+	var idx, vals []int
+	for i, v := range s.All() {
+		idx = append(idx, i)
+		vals = append(vals, v)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3}, idx)
+	assert.Equal(t, []int{10, 11, 12, 13}, vals)
+
+	idx, vals = nil, nil
+	for i, v := range s.Range(1, 3) {
+		idx = append(idx, i)
+		vals = append(vals, v)
+	}
+	assert.Equal(t, []int{1, 2}, idx)
+	assert.Equal(t, []int{11, 12}, vals)
+
+	idx = nil
+	for i := range s.Range(-5, 2) {
+		idx = append(idx, i)
+	}
+	assert.Equal(t, []int{0, 1}, idx)
+
+	for i := range s.Range(2, 100) {
+		if i == 2 {
+			break
+		}
+		t.Fatalf("unexpected index %d", i)
+	}
+}
+
+func TestSeqSliceConcurrentAppendAndIterate(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 50
+	}
+	var s SeqSlice[int]
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			s.Append(i)
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n/10; i++ {
+				for i, v := range s.All() {
+					if i != v {
+						panic("inconsistent snapshot")
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, n, s.Len())
+}
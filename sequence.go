@@ -0,0 +1,14 @@
+package seqmut
+
+import "fmt"
+
+// NewWithSequence returns an RWMutex whose sequence starts at n rather than
+// zero, mostly so tests can exercise behaviour near wraparound. n must be
+// even, since an odd sequence means a writer is active; NewWithSequence
+// panics otherwise.
+func NewWithSequence(n uint64) *RWMutex {
+	if n&1 != 0 {
+		panic(fmt.Sprintf("seqmut: initial sequence must be even, got %d", n))
+	}
+	return &RWMutex{sequence: n}
+}
@@ -0,0 +1,23 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewWithSequenceAcrossWraparound(t *testing.T) {
+	rw := NewWithSequence(MaxUint64 - 1)
+
+	stamp := rw.RStamp()
+	assert.Equal(t, Stamp(MaxUint64-1), rw.plain(stamp))
+	rw.Lock()
+	rw.Unlock()
+
+	assert.False(t, rw.Ok(stamp))
+	assert.Equal(t, Stamp(0), rw.plain(stamp))
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestNewWithSequenceRejectsOdd(t *testing.T) {
+	assert.Panics(t, func() { NewWithSequence(3) })
+}
@@ -0,0 +1,42 @@
+package seqmut
+
+// ReadSession supports long optimistic traversals that would rarely make it
+// from start to end without a racing writer. The reader calls Validate at
+// checkpoints along the way; when it fails, only the work since the last
+// successful checkpoint has to be redone, not the whole traversal.
+//
+// Each segment between checkpoints is consistent on its own, but if writers
+// got in between checkpoints, different segments reflect different versions.
+// Use Consistent to find out whether the traversal as a whole was atomic.
+type ReadSession struct {
+	rw       *RWMutex
+	stamp    Stamp
+	restarts int
+}
+
+// BeginSession starts a read session on rw.
+func (rw *RWMutex) BeginSession() ReadSession {
+	return ReadSession{rw: rw, stamp: *rw.RStamp()}
+}
+
+// Validate checks the segment read since the previous checkpoint. If it
+// returns false, the segment must be re-read before moving on.
+func (s *ReadSession) Validate() bool {
+	if s.rw.Ok(&s.stamp) {
+		return true
+	}
+	s.restarts++
+	return false
+}
+
+// Restarts returns the number of segments that had to be re-read.
+func (s *ReadSession) Restarts() int {
+	return s.restarts
+}
+
+// Consistent returns true if every checkpoint so far validated against the
+// version the session started at, meaning everything read in the session is
+// one consistent snapshot.
+func (s *ReadSession) Consistent() bool {
+	return s.restarts == 0
+}
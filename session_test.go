@@ -0,0 +1,60 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadSessionWithoutWriters(t *testing.T) {
+	var rw RWMutex
+	data := []int{1, 2, 3}
+
+	s := rw.BeginSession()
+	var sum int
+	for i := 0; i < len(data); {
+		v := data[i]
+		if s.Validate() {
+			sum += v
+			i++
+		}
+	}
+
+	assert.Equal(t, 6, sum)
+	assert.True(t, s.Consistent())
+}
+
+func TestReadSessionRedoesOnlyFailedSegment(t *testing.T) {
+	var rw RWMutex
+	data := []int{1, 2, 3}
+	var reads []int
+
+	s := rw.BeginSession()
+	for i := 0; i < len(data); {
+		reads = append(reads, i)
+		if i == 1 && s.Restarts() == 0 {
+			// A writer sneaks in while we are reading the second segment
+			rw.Lock()
+			data[2] = 30
+			rw.Unlock()
+		}
+		if s.Validate() {
+			i++
+		}
+	}
+
+	assert.Equal(t, []int{0, 1, 1, 2}, reads)
+	assert.Equal(t, 1, s.Restarts())
+	assert.False(t, s.Consistent())
+}
+
+func TestReadSessionStartedDuringWrite(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+	s := rw.BeginSession()
+	assert.False(t, s.Validate())
+	rw.Unlock()
+
+	assert.False(t, s.Validate())
+	assert.True(t, s.Validate())
+}
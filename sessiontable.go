@@ -0,0 +1,76 @@
+package seqmut
+
+// SessionEntry is a session and its ID, as listed by SessionTable.List.
+type SessionEntry[K comparable, S any] struct {
+	ID      K
+	Session S
+}
+
+// listAttempts is how many optimistic attempts SessionTable.List makes
+// before holding writers off.
+const listAttempts = 4
+
+// SessionTable holds per-session or per-connection state for a server. The
+// data path looks sessions up with optimistic reads; inserts, removals and
+// updates take the write lock. List copies the whole table consistently for
+// admin endpoints, optimistically at first, and if connections churn too fast
+// for that to succeed, by briefly holding writers off, without ever blocking
+// lookups.
+//
+// The zero value is an empty table ready to use.
+type SessionTable[K comparable, S any] struct {
+	sessions SeqMap[K, S]
+}
+
+// Get returns the session with id.
+func (t *SessionTable[K, S]) Get(id K) (S, bool) {
+	return t.sessions.Load(id)
+}
+
+// Insert adds or replaces the session with id.
+func (t *SessionTable[K, S]) Insert(id K, s S) {
+	t.sessions.Store(id, s)
+}
+
+// Remove removes the session with id, returning it.
+func (t *SessionTable[K, S]) Remove(id K) (S, bool) {
+	return t.sessions.LoadAndDelete(id)
+}
+
+// Update runs fn on the session with id under the write lock, returning
+// false if there is no such session.
+func (t *SessionTable[K, S]) Update(id K, fn func(s *S)) bool {
+	found := false
+	t.sessions.Apply(func(view MutableView[K, S]) {
+		var s S
+		if s, found = view.Load(id); found {
+			fn(&s)
+			view.Store(id, s)
+		}
+	})
+	return found
+}
+
+// Len returns the number of sessions.
+func (t *SessionTable[K, S]) Len() int {
+	return t.sessions.Len()
+}
+
+// List returns every session as of a single moment, in no particular order.
+func (t *SessionTable[K, S]) List() []SessionEntry[K, S] {
+	slots := t.sessions.snapshotWithin(listAttempts)
+	entries := make([]SessionEntry[K, S], len(slots))
+	for i, s := range slots {
+		entries[i] = SessionEntry[K, S]{s.key, s.value}
+	}
+	return entries
+}
+
+// Range calls fn for every session in a List, stopping if fn returns false.
+func (t *SessionTable[K, S]) Range(fn func(id K, s S) bool) {
+	for _, e := range t.List() {
+		if !fn(e.ID, e.Session) {
+			return
+		}
+	}
+}
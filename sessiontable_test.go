@@ -0,0 +1,68 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type conn struct {
+	user  string
+	bytes int
+}
+
+func TestSessionTable(t *testing.T) {
+	var st SessionTable[int, conn]
+	st.Insert(1, conn{user: "ann"})
+	st.Insert(2, conn{user: "bob"})
+
+	assert.True(t, st.Update(1, func(c *conn) { c.bytes += 100 }))
+	assert.False(t, st.Update(3, func(c *conn) { t.Fatal("no such session") }))
+	c, ok := st.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, conn{"ann", 100}, c)
+
+	removed, ok := st.Remove(2)
+	assert.True(t, ok)
+	assert.Equal(t, "bob", removed.user)
+	assert.Equal(t, 1, st.Len())
+	assert.Equal(t, []SessionEntry[int, conn]{{1, conn{"ann", 100}}}, st.List())
+}
+
+func TestSessionTableListUnderChurn(t *testing.T) {
+	var st SessionTable[int, conn]
+	for i := 0; i < 1000; i++ {
+		st.Insert(i, conn{})
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Replace sessions one at a time, always keeping 1000 open
+		for i := 1000; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			st.Remove(i - 1000)
+			st.Insert(i, conn{})
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		entries := st.List()
+		assert.True(t, len(entries) == 999 || len(entries) == 1000, "%d", len(entries))
+		ids := make([]int, len(entries))
+		for j, e := range entries {
+			ids[j] = e.ID
+		}
+		sort.Ints(ids)
+		// The open sessions are always a contiguous range of IDs
+		assert.True(t, ids[len(ids)-1]-ids[0] <= 1000)
+	}
+	close(done)
+	wg.Wait()
+}
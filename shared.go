@@ -0,0 +1,95 @@
+package seqmut
+
+import (
+	"errors"
+	"sync/atomic"
+	"unsafe"
+)
+
+// SharedSeqLockHeader is the number of bytes at the start of a shared region
+// that hold the sequence.
+const SharedSeqLockHeader = 8
+
+var ErrMisaligned = errors.New("seqmut: shared region must be 8-byte aligned and hold at least the header")
+
+// SharedSeqLock is a sequence lock living entirely in caller-provided memory,
+// such as an mmap'd file or a shared memory segment, so one writer process can
+// publish records to many reader processes. The first SharedSeqLockHeader
+// bytes of the region hold the sequence, the rest is the payload.
+//
+// There is no mutex, so the write path is pure atomics and works across
+// processes, but there must only ever be one writer at a time. If a writer
+// dies mid-write it leaves the sequence odd, and readers keep retrying until
+// the next writer recovers the region, see Lock.
+type SharedSeqLock struct {
+	sequence *uint64
+	payload  []byte
+}
+
+// NewSharedSeqLock wraps a shared region. All processes must map the same
+// region and agree on the layout of the payload.
+func NewSharedSeqLock(region []byte) (*SharedSeqLock, error) {
+	if len(region) < SharedSeqLockHeader || uintptr(unsafe.Pointer(&region[0]))%8 != 0 {
+		return nil, ErrMisaligned
+	}
+	return &SharedSeqLock{
+		sequence: (*uint64)(unsafe.Pointer(&region[0])),
+		payload:  region[SharedSeqLockHeader:],
+	}, nil
+}
+
+// Payload returns the guarded part of the region. Readers must only look at
+// it between RStamp and a successful Ok; the writer only between Lock and
+// Unlock.
+func (s *SharedSeqLock) Payload() []byte {
+	return s.payload
+}
+
+func (s *SharedSeqLock) RStamp() *Stamp {
+	return loadStamp(s.sequence)
+}
+
+// See RWMutex.Ok
+func (s *SharedSeqLock) Ok(stamp *Stamp) (ok bool) {
+	return validate(s.sequence, stamp)
+}
+
+// Lock begins a write. If the sequence is already odd, a previous writer died
+// mid-write: Lock takes over its write instead of starting a new one and
+// reports recovered == true. The payload is then torn, and the caller must
+// rewrite it completely before calling Unlock.
+func (s *SharedSeqLock) Lock() (recovered bool) {
+	for {
+		current := atomic.LoadUint64(s.sequence)
+		if (current & 1) == 1 {
+			return true
+		}
+		if atomic.CompareAndSwapUint64(s.sequence, current, current+1) {
+			return false
+		}
+	}
+}
+
+func (s *SharedSeqLock) Unlock() {
+	atomic.AddUint64(s.sequence, 1)
+}
+
+// ReadInto copies a consistent view of the payload into dst, retrying until
+// it validates, and returns the number of bytes copied.
+func (s *SharedSeqLock) ReadInto(dst []byte) int {
+	stamp := s.RStamp()
+	for {
+		n, _ := copyValidated(dst, s.payload, s.sequence, *stamp)
+		if s.Ok(stamp) {
+			return n
+		}
+	}
+}
+
+// Write replaces the start of the payload with src in one write.
+func (s *SharedSeqLock) Write(src []byte) int {
+	s.Lock()
+	n := copy(s.payload, src)
+	s.Unlock()
+	return n
+}
@@ -0,0 +1,48 @@
+package seqmut
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func TestSharedSeqLockOverMmap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "region")
+	assert.NoError(t, os.WriteFile(path, make([]byte, 4096), 0600))
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	mapRegion := func() []byte {
+		mem, err := syscall.Mmap(int(f.Fd()), 0, 4096, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+		assert.NoError(t, err)
+		return mem
+	}
+	writerMem, readerMem := mapRegion(), mapRegion()
+	defer syscall.Munmap(writerMem)
+	defer syscall.Munmap(readerMem)
+
+	w, err := NewSharedSeqLock(writerMem)
+	assert.NoError(t, err)
+	r, err := NewSharedSeqLock(readerMem)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := byte(0); i < 200; i++ {
+			w.Write(bytes.Repeat([]byte{i}, 64))
+		}
+	}()
+	dst := make([]byte, 64)
+	for i := 0; i < 200; i++ {
+		r.ReadInto(dst)
+		assert.Equal(t, bytes.Repeat(dst[:1], 64), dst)
+	}
+	wg.Wait()
+}
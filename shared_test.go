@@ -0,0 +1,51 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unsafe"
+)
+
+func alignedRegion(size int) []byte {
+	words := make([]uint64, (size+7)/8)
+	return unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), size)
+}
+
+func TestSharedSeqLockRejectsBadRegions(t *testing.T) {
+	_, err := NewSharedSeqLock(make([]byte, 4))
+	assert.Equal(t, ErrMisaligned, err)
+
+	_, err = NewSharedSeqLock(alignedRegion(32)[1:])
+	assert.Equal(t, ErrMisaligned, err)
+}
+
+func TestSharedSeqLockWriteAndRead(t *testing.T) {
+	s, err := NewSharedSeqLock(alignedRegion(24))
+	assert.NoError(t, err)
+
+	s.Write([]byte("hello"))
+
+	dst := make([]byte, 5)
+	assert.Equal(t, 5, s.ReadInto(dst))
+	assert.Equal(t, "hello", string(dst))
+}
+
+func TestSharedSeqLockRecoversFromDeadWriter(t *testing.T) {
+	region := alignedRegion(16)
+	dead, _ := NewSharedSeqLock(region)
+	assert.False(t, dead.Lock())
+	copy(dead.Payload(), "torn")
+	// ...and the writer process dies here
+
+	s, _ := NewSharedSeqLock(region)
+	stamp := s.RStamp()
+	assert.False(t, s.Ok(stamp))
+
+	assert.True(t, s.Lock())
+	copy(s.Payload(), "good")
+	s.Unlock()
+
+	assert.False(t, s.Ok(stamp))
+	assert.True(t, s.Ok(stamp))
+	assert.Equal(t, uint64(2), *s.sequence)
+}
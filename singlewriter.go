@@ -0,0 +1,47 @@
+package seqmut
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// SingleWriterSeqLock is a sequence lock for the case where there is exactly
+// one writer goroutine. There is no mutex; the write path is just the two
+// sequence increments.
+//
+// It is up to the caller to guarantee that writers never overlap. Building
+// with -tags seqmutdebug makes Lock panic if it detects a concurrent writer.
+type SingleWriterSeqLock struct {
+	sequence uint64
+}
+
+func (sw *SingleWriterSeqLock) RStamp() *Stamp {
+	return loadStamp(&sw.sequence)
+}
+
+// See RWMutex.Ok
+func (sw *SingleWriterSeqLock) Ok(stamp *Stamp) (ok bool) {
+	return validate(&sw.sequence, stamp)
+}
+
+func (sw *SingleWriterSeqLock) Lock() {
+	if debug {
+		current := atomic.LoadUint64(&sw.sequence)
+		if (current&1) == 1 || !atomic.CompareAndSwapUint64(&sw.sequence, current, current+1) {
+			panic(fmt.Sprintf("seqmut: concurrent writers on SingleWriterSeqLock (sequence %d)", current))
+		}
+		return
+	}
+	atomic.AddUint64(&sw.sequence, 1)
+}
+
+func (sw *SingleWriterSeqLock) Unlock() {
+	if debug {
+		current := atomic.LoadUint64(&sw.sequence)
+		if (current&1) == 0 || !atomic.CompareAndSwapUint64(&sw.sequence, current, current+1) {
+			panic(fmt.Sprintf("seqmut: unlock of unlocked or concurrently written SingleWriterSeqLock (sequence %d)", current))
+		}
+		return
+	}
+	atomic.AddUint64(&sw.sequence, 1)
+}
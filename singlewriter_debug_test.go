@@ -0,0 +1,22 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSingleWriterDebugDetectsOverlappingWriters(t *testing.T) {
+	var sw SingleWriterSeqLock
+	sw.Lock()
+
+	assert.Panics(t, func() { sw.Lock() })
+}
+
+func TestSingleWriterDebugDetectsUnlockOfUnlocked(t *testing.T) {
+	var sw SingleWriterSeqLock
+
+	assert.Panics(t, func() { sw.Unlock() })
+}
@@ -0,0 +1,96 @@
+package seqmut
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSingleWriterReadHappyPath(t *testing.T) {
+	var sw SingleWriterSeqLock
+
+	stamp := sw.RStamp()
+
+	assert.True(t, sw.Ok(stamp))
+}
+
+func TestSingleWriterOkIsFalseWhileWriterActive(t *testing.T) {
+	var sw SingleWriterSeqLock
+
+	stamp := sw.RStamp()
+	sw.Lock()
+
+	assert.False(t, sw.Ok(stamp))
+	assert.False(t, sw.Ok(stamp))
+
+	sw.Unlock()
+
+	assert.False(t, sw.Ok(stamp))
+	assert.True(t, sw.Ok(stamp))
+}
+
+func TestSingleWriterHammer(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 1000
+	if testing.Short() {
+		n = 5
+	}
+
+	var sw SingleWriterSeqLock
+	var activity int32
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			sw.Lock()
+			atomic.AddInt32(&activity, 1)
+			for i := 0; i < 100; i++ {
+			}
+			atomic.AddInt32(&activity, -1)
+			sw.Unlock()
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				var read int32
+				stamp := sw.RStamp()
+				for {
+					read = atomic.LoadInt32(&activity)
+					if sw.Ok(stamp) {
+						break
+					}
+				}
+				if read != 0 {
+					panic(fmt.Sprintf("wlock(%d)", read))
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkSingleWriterLockUnlock(b *testing.B) {
+	var sw SingleWriterSeqLock
+	for i := 0; i < b.N; i++ {
+		sw.Lock()
+		sw.Unlock()
+	}
+}
+
+func BenchmarkRWMutexLockUnlock(b *testing.B) {
+	var rw RWMutex
+	for i := 0; i < b.N; i++ {
+		rw.Lock()
+		rw.Unlock()
+	}
+}
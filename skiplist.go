@@ -0,0 +1,243 @@
+package seqmut
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+const skipMaxLevel = 16
+
+type skipNode[K cmp.Ordered, V any] struct {
+	// Guards value; write-locked by inserts using the node as a predecessor,
+	// and made obsolete once the node is unlinked
+	lock  NodeLock
+	key   K
+	value V
+	// Set by Delete, before unlinking, under lock
+	marked atomic.Bool
+	// Set once the node is linked in at every level
+	fullyLinked atomic.Bool
+	next        []atomic.Pointer[skipNode[K, V]]
+}
+
+// Skiplist is an ordered map with optimistic reads. Searches descend without
+// locking anything and read values validated against the node's version;
+// inserts and deletes lock only the predecessors of the node they link or
+// unlink (Herlihy et al., "A Simple Optimistic Skiplist Algorithm").
+//
+// Nodes come from an Arena, and every operation runs inside its epoch, so
+// deleted nodes are recycled once no search can still be passing through
+// them.
+type Skiplist[K cmp.Ordered, V any] struct {
+	head  *skipNode[K, V]
+	nodes *Arena[skipNode[K, V]]
+}
+
+// skipSlabSize is the number of nodes a Skiplist allocates at a time.
+const skipSlabSize = 64
+
+func NewSkiplist[K cmp.Ordered, V any]() *Skiplist[K, V] {
+	head := &skipNode[K, V]{next: make([]atomic.Pointer[skipNode[K, V]], skipMaxLevel)}
+	head.fullyLinked.Store(true)
+	return &Skiplist[K, V]{head: head, nodes: NewArena[skipNode[K, V]](skipSlabSize)}
+}
+
+// find fills preds and succs with the nodes either side of key at every
+// level, returning the highest level key was found at, or -1.
+func (s *Skiplist[K, V]) find(key K, preds, succs *[skipMaxLevel]*skipNode[K, V]) int {
+	found := -1
+	pred := s.head
+	for l := skipMaxLevel - 1; l >= 0; l-- {
+		cur := pred.next[l].Load()
+		for cur != nil && cur.key < key {
+			pred, cur = cur, cur.next[l].Load()
+		}
+		if found == -1 && cur != nil && cur.key == key {
+			found = l
+		}
+		preds[l], succs[l] = pred, cur
+	}
+	return found
+}
+
+// Load returns the value stored under key.
+func (s *Skiplist[K, V]) Load(key K) (value V, ok bool) {
+	g := s.nodes.Epochs.Enter()
+	defer g.Exit()
+	pred := s.head
+	var cur *skipNode[K, V]
+	for l := skipMaxLevel - 1; l >= 0; l-- {
+		cur = pred.next[l].Load()
+		for cur != nil && cur.key < key {
+			pred, cur = cur, cur.next[l].Load()
+		}
+		if cur != nil && cur.key == key {
+			break
+		}
+	}
+	if cur == nil || cur.key != key || !cur.fullyLinked.Load() || cur.marked.Load() {
+		return value, false
+	}
+	return cur.load()
+}
+
+// load reads the node's value, failing if the node has been deleted.
+func (n *skipNode[K, V]) load() (value V, ok bool) {
+	for {
+		version, ok := n.lock.ReadLockOrRestart()
+		if !ok {
+			var zero V
+			return zero, false
+		}
+		value = n.value
+		if n.lock.CheckOrRestart(version) {
+			return value, true
+		}
+	}
+}
+
+// Store sets the value for key.
+func (s *Skiplist[K, V]) Store(key K, value V) {
+	var preds, succs [skipMaxLevel]*skipNode[K, V]
+	// Each level up holds a quarter of the nodes of the one below
+	top := bits.TrailingZeros64(rand.Uint64()|1<<(2*skipMaxLevel-2)) / 2
+	g := s.nodes.Epochs.Enter()
+	defer g.Exit()
+	for {
+		if found := s.find(key, &preds, &succs); found != -1 {
+			n := succs[found]
+			if n.marked.Load() {
+				// Being deleted; look again once it is gone
+				continue
+			}
+			for !n.fullyLinked.Load() {
+				runtime.Gosched()
+			}
+			if !n.lock.WriteLockOrRestart() {
+				continue
+			}
+			n.value = value
+			n.lock.WriteUnlock()
+			return
+		}
+
+		// Lock each distinct predecessor, bottom up, and check nothing
+		// changed between them and their successors since find
+		highest := -1
+		var prev *skipNode[K, V]
+		valid := true
+		for l := 0; valid && l <= top; l++ {
+			pred, succ := preds[l], succs[l]
+			if pred != prev {
+				if !pred.lock.WriteLockOrRestart() {
+					valid = false
+					break
+				}
+				highest, prev = l, pred
+			}
+			valid = !pred.marked.Load() && (succ == nil || !succ.marked.Load()) && pred.next[l].Load() == succ
+		}
+		if valid {
+			n := s.nodes.Alloc()
+			n.key, n.value, n.next = key, value, make([]atomic.Pointer[skipNode[K, V]], top+1)
+			for l := 0; l <= top; l++ {
+				n.next[l].Store(succs[l])
+			}
+			for l := 0; l <= top; l++ {
+				preds[l].next[l].Store(n)
+			}
+			n.fullyLinked.Store(true)
+			unlockPreds(&preds, highest)
+			return
+		}
+		unlockPreds(&preds, highest)
+	}
+}
+
+func unlockPreds[K cmp.Ordered, V any](preds *[skipMaxLevel]*skipNode[K, V], highest int) {
+	var prev *skipNode[K, V]
+	for l := 0; l <= highest; l++ {
+		if preds[l] != prev {
+			preds[l].lock.WriteUnlock()
+			prev = preds[l]
+		}
+	}
+}
+
+// Delete removes key, returning false if it was not present.
+func (s *Skiplist[K, V]) Delete(key K) bool {
+	var preds, succs [skipMaxLevel]*skipNode[K, V]
+	var victim *skipNode[K, V]
+	g := s.nodes.Epochs.Enter()
+	defer g.Exit()
+	for {
+		found := s.find(key, &preds, &succs)
+		if victim == nil {
+			if found == -1 {
+				return false
+			}
+			n := succs[found]
+			if !n.fullyLinked.Load() || len(n.next)-1 != found || n.marked.Load() {
+				return false
+			}
+			if !n.lock.WriteLockOrRestart() {
+				return false
+			}
+			if n.marked.Load() {
+				n.lock.WriteUnlock()
+				return false
+			}
+			n.marked.Store(true)
+			victim = n
+		}
+
+		top := len(victim.next) - 1
+		highest := -1
+		var prev *skipNode[K, V]
+		valid := true
+		for l := 0; valid && l <= top; l++ {
+			pred := preds[l]
+			if pred != prev {
+				if !pred.lock.WriteLockOrRestart() {
+					valid = false
+					break
+				}
+				highest, prev = l, pred
+			}
+			valid = !pred.marked.Load() && pred.next[l].Load() == victim
+		}
+		if valid {
+			for l := top; l >= 0; l-- {
+				preds[l].next[l].Store(victim.next[l].Load())
+			}
+			victim.lock.WriteUnlockObsolete()
+			unlockPreds(&preds, highest)
+			// Searches that started before the unlink may still be on it
+			s.nodes.Retire(victim)
+			return true
+		}
+		unlockPreds(&preds, highest)
+	}
+}
+
+// All iterates over the map in key order. The iteration is not a snapshot:
+// it sees each entry as it is when the iterator gets to it. Deleted nodes are
+// not recycled until the iteration ends, so long iterations hold back reuse.
+func (s *Skiplist[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		g := s.nodes.Epochs.Enter()
+		defer g.Exit()
+		for n := s.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			if !n.fullyLinked.Load() || n.marked.Load() {
+				continue
+			}
+			if v, ok := n.load(); ok && !yield(n.key, v) {
+				return
+			}
+		}
+	}
+}
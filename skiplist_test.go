@@ -0,0 +1,85 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestSkiplist(t *testing.T) {
+	s := NewSkiplist[int, string]()
+	s.Store(3, "c")
+	s.Store(1, "a")
+	s.Store(2, "b")
+	s.Store(2, "B")
+
+	v, ok := s.Load(2)
+	assert.True(t, ok)
+	assert.Equal(t, "B", v)
+	_, ok = s.Load(4)
+	assert.False(t, ok)
+
+	assert.True(t, s.Delete(1))
+	assert.False(t, s.Delete(1))
+	_, ok = s.Load(1)
+	assert.False(t, ok)
+
+	var keys []int
+	for k := range s.All() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{2, 3}, keys)
+}
+
+func TestSkiplistConcurrent(t *testing.T) {
+	s := NewSkiplist[int, int]()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 2000; i += 4 {
+				s.Store(i, i)
+				if i%3 == 0 {
+					assert.True(t, s.Delete(i))
+				}
+			}
+		}(w)
+	}
+	for i := 0; i < 2000; i++ {
+		if v, ok := s.Load(i); ok {
+			assert.Equal(t, i, v)
+		}
+	}
+	wg.Wait()
+
+	prev, n := -1, 0
+	for k, v := range s.All() {
+		assert.True(t, k > prev)
+		assert.Equal(t, k, v)
+		assert.NotEqual(t, 0, k%3)
+		prev = k
+		n++
+	}
+	assert.Equal(t, 2000-667, n)
+}
+
+func TestSkiplistRecyclesDeletedNodes(t *testing.T) {
+	s := NewSkiplist[int, int]()
+	for i := 0; i < 10; i++ {
+		s.Store(i, i)
+	}
+	for i := 0; i < 10; i++ {
+		assert.True(t, s.Delete(i))
+	}
+
+	assert.Equal(t, 10, s.nodes.Reclaim())
+
+	for i := 0; i < 10; i++ {
+		s.Store(i, i*2)
+	}
+	assert.Equal(t, 0, s.nodes.Reclaim())
+	v, ok := s.Load(3)
+	assert.True(t, ok)
+	assert.Equal(t, 6, v)
+}
@@ -0,0 +1,337 @@
+package seqmut
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Point is a position in the plane.
+type Point struct {
+	X, Y float64
+}
+
+// Rect is an axis-aligned rectangle, including its edges.
+type Rect struct {
+	Min, Max Point
+}
+
+// Contains returns true if p lies inside r.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// SpatialItem is a key and its position in a SpatialGrid.
+type SpatialItem[K comparable] struct {
+	Key K
+	At  Point
+}
+
+// SpatialGrid is a 2D spatial index over a uniform grid of square cells.
+// Queries read each cell they visit optimistically, validating it with the
+// cell's NodeLock, and never block writers; updates lock only the one or two
+// cells involved, so moves in different parts of the world don't contend.
+// Suited to game servers and geo services, which query far more often than
+// objects move.
+//
+// Updates to any one key must not run concurrently with each other; updates
+// to different keys may.
+type SpatialGrid[K comparable] struct {
+	cellSize float64
+	cells    SeqMap[cellCoord, *gridCell[K]]
+	// Where each key is, kept up to date while holding its cell's lock
+	positions SeqMap[K, Point]
+
+	// Range of cell coordinates in use, to bound nearest-neighbour searches
+	boundsMu sync.Mutex
+	bounds   Protected[cellBounds]
+}
+
+type cellCoord struct {
+	x, y int
+}
+
+type cellBounds struct {
+	min, max cellCoord
+	empty    bool
+}
+
+type gridCell[K comparable] struct {
+	lock NodeLock
+	// Slice headers are published atomically so racing readers stay in
+	// bounds; positions are updated in place, under lock
+	items atomic.Pointer[[]SpatialItem[K]]
+}
+
+// NewSpatialGrid returns an empty grid with cells cellSize wide. Queries are
+// fastest when a typical query rectangle spans a few cells. It panics if
+// cellSize is not positive and finite.
+func NewSpatialGrid[K comparable](cellSize float64) *SpatialGrid[K] {
+	if !(cellSize > 0) || math.IsInf(cellSize, 1) {
+		panic("seqmut: spatial grid cell size must be positive and finite")
+	}
+	g := &SpatialGrid[K]{cellSize: cellSize}
+	g.bounds.Store(cellBounds{empty: true})
+	return g
+}
+
+// maxCell bounds cell coordinates, so that points out at 1e30 or infinity
+// land in the outermost cells rather than overflowing int, with room to spare
+// for searches ranging across the whole grid, even where int is 32 bits.
+const maxCell = 1 << 28
+
+func (g *SpatialGrid[K]) coord(p Point) cellCoord {
+	return cellCoord{g.cellOf(p.X), g.cellOf(p.Y)}
+}
+
+func (g *SpatialGrid[K]) cellOf(v float64) int {
+	c := math.Floor(v / g.cellSize)
+	switch {
+	case c < -maxCell:
+		return -maxCell
+	case c > maxCell:
+		return maxCell
+	case c != c:
+		// NaN is never stored, and contains nothing
+		return 0
+	}
+	return int(c)
+}
+
+// cell returns the cell at c, creating it if need be.
+func (g *SpatialGrid[K]) cell(c cellCoord) *gridCell[K] {
+	if cell, ok := g.cells.Load(c); ok {
+		return cell
+	}
+	cell, loaded := g.cells.LoadOrStore(c, &gridCell[K]{})
+	if !loaded {
+		g.boundsMu.Lock()
+		g.bounds.Update(func(b *cellBounds) {
+			if b.empty {
+				*b = cellBounds{min: c, max: c}
+				return
+			}
+			b.min = cellCoord{min(b.min.x, c.x), min(b.min.y, c.y)}
+			b.max = cellCoord{max(b.max.x, c.x), max(b.max.y, c.y)}
+		})
+		g.boundsMu.Unlock()
+	}
+	return cell
+}
+
+func (c *gridCell[K]) load() []SpatialItem[K] {
+	if p := c.items.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// index returns where key is in the cell; must hold the cell's lock.
+func (c *gridCell[K]) index(key K) int {
+	for i, item := range c.load() {
+		if item.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *gridCell[K]) add(key K, p Point) {
+	items := append(c.load(), SpatialItem[K]{key, p})
+	c.items.Store(&items)
+}
+
+func (c *gridCell[K]) remove(i int) {
+	items := c.load()
+	last := len(items) - 1
+	items[i] = items[last]
+	items[last] = SpatialItem[K]{}
+	items = items[:last]
+	c.items.Store(&items)
+}
+
+// Set puts key at p, moving it if it is already in the grid. It panics if
+// either coordinate of p is NaN.
+func (g *SpatialGrid[K]) Set(key K, p Point) {
+	if math.IsNaN(p.X) || math.IsNaN(p.Y) {
+		panic("seqmut: spatial grid position is NaN")
+	}
+	to := g.cell(g.coord(p))
+	old, ok := g.positions.Load(key)
+	if !ok {
+		to.lock.WriteLockOrRestart()
+		to.add(key, p)
+		g.positions.Store(key, p)
+		to.lock.WriteUnlock()
+		return
+	}
+	from := g.cell(g.coord(old))
+	if from == to {
+		to.lock.WriteLockOrRestart()
+		to.load()[to.index(key)].At = p
+		g.positions.Store(key, p)
+		to.lock.WriteUnlock()
+		return
+	}
+	// Lock both cells, in a fixed order, so the key is never missing from
+	// both at once
+	first, second := from, to
+	if c, o := g.coord(p), g.coord(old); c.x < o.x || c.x == o.x && c.y < o.y {
+		first, second = to, from
+	}
+	first.lock.WriteLockOrRestart()
+	second.lock.WriteLockOrRestart()
+	from.remove(from.index(key))
+	to.add(key, p)
+	g.positions.Store(key, p)
+	second.lock.WriteUnlock()
+	first.lock.WriteUnlock()
+}
+
+// Delete removes key, returning false if it was not in the grid.
+func (g *SpatialGrid[K]) Delete(key K) bool {
+	old, ok := g.positions.Load(key)
+	if !ok {
+		return false
+	}
+	from := g.cell(g.coord(old))
+	from.lock.WriteLockOrRestart()
+	from.remove(from.index(key))
+	g.positions.Delete(key)
+	from.lock.WriteUnlock()
+	return true
+}
+
+// Position returns where key is.
+func (g *SpatialGrid[K]) Position(key K) (Point, bool) {
+	return g.positions.Load(key)
+}
+
+// readCell returns a consistent copy of the cell's items, appended to dst.
+func (c *gridCell[K]) readCell(dst []SpatialItem[K]) []SpatialItem[K] {
+	n := len(dst)
+	for {
+		version, _ := c.lock.ReadLockOrRestart()
+		dst = append(dst[:n], c.load()...)
+		if c.lock.ReadUnlockOrRestart(version) {
+			return dst
+		}
+	}
+}
+
+// Within returns the items inside r. Each cell is read consistently, but
+// cells are read one after another, so an item moving during the query may
+// be missed or seen twice.
+func (g *SpatialGrid[K]) Within(r Rect) []SpatialItem[K] {
+	lo, hi := g.coord(r.Min), g.coord(r.Max)
+	b := g.bounds.Load()
+	if b.empty {
+		return nil
+	}
+	lo = cellCoord{max(lo.x, b.min.x), max(lo.y, b.min.y)}
+	hi = cellCoord{min(hi.x, b.max.x), min(hi.y, b.max.y)}
+	var items, found []SpatialItem[K]
+	visit := func(cell *gridCell[K]) {
+		items = cell.readCell(items[:0])
+		for _, item := range items {
+			if r.Contains(item.At) {
+				found = append(found, item)
+			}
+		}
+	}
+	if g.sparse(lo, hi) {
+		g.cells.Range(func(c cellCoord, cell *gridCell[K]) bool {
+			if c.x >= lo.x && c.x <= hi.x && c.y >= lo.y && c.y <= hi.y {
+				visit(cell)
+			}
+			return true
+		})
+		return found
+	}
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			if cell, ok := g.cells.Load(cellCoord{x, y}); ok {
+				visit(cell)
+			}
+		}
+	}
+	return found
+}
+
+// sparse returns true if the cells from lo to hi are mostly empty, so that
+// going through the cells in use is cheaper than looking up each one in turn,
+// as it is when far-flung points spread the grid out.
+func (g *SpatialGrid[K]) sparse(lo, hi cellCoord) bool {
+	return float64(hi.x-lo.x+1)*float64(hi.y-lo.y+1) > float64(g.cells.Len())
+}
+
+// Nearest returns the item closest to p, searching outwards ring by ring of
+// cells, or through every cell in use where the grid is sparse, with the
+// same consistency as Within.
+func (g *SpatialGrid[K]) Nearest(p Point) (nearest SpatialItem[K], ok bool) {
+	b := g.bounds.Load()
+	if b.empty {
+		return nearest, false
+	}
+	c := g.coord(p)
+	best := math.Inf(1)
+	var items []SpatialItem[K]
+	visitCell := func(cell *gridCell[K]) {
+		items = cell.readCell(items[:0])
+		for _, item := range items {
+			d := math.Hypot(item.At.X-p.X, item.At.Y-p.Y)
+			if math.IsNaN(d) {
+				// Infinitely far away, and the other way round
+				d = math.Inf(1)
+			}
+			// Distances may be infinite, but any item beats none
+			if d < best || !ok {
+				best, nearest, ok = d, item, true
+			}
+		}
+	}
+	if g.sparse(b.min, b.max) {
+		g.cells.Range(func(_ cellCoord, cell *gridCell[K]) bool {
+			visitCell(cell)
+			return true
+		})
+		return nearest, ok
+	}
+	visit := func(x, y int) {
+		if cell, found := g.cells.Load(cellCoord{x, y}); found {
+			visitCell(cell)
+		}
+	}
+	// Rings closer than the bounds of the occupied cells are empty, so start
+	// at the first that reaches them
+	start := max(b.min.x-c.x, c.x-b.max.x, b.min.y-c.y, c.y-b.max.y, 0)
+	for r := start; ; r++ {
+		// Only the perimeter of the ring, and only where it is within bounds
+		xlo, xhi := max(c.x-r, b.min.x), min(c.x+r, b.max.x)
+		for _, y := range [2]int{c.y - r, c.y + r} {
+			if y >= b.min.y && y <= b.max.y {
+				for x := xlo; x <= xhi; x++ {
+					visit(x, y)
+				}
+			}
+			if r == 0 {
+				break
+			}
+		}
+		ylo, yhi := max(c.y-r+1, b.min.y), min(c.y+r-1, b.max.y)
+		for _, x := range [2]int{c.x - r, c.x + r} {
+			if r > 0 && x >= b.min.x && x <= b.max.x {
+				for y := ylo; y <= yhi; y++ {
+					visit(x, y)
+				}
+			}
+		}
+		// Anything in further rings is at least r cells away
+		if ok && best <= float64(r)*g.cellSize {
+			return nearest, true
+		}
+		if c.x-r <= b.min.x && c.x+r >= b.max.x && c.y-r <= b.min.y && c.y+r >= b.max.y {
+			return nearest, ok
+		}
+	}
+}
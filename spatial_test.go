@@ -0,0 +1,128 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func keys(items []SpatialItem[string]) []string {
+	var ks []string
+	for _, item := range items {
+		ks = append(ks, item.Key)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func TestSpatialGridWithin(t *testing.T) {
+	g := NewSpatialGrid[string](10)
+	g.Set("a", Point{1, 1})
+	g.Set("b", Point{15, 5})
+	g.Set("c", Point{-25, 40})
+
+	assert.Equal(t, []string{"a", "b"}, keys(g.Within(Rect{Point{0, 0}, Point{20, 20}})))
+	assert.Equal(t, []string{"c"}, keys(g.Within(Rect{Point{-100, 0}, Point{0, 100}})))
+	assert.Empty(t, g.Within(Rect{Point{100, 100}, Point{200, 200}}))
+}
+
+func TestSpatialGridSetMovesAndDelete(t *testing.T) {
+	g := NewSpatialGrid[string](10)
+	g.Set("a", Point{1, 1})
+	g.Set("a", Point{2, 2})
+	g.Set("a", Point{55, 55})
+
+	assert.Empty(t, g.Within(Rect{Point{0, 0}, Point{10, 10}}))
+	assert.Equal(t, []SpatialItem[string]{{"a", Point{55, 55}}}, g.Within(Rect{Point{50, 50}, Point{60, 60}}))
+	p, ok := g.Position("a")
+	assert.True(t, ok)
+	assert.Equal(t, Point{55, 55}, p)
+
+	assert.True(t, g.Delete("a"))
+	assert.False(t, g.Delete("a"))
+	assert.Empty(t, g.Within(Rect{Point{50, 50}, Point{60, 60}}))
+}
+
+func TestSpatialGridNearest(t *testing.T) {
+	g := NewSpatialGrid[string](10)
+	_, ok := g.Nearest(Point{})
+	assert.False(t, ok)
+
+	g.Set("near", Point{12, 0})
+	g.Set("far", Point{-50, -50})
+	g.Set("same-cell", Point{9, 9})
+
+	item, ok := g.Nearest(Point{8, 8})
+	assert.True(t, ok)
+	assert.Equal(t, "same-cell", item.Key)
+
+	// "same-cell" shares the query's cell, but "near" is closer
+	item, _ = g.Nearest(Point{8, 1})
+	assert.Equal(t, "near", item.Key)
+
+	item, _ = g.Nearest(Point{-1000, -1000})
+	assert.Equal(t, "far", item.Key)
+
+	// Rings short of the occupied cells are skipped rather than scanned
+	item, _ = g.Nearest(Point{1e6, -1e6})
+	assert.Equal(t, "near", item.Key)
+}
+
+func TestSpatialGridHandlesExtremeCoordinates(t *testing.T) {
+	g := NewSpatialGrid[string](10)
+	g.Set("origin", Point{1, 1})
+	g.Set("far", Point{1e30, -1e30})
+	g.Set("inf", Point{math.Inf(1), math.Inf(-1)})
+
+	assert.Equal(t, []string{"far", "inf", "origin"}, keys(g.Within(Rect{Point{math.Inf(-1), math.Inf(-1)}, Point{math.Inf(1), math.Inf(1)}})))
+	assert.Equal(t, []string{"far"}, keys(g.Within(Rect{Point{1e29, -1e31}, Point{1e31, -1e29}})))
+	item, ok := g.Nearest(Point{2e30, -2e30})
+	assert.True(t, ok)
+	assert.Equal(t, "far", item.Key)
+	_, ok = g.Nearest(Point{math.Inf(1), math.Inf(-1)})
+	assert.True(t, ok)
+}
+
+func TestSpatialGridRejectsInvalidInput(t *testing.T) {
+	for _, size := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		assert.Panics(t, func() { NewSpatialGrid[string](size) }, "cell size %v", size)
+	}
+	g := NewSpatialGrid[string](10)
+	assert.Panics(t, func() { g.Set("a", Point{math.NaN(), 0}) })
+	assert.Panics(t, func() { g.Set("a", Point{0, math.NaN()}) })
+	_, ok := g.Position("a")
+	assert.False(t, ok)
+}
+
+func TestSpatialGridQueriesDuringMoves(t *testing.T) {
+	g := NewSpatialGrid[int](10)
+	for i := 0; i < 8; i++ {
+		g.Set(i, Point{float64(i), float64(i)})
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Points always lie on the diagonal
+			d := float64(i % 40)
+			g.Set(i%8, Point{d, d})
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		for _, item := range g.Within(Rect{Point{0, 0}, Point{40, 40}}) {
+			assert.Equal(t, item.At.X, item.At.Y)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package seqmut
+
+import "sync/atomic"
+
+type snapshot[T any] struct {
+	value T
+	stamp Stamp
+}
+
+// RetainSnapshots makes every subsequent write keep an immutable deep copy
+// (see Clone) of the committed value on the side, at the cost of the copy. The
+// relaxed read modes use it to answer without waiting out a writer.
+func (p *Protected[T]) RetainSnapshots() {
+	p.lock()
+	p.retained.Store(&snapshot[T]{})
+	p.unlock()
+}
+
+// LoadWithin is a relaxed-consistency read: the returned value is never torn,
+// but it may be up to maxVersions committed writes behind the latest one.
+// If an optimistic read fails and the retained snapshot is recent enough it is
+// returned instead of retrying. Without RetainSnapshots, LoadWithin behaves
+// like LoadStamped.
+func (p *Protected[T]) LoadWithin(maxVersions uint64) (T, Stamp) {
+	var v T
+	stamp := p.rw.RStamp()
+	for {
+		v = p.value
+		if p.rw.Ok(stamp) {
+			return v, p.rw.plain(stamp)
+		}
+		if snap := p.snapshot(); snap != nil && versionsSince(snap.stamp, Stamp(atomic.LoadUint64(&p.rw.sequence))) <= maxVersions {
+			return snap.value, snap.stamp
+		}
+	}
+}
+
+func (p *Protected[T]) snapshot() *snapshot[T] {
+	snap, _ := p.retained.Load().(*snapshot[T])
+	return snap
+}
+
+// versionsSince returns the number of writes committed between two stamps.
+// A write in progress at current is not counted.
+func versionsSince(from, current Stamp) uint64 {
+	return uint64(current-from) / 2
+}
+
+// ReadStale returns the retained snapshot of the last committed value without
+// touching the lock, so it is wait-free: it never retries, whatever writers
+// are doing. The value may be one write behind a writer that is still in
+// progress, or that is just about to publish.
+//
+// ReadStale requires RetainSnapshots; without it, it falls back to a regular
+// LoadStamped.
+func (p *Protected[T]) ReadStale() (T, Stamp) {
+	if snap := p.snapshot(); snap != nil {
+		return snap.value, snap.stamp
+	}
+	return p.LoadStamped()
+}
+
+// LoadRealtime bounds the latency of a read, for audio callbacks, control
+// loops and other code that cannot retry indefinitely however rarely that
+// happens: it makes at most maxAttempts optimistic attempts, and if all of
+// them race a writer, returns the retained snapshot of the last committed
+// value instead, with stale set. A stale value is never torn, but may be one
+// write behind.
+//
+// The bound requires RetainSnapshots; without it, LoadRealtime keeps
+// retrying like LoadStamped once the attempts run out.
+func (p *Protected[T]) LoadRealtime(maxAttempts int) (v T, stamp Stamp, stale bool) {
+	s := p.rw.RStamp()
+	for attempt := 1; ; attempt++ {
+		v = p.value
+		if p.rw.Ok(s) {
+			return v, p.rw.plain(s), false
+		}
+		if attempt >= maxAttempts {
+			if snap := p.snapshot(); snap != nil {
+				return snap.value, snap.stamp, true
+			}
+		}
+	}
+}
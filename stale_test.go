@@ -0,0 +1,105 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLoadWithinWithoutWriters(t *testing.T) {
+	p := NewProtected(1)
+	p.RetainSnapshots()
+
+	v, stamp := p.LoadWithin(0)
+
+	assert.Equal(t, 1, v)
+	assert.Equal(t, Stamp(2), stamp)
+}
+
+func TestLoadWithinReturnsSnapshotWhileWriterActive(t *testing.T) {
+	p := NewProtected(1)
+	p.RetainSnapshots()
+	p.Store(2)
+
+	p.rw.Lock()
+	p.value = 3
+
+	v, stamp := p.LoadWithin(1)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, Stamp(4), stamp)
+
+	p.unlock()
+}
+
+func TestLoadWithinRejectsTooStaleSnapshot(t *testing.T) {
+	p := NewProtected(1)
+	p.RetainSnapshots()
+
+	// Sneak a committed write past the snapshot machinery so the snapshot falls behind
+	p.rw.Lock()
+	p.value = 2
+	p.rw.Unlock()
+	p.rw.Lock()
+
+	done := make(chan int)
+	go func() {
+		v, _ := p.LoadWithin(0)
+		done <- v
+	}()
+
+	p.value = 3
+	p.unlock()
+	assert.Equal(t, 3, <-done)
+}
+
+func TestVersionsSinceAcrossWrapAround(t *testing.T) {
+	assert.Equal(t, uint64(0), versionsSince(4, 4))
+	assert.Equal(t, uint64(0), versionsSince(4, 5))
+	assert.Equal(t, uint64(1), versionsSince(4, 6))
+	assert.Equal(t, uint64(2), versionsSince(Stamp(MaxUint64-1), 2))
+}
+
+func TestReadStaleReturnsLastCommittedValue(t *testing.T) {
+	p := NewProtected(1)
+	p.RetainSnapshots()
+	p.Store(2)
+
+	p.rw.Lock()
+	p.value = 3
+	v, stamp := p.ReadStale()
+	p.unlock()
+
+	assert.Equal(t, 2, v)
+	assert.Equal(t, Stamp(4), stamp)
+
+	v, _ = p.ReadStale()
+	assert.Equal(t, 3, v)
+}
+
+func TestReadStaleWithoutRetainedSnapshots(t *testing.T) {
+	p := NewProtected(1)
+
+	v, stamp := p.ReadStale()
+
+	assert.Equal(t, 1, v)
+	assert.Equal(t, Stamp(0), stamp)
+}
+
+func TestLoadRealtimeFallsBackToSnapshot(t *testing.T) {
+	p := NewProtected(1)
+	p.RetainSnapshots()
+	p.Store(2)
+
+	v, stamp, stale := p.LoadRealtime(3)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, Stamp(4), stamp)
+	assert.False(t, stale)
+
+	p.rw.Lock()
+	p.value = 3
+	v, stamp, stale = p.LoadRealtime(3)
+	p.unlock()
+
+	assert.Equal(t, 2, v)
+	assert.Equal(t, Stamp(4), stamp)
+	assert.True(t, stale)
+}
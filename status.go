@@ -0,0 +1,43 @@
+package seqmut
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// LockStatus is a point-in-time view of an RWMutex, for diagnostics.
+type LockStatus struct {
+	// Name the lock was registered under, if any
+	Name string
+	// Current sequence; odd while a writer is active
+	Sequence Stamp
+	// True if a writer held the lock when the status was taken
+	WriterActive bool
+	// Optimistic reads sent around the loop again, see Retries
+	Retries uint64
+	// Readers blocked in OkOrPark
+	Parked int
+	// Stack of the active writer; only available in debug builds
+	WriterStack string
+}
+
+// Status returns the current state of the lock.
+func (rw *RWMutex) Status() LockStatus {
+	seq := Stamp(atomic.LoadUint64(&rw.sequence))
+	s := LockStatus{
+		Sequence:     seq,
+		WriterActive: seq&1 == 1,
+		Retries:      atomic.LoadUint64(&rw.retries),
+		Parked:       int(atomic.LoadInt32(&rw.parked)),
+	}
+	if stack := rw.writerStack.Load(); stack != nil {
+		s.WriterStack = *stack
+	}
+	return s
+}
+
+func (rw *RWMutex) recordWriterStack() {
+	buf := make([]byte, 4096)
+	stack := string(buf[:runtime.Stack(buf, false)])
+	rw.writerStack.Store(&stack)
+}
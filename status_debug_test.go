@@ -0,0 +1,21 @@
+//go:build seqmutdebug
+// +build seqmutdebug
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestStatusReportsWriterStack(t *testing.T) {
+	var rw RWMutex
+	rw.Lock()
+
+	stack := rw.Status().WriterStack
+	assert.True(t, strings.Contains(stack, "TestStatusReportsWriterStack"), stack)
+
+	rw.Unlock()
+	assert.Equal(t, "", rw.Status().WriterStack)
+}
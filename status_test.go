@@ -0,0 +1,23 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	var rw RWMutex
+	rw.Lock()
+	rw.Unlock()
+	stamp := rw.RStamp()
+	rw.Lock()
+
+	assert.False(t, rw.Ok(stamp))
+	s := rw.Status()
+	assert.Equal(t, Stamp(3), s.Sequence)
+	assert.True(t, s.WriterActive)
+	assert.Equal(t, uint64(1), s.Retries)
+	rw.Unlock()
+
+	assert.False(t, rw.Status().WriterActive)
+}
@@ -0,0 +1,102 @@
+package seqmut
+
+import (
+	"hash/maphash"
+	"sort"
+	"sync/atomic"
+)
+
+const mapStripes = 64
+
+var stripeSeed = maphash.MakeSeed()
+
+// StripedMap has the method set of sync.Map, but spreads its keys over a
+// fixed number of SeqMaps, each with its own lock. Loads are optimistic
+// reads that never write to shared memory, so read-mostly workloads with hot
+// keys scale without sync.Map's promotion between its read-only and dirty
+// maps. Writes only contend with writes to keys in the same stripe.
+//
+// As with sync.Map, keys must be comparable, and the zero value is an empty
+// map ready to use.
+type StripedMap struct {
+	stripes [mapStripes]SeqMap[any, any]
+}
+
+func (m *StripedMap) stripe(key any) *SeqMap[any, any] {
+	return &m.stripes[maphash.Comparable(stripeSeed, key)%mapStripes]
+}
+
+// Load returns the value stored for key, if any.
+func (m *StripedMap) Load(key any) (value any, ok bool) {
+	return m.stripe(key).Load(key)
+}
+
+// Store sets the value for key.
+func (m *StripedMap) Store(key, value any) {
+	m.stripe(key).Store(key, value)
+}
+
+// LoadOrStore returns the existing value for key if there is one. Otherwise
+// it stores value and returns it. loaded is true if the value was loaded.
+func (m *StripedMap) LoadOrStore(key, value any) (actual any, loaded bool) {
+	return m.stripe(key).LoadOrStore(key, value)
+}
+
+// LoadAndDelete removes key, returning the value it had, if any.
+func (m *StripedMap) LoadAndDelete(key any) (value any, loaded bool) {
+	return m.stripe(key).LoadAndDelete(key)
+}
+
+// Delete removes key from the map.
+func (m *StripedMap) Delete(key any) {
+	m.stripe(key).Delete(key)
+}
+
+// Range calls f for each entry, stopping if f returns false. Each stripe is
+// iterated over a consistent snapshot, but as with sync.Map there is no
+// snapshot of the map as a whole.
+func (m *StripedMap) Range(f func(key, value any) bool) {
+	for i := range m.stripes {
+		for k, v := range m.stripes[i].All() {
+			if !f(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// StripeHeat is the contention recorded on one stripe of a StripedMap.
+type StripeHeat struct {
+	Stripe int
+	// Writes committed to the stripe
+	Writes uint64
+	// Optimistic reads of the stripe that had to retry
+	Retries uint64
+	// Entries currently in the stripe
+	Len int
+}
+
+// Hotspots reports the contention on every stripe, hottest first: most
+// retries, then most writes. If a few stripes account for most of it, a few
+// hot keys are defeating the striping, and the data probably wants a
+// different layout. The counters are the ones every RWMutex keeps anyway, so
+// there is nothing to turn on.
+func (m *StripedMap) Hotspots() []StripeHeat {
+	heat := make([]StripeHeat, len(m.stripes))
+	for i := range m.stripes {
+		s := &m.stripes[i]
+		heat[i] = StripeHeat{
+			Stripe:  i,
+			Writes:  versionsSince(0, Stamp(atomic.LoadUint64(&s.rw.sequence))),
+			Retries: s.rw.Retries(),
+			Len:     s.Len(),
+		}
+	}
+	sort.SliceStable(heat, func(i, j int) bool {
+		if heat[i].Retries != heat[j].Retries {
+			return heat[i].Retries > heat[j].Retries
+		}
+		return heat[i].Writes > heat[j].Writes
+	})
+	return heat
+}
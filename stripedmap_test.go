@@ -0,0 +1,97 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// syncMap is the part of sync.Map's method set StripedMap mirrors
+type syncMap interface {
+	Load(key any) (any, bool)
+	Store(key, value any)
+	LoadOrStore(key, value any) (any, bool)
+	LoadAndDelete(key any) (any, bool)
+	Delete(key any)
+	Range(f func(key, value any) bool)
+}
+
+var (
+	_ syncMap = (*StripedMap)(nil)
+	_ syncMap = (*sync.Map)(nil)
+)
+
+func TestStripedMap(t *testing.T) {
+	var m StripedMap
+	for i := 0; i < 100; i++ {
+		m.Store(i, strconv.Itoa(i))
+	}
+
+	v, ok := m.Load(42)
+	assert.True(t, ok)
+	assert.Equal(t, "42", v)
+
+	actual, loaded := m.LoadOrStore(42, "x")
+	assert.True(t, loaded)
+	assert.Equal(t, "42", actual)
+	actual, loaded = m.LoadOrStore("new", "x")
+	assert.False(t, loaded)
+	assert.Equal(t, "x", actual)
+
+	v, loaded = m.LoadAndDelete(42)
+	assert.True(t, loaded)
+	assert.Equal(t, "42", v)
+	m.Delete("new")
+	_, ok = m.Load(42)
+	assert.False(t, ok)
+
+	n := 0
+	m.Range(func(k, v any) bool {
+		assert.Equal(t, strconv.Itoa(k.(int)), v)
+		n++
+		return true
+	})
+	assert.Equal(t, 99, n)
+}
+
+func benchmarkMapHotKeys(b *testing.B, m syncMap) {
+	for i := 0; i < 16; i++ {
+		m.Store(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%1000 == 0 {
+				m.Store(i%16, i)
+			} else {
+				m.Load(i % 16)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkStripedMapHotKeys(b *testing.B) {
+	benchmarkMapHotKeys(b, &StripedMap{})
+}
+
+func BenchmarkSyncMapHotKeys(b *testing.B) {
+	benchmarkMapHotKeys(b, &sync.Map{})
+}
+
+func TestStripedMapHotspots(t *testing.T) {
+	var m StripedMap
+	for i := 0; i < 10; i++ {
+		m.Store("hot", i)
+	}
+
+	heat := m.Hotspots()
+
+	assert.Len(t, heat, mapStripes)
+	hot := m.stripe("hot")
+	assert.True(t, &m.stripes[heat[0].Stripe] == hot)
+	assert.Equal(t, uint64(10), heat[0].Writes)
+	assert.Equal(t, 1, heat[0].Len)
+}
@@ -0,0 +1,47 @@
+package seqmut
+
+import "sync"
+
+// subscribers is a set of callbacks for events of type E, kept in
+// subscription order. The zero value is empty and ready to use.
+type subscribers[E any] struct {
+	mu     sync.Mutex
+	subs   []subscriber[E]
+	nextID int
+}
+
+type subscriber[E any] struct {
+	id int
+	fn func(E)
+}
+
+// add subscribes fn, returning the function that ends the subscription.
+// Cancelling more than once is harmless.
+func (s *subscribers[E]) add(fn func(E)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.subs = append(s.subs, subscriber[E]{id, fn})
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, sub := range s.subs {
+			if sub.id == id {
+				// Copy rather than shift in place, list may have handed out
+				// the old slice
+				s.subs = append(s.subs[:i:i], s.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// list returns the current subscribers, in subscription order. Subscribing or
+// cancelling afterwards doesn't change it, so the caller can run the
+// callbacks without holding any lock.
+func (s *subscribers[E]) list() []subscriber[E] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subs
+}
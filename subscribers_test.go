@@ -0,0 +1,27 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSubscribersInSubscriptionOrder(t *testing.T) {
+	var s subscribers[int]
+	var calls []string
+	s.add(func(int) { calls = append(calls, "a") })
+	cancelB := s.add(func(int) { calls = append(calls, "b") })
+	s.add(func(int) { calls = append(calls, "c") })
+
+	listed := s.list()
+	cancelB()
+	cancelB()
+	for _, sub := range listed {
+		sub.fn(0)
+	}
+	for _, sub := range s.list() {
+		sub.fn(0)
+	}
+
+	// Cancelling doesn't disturb a list already handed out
+	assert.Equal(t, []string{"a", "b", "c", "a", "c"}, calls)
+}
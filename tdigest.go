@@ -0,0 +1,196 @@
+package seqmut
+
+import (
+	"math"
+	"sort"
+)
+
+// Centroid is a cluster of samples in a TDigest.
+type Centroid struct {
+	Mean, Weight float64
+}
+
+// TDigest estimates quantiles of a stream of samples, such as request
+// latencies for SLO reporting, in bounded memory: a merging t-digest
+// (Dunning and Ertl), which is most accurate towards the extreme quantiles.
+// Add buffers samples and folds them into the centroids under the write lock;
+// Quantile and Export work on a validated optimistic copy, so reports never
+// take a global metrics mutex.
+type TDigest struct {
+	rw          RWMutex
+	compression float64
+	// Fixed size, with the lengths in use kept separately, so readers on a
+	// torn view stay in bounds
+	centroids  []Centroid
+	nCentroids int
+	buffer     []float64
+	nBuffer    int
+	min, max   float64
+	// Only used by writers while merging
+	scratch []Centroid
+}
+
+// NewTDigest returns an empty digest. Higher compression keeps more
+// centroids and gives more accurate quantiles; 100 is typical.
+func NewTDigest(compression float64) *TDigest {
+	size := 2*int(math.Ceil(compression)) + 8
+	return &TDigest{
+		compression: compression,
+		centroids:   make([]Centroid, size),
+		buffer:      make([]float64, 5*size),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add records a sample.
+func (d *TDigest) Add(x float64) {
+	d.rw.Lock()
+	defer d.rw.Unlock()
+	d.buffer[d.nBuffer] = x
+	d.nBuffer++
+	d.min, d.max = min(d.min, x), max(d.max, x)
+	if d.nBuffer == len(d.buffer) {
+		d.scratch = mergeCentroids(d.scratch[:0], d.centroids[:d.nCentroids], d.buffer, d.compression)
+		d.nCentroids = copy(d.centroids, d.scratch)
+		d.nBuffer = 0
+	}
+}
+
+// mergeCentroids merges centroids and samples into dst, sorted by mean, with
+// each centroid spanning at most one unit of the k1 scale function.
+func mergeCentroids(dst, centroids []Centroid, samples []float64, compression float64) []Centroid {
+	all := make([]Centroid, 0, len(centroids)+len(samples))
+	all = append(all, centroids...)
+	var total float64
+	for _, c := range centroids {
+		total += c.Weight
+	}
+	for _, x := range samples {
+		all = append(all, Centroid{x, 1})
+	}
+	total += float64(len(samples))
+	if len(all) == 0 {
+		return dst
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Mean < all[j].Mean })
+
+	k := func(q float64) float64 {
+		return compression / (2 * math.Pi) * math.Asin(2*q-1)
+	}
+	cur := all[0]
+	var before float64
+	kLeft := k(0)
+	for _, c := range all[1:] {
+		if k((before+cur.Weight+c.Weight)/total)-kLeft <= 1 {
+			w := cur.Weight + c.Weight
+			cur.Mean += (c.Mean - cur.Mean) * c.Weight / w
+			cur.Weight = w
+			continue
+		}
+		dst = append(dst, cur)
+		before += cur.Weight
+		kLeft = k(before / total)
+		cur = c
+	}
+	return append(dst, cur)
+}
+
+// snapshotAttempts is how many optimistic attempts a TDigest snapshot makes
+// before holding writers off.
+const snapshotAttempts = 4
+
+// snapshot returns the digest's centroids, with any buffered samples merged
+// in, and its extremes. A steady stream of Adds can keep optimistic reads
+// from ever validating, so after a few attempts it holds writers off while
+// it copies, as SeqMap does for its snapshots, without disturbing other
+// readers.
+func (d *TDigest) snapshot() (centroids []Centroid, lo, hi float64) {
+	var samples []float64
+	copyOut := func() {
+		n, nb := min(d.nCentroids, len(d.centroids)), min(d.nBuffer, len(d.buffer))
+		centroids = append(centroids[:0], d.centroids[:n]...)
+		samples = append(samples[:0], d.buffer[:nb]...)
+		lo, hi = d.min, d.max
+	}
+	stamp := d.rw.RStamp()
+	ok := false
+	for attempt := 0; attempt < snapshotAttempts && !ok; attempt++ {
+		copyOut()
+		ok = d.rw.Ok(stamp)
+	}
+	if !ok {
+		d.rw.acquire()
+		copyOut()
+		d.rw.release()
+	}
+	if len(samples) > 0 {
+		centroids = mergeCentroids(nil, centroids, samples, d.compression)
+	}
+	return centroids, lo, hi
+}
+
+// Export returns the centroids, ordered by mean, for shipping to an
+// aggregator.
+func (d *TDigest) Export() []Centroid {
+	centroids, _, _ := d.snapshot()
+	return centroids
+}
+
+// Count returns the number of samples added.
+func (d *TDigest) Count() float64 {
+	var total float64
+	for _, c := range d.Export() {
+		total += c.Weight
+	}
+	return total
+}
+
+// Quantile returns an estimate of the q quantile, 0 <= q <= 1, or NaN if no
+// samples have been added.
+func (d *TDigest) Quantile(q float64) float64 {
+	centroids, lo, hi := d.snapshot()
+	return quantile(centroids, lo, hi, q)
+}
+
+// Quantiles returns estimates of several quantiles, as with Quantile, all
+// from the same samples, so that they are consistent with each other even as
+// samples keep arriving.
+func (d *TDigest) Quantiles(qs ...float64) []float64 {
+	centroids, lo, hi := d.snapshot()
+	estimates := make([]float64, len(qs))
+	for i, q := range qs {
+		estimates[i] = quantile(centroids, lo, hi, q)
+	}
+	return estimates
+}
+
+func quantile(centroids []Centroid, lo, hi, q float64) float64 {
+	if len(centroids) == 0 {
+		return math.NaN()
+	}
+	var total float64
+	for _, c := range centroids {
+		total += c.Weight
+	}
+	target := q * total
+	// Each centroid's mean is taken to sit at the middle of its weight, and
+	// quantiles in between are interpolated
+	prevMean, prevPos := lo, 0.0
+	var cum float64
+	for _, c := range centroids {
+		pos := cum + c.Weight/2
+		if target < pos {
+			if pos == prevPos {
+				return c.Mean
+			}
+			return prevMean + (c.Mean-prevMean)*(target-prevPos)/(pos-prevPos)
+		}
+		prevMean, prevPos = c.Mean, pos
+		cum += c.Weight
+	}
+	if total == prevPos {
+		return hi
+	}
+	return prevMean + (hi-prevMean)*(target-prevPos)/(total-prevPos)
+}
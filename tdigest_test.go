@@ -0,0 +1,64 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+func TestTDigestQuantilesOfUniformSamples(t *testing.T) {
+	d := NewTDigest(100)
+	assert.True(t, math.IsNaN(d.Quantile(0.5)))
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 100000; i++ {
+		d.Add(r.Float64() * 1000)
+	}
+
+	assert.Equal(t, float64(100000), d.Count())
+	assert.InDelta(t, 500, d.Quantile(0.5), 10)
+	assert.InDelta(t, 990, d.Quantile(0.99), 2)
+	assert.InDelta(t, 999, d.Quantile(0.999), 0.5)
+	assert.InDelta(t, 0, d.Quantile(0), 0.5)
+	assert.Equal(t, []float64{d.Quantile(0.5), d.Quantile(0.99)}, d.Quantiles(0.5, 0.99))
+	assert.True(t, len(d.Export()) < 250, "%d centroids", len(d.Export()))
+}
+
+func TestTDigestIncludesBufferedSamples(t *testing.T) {
+	d := NewTDigest(100)
+	d.Add(1)
+	d.Add(2)
+	d.Add(3)
+
+	assert.Equal(t, 2.0, d.Quantile(0.5))
+	assert.Equal(t, []Centroid{{1, 1}, {2, 1}, {3, 1}}, d.Export())
+}
+
+func TestTDigestReadsDuringAdds(t *testing.T) {
+	d := NewTDigest(50)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	// A writer that never pauses; readers fall back to holding it off
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			d.Add(float64(i % 100))
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		if q := d.Quantile(0.5); !math.IsNaN(q) {
+			assert.True(t, q >= 0 && q <= 99, "%v", q)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,146 @@
+package seqmut
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Token is a credential with an expiry, such as an OAuth access token.
+type Token struct {
+	Value  string
+	Expiry time.Time
+}
+
+// TokenCache caches an auth token, refreshing it shortly before it expires.
+// Get is an optimistic read of the token and its expiry. When the token is
+// due for refresh, every caller that noticed races to LockIfUnchanged on the
+// version it read: exactly one wins and fetches a new token, while the rest
+// keep using the current one as long as it is still valid, or wait for the
+// winner if it is not. Concurrent callers never stampede the token endpoint.
+//
+// The fetch is shared, so it is not cancelled when the caller running it
+// gives up; fetch should apply its own timeout.
+type TokenCache struct {
+	refresh refresher
+	token   Token
+
+	fetch func(ctx context.Context) (Token, error)
+	// How long before expiry to refresh
+	margin time.Duration
+}
+
+// NewTokenCache returns a cache fetching tokens with fetch, margin before the
+// current one expires.
+func NewTokenCache(margin time.Duration, fetch func(ctx context.Context) (Token, error)) *TokenCache {
+	return &TokenCache{margin: margin, fetch: fetch}
+}
+
+// Get returns a valid token, fetching one first if need be. It returns the
+// fetch error if it had to wait for a refresh that failed, or ran one.
+func (c *TokenCache) Get(ctx context.Context) (string, error) {
+	var token, fetched Token
+	err := c.refresh.get(ctx,
+		func() (fresh, usable bool) {
+			token = c.token
+			now := time.Now()
+			return now.Add(c.margin).Before(token.Expiry), now.Before(token.Expiry)
+		},
+		func(ctx context.Context) (err error) {
+			fetched, err = c.fetch(ctx)
+			return err
+		},
+		func(err error) {
+			if err == nil {
+				c.token, token = fetched, fetched
+			}
+		})
+	if err != nil {
+		return "", err
+	}
+	return token.Value, nil
+}
+
+var errRefreshPanicked = errors.New("seqmut: refresh panicked")
+
+// refresher deduplicates refreshes of a cached value guarded by its rw. Every
+// caller that finds the value due for refresh races to LockIfUnchanged on
+// the version it read: exactly one wins and fetches, while the rest keep
+// using the current value as long as it is usable, or wait for the winner if
+// it is not.
+type refresher struct {
+	rw RWMutex
+	// Closed when the refresh in progress, if any, finishes
+	refreshing chan struct{}
+	lastErr    error
+}
+
+// get returns once the value is fresh, or usable while someone else
+// refreshes it, running the refresh itself if it wins the race to. read is
+// called in an optimistic read, and must copy out what the caller needs and
+// report whether that is fresh, and if not whether it is still usable. get
+// returns the fetch error if it had to wait for a refresh that failed, or ran
+// one, and the context's error if it gave up waiting.
+func (r *refresher) get(ctx context.Context, read func() (fresh, usable bool), fetch func(ctx context.Context) error, commit func(err error)) error {
+	waited := false
+	for {
+		var fresh, usable bool
+		var refreshing chan struct{}
+		var err error
+		stamp := r.rw.RStamp()
+		for {
+			fresh, usable = read()
+			refreshing, err = r.refreshing, r.lastErr
+			if r.rw.Ok(stamp) {
+				break
+			}
+		}
+
+		if fresh {
+			return nil
+		}
+		if refreshing == nil {
+			if waited && err != nil {
+				// The refresh we waited for failed
+				return err
+			}
+			if r.rw.LockIfUnchanged(*stamp) == nil {
+				return r.run(ctx, fetch, commit)
+			}
+			// Someone else got there first
+			continue
+		}
+		if usable {
+			return nil
+		}
+		select {
+		case <-refreshing:
+			waited = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// run fetches a new value; must be called holding the write lock, which it
+// releases while fetching, then takes again to call commit with the fetch
+// error, for it to store the result. Others are waiting on the fetch too, so
+// it is detached from the cancellation of ctx, and a fetch that panics fails
+// the refresh for all of them, rather than leaving them waiting forever.
+func (r *refresher) run(ctx context.Context, fetch func(ctx context.Context) error, commit func(err error)) (err error) {
+	done := make(chan struct{})
+	r.refreshing = done
+	r.rw.Unlock()
+
+	err = errRefreshPanicked
+	defer func() {
+		r.rw.Lock()
+		r.refreshing = nil
+		r.lastErr = err
+		commit(err)
+		r.rw.Unlock()
+		close(done)
+	}()
+	err = fetch(context.WithoutCancel(ctx))
+	return err
+}
@@ -0,0 +1,129 @@
+package seqmut
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenCacheFetchesOnceForConcurrentCallers(t *testing.T) {
+	var fetches atomic.Int64
+	release := make(chan struct{})
+	c := NewTokenCache(time.Minute, func(ctx context.Context) (Token, error) {
+		fetches.Add(1)
+		<-release
+		return Token{Value: "t1", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.Get(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "t1", v)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), fetches.Load())
+}
+
+func TestTokenCacheRefreshesWithinMargin(t *testing.T) {
+	n := 0
+	c := NewTokenCache(time.Minute, func(ctx context.Context) (Token, error) {
+		n++
+		// The first token is already due for refresh, but still valid
+		expiry := time.Now().Add(30 * time.Second)
+		if n > 1 {
+			expiry = time.Now().Add(time.Hour)
+		}
+		return Token{Value: string(rune('0' + n)), Expiry: expiry}, nil
+	})
+
+	v, _ := c.Get(context.Background())
+	assert.Equal(t, "1", v)
+	v, _ = c.Get(context.Background())
+	assert.Equal(t, "2", v)
+	v, _ = c.Get(context.Background())
+	assert.Equal(t, "2", v)
+}
+
+func TestTokenCacheReturnsFetchError(t *testing.T) {
+	boom := errors.New("boom")
+	c := NewTokenCache(time.Minute, func(ctx context.Context) (Token, error) {
+		return Token{}, boom
+	})
+
+	_, err := c.Get(context.Background())
+
+	assert.Equal(t, boom, err)
+}
+
+func TestTokenCachePanickingFetchReleasesWaiters(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var fetches atomic.Int64
+	c := NewTokenCache(time.Minute, func(ctx context.Context) (Token, error) {
+		if fetches.Add(1) == 1 {
+			close(started)
+			<-release
+			panic("fetch bug")
+		}
+		return Token{Value: "t2", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+
+	go func() {
+		defer func() { recover() }()
+		c.Get(context.Background())
+	}()
+	<-started
+	waiter := make(chan error)
+	go func() {
+		_, err := c.Get(context.Background())
+		waiter <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, errRefreshPanicked, <-waiter)
+	v, err := c.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "t2", v)
+}
+
+func TestTokenCacheFetchOutlivesCancelledCaller(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	c := NewTokenCache(time.Minute, func(ctx context.Context) (Token, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return Token{}, err
+		}
+		return Token{Value: "t1", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := c.Get(ctx)
+		first <- err
+	}()
+	<-started
+	waiter := make(chan string)
+	go func() {
+		v, _ := c.Get(context.Background())
+		waiter <- v
+	}()
+	cancel()
+	close(release)
+
+	assert.NoError(t, <-first)
+	assert.Equal(t, "t1", <-waiter)
+}
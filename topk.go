@@ -0,0 +1,79 @@
+package seqmut
+
+import "sort"
+
+// HeavyHitter is a key tracked by TopK with its estimated count. The true
+// count lies between Count-Error and Count.
+type HeavyHitter[K comparable] struct {
+	Key   K
+	Count uint64
+	Error uint64
+}
+
+// TopK tracks the most frequent keys in a stream using the space-saving
+// algorithm, in a fixed number of counters. Updates take the write lock;
+// TopK reads are optimistic, so dashboards can poll "what are my hottest
+// keys right now" as often as they like without slowing the writers down.
+type TopK[K comparable] struct {
+	rw RWMutex
+	// Only touched by writers; a map cannot be read optimistically
+	index map[K]int
+	// Fixed backing array, of which the first used entries are live
+	items []HeavyHitter[K]
+	used  int
+}
+
+// NewTopK returns a tracker with the given number of counters. Keys whose
+// frequency is above 1/capacity of the stream are guaranteed to be tracked.
+func NewTopK[K comparable](capacity int) *TopK[K] {
+	if capacity < 1 {
+		panic("seqmut: TopK needs at least one counter")
+	}
+	return &TopK[K]{index: make(map[K]int, capacity), items: make([]HeavyHitter[K], capacity)}
+}
+
+// Add counts n occurrences of key.
+func (t *TopK[K]) Add(key K, n uint64) {
+	t.rw.Lock()
+	defer t.rw.Unlock()
+	if i, ok := t.index[key]; ok {
+		t.items[i].Count += n
+		return
+	}
+	if t.used < len(t.items) {
+		t.items[t.used] = HeavyHitter[K]{Key: key, Count: n}
+		t.index[key] = t.used
+		t.used++
+		return
+	}
+	// Evict the smallest counter; the newcomer inherits its count as error
+	min := 0
+	for i := range t.items {
+		if t.items[i].Count < t.items[min].Count {
+			min = i
+		}
+	}
+	evicted := t.items[min]
+	delete(t.index, evicted.Key)
+	t.items[min] = HeavyHitter[K]{Key: key, Count: evicted.Count + n, Error: evicted.Count}
+	t.index[key] = min
+}
+
+// TopK returns up to k tracked keys, most frequent first, as of a single
+// point in time.
+func (t *TopK[K]) TopK(k int) []HeavyHitter[K] {
+	var out []HeavyHitter[K]
+	stamp := t.rw.RStamp()
+	for {
+		used := t.used
+		if used > len(t.items) {
+			used = len(t.items)
+		}
+		out = append(out[:0], t.items[:used]...)
+		if t.rw.Ok(stamp) {
+			break
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+	return out[:max(0, min(k, len(out)))]
+}
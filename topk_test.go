@@ -0,0 +1,30 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	tk := NewTopK[string](3)
+	tk.Add("a", 10)
+	tk.Add("b", 5)
+	tk.Add("c", 1)
+	tk.Add("a", 1)
+
+	top := tk.TopK(2)
+
+	assert.Equal(t, []HeavyHitter[string]{{Key: "a", Count: 11}, {Key: "b", Count: 5}}, top)
+	assert.Empty(t, tk.TopK(-1))
+}
+
+func TestTopKEvictsSmallest(t *testing.T) {
+	tk := NewTopK[string](2)
+	tk.Add("a", 10)
+	tk.Add("b", 2)
+	tk.Add("c", 1)
+
+	top := tk.TopK(10)
+
+	assert.Equal(t, []HeavyHitter[string]{{Key: "a", Count: 10}, {Key: "c", Count: 3, Error: 2}}, top)
+}
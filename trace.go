@@ -0,0 +1,75 @@
+package seqmut
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Access describes one read of guarded state, recorded on a context by
+// RecordAccess.
+type Access struct {
+	// Name the caller gave the guarded state
+	Name string
+	// file:line of the read
+	Site string
+	// Version that was read
+	Stamp Stamp
+}
+
+func (a Access) String() string {
+	return fmt.Sprintf("%s@%d (%s)", a.Name, a.Stamp, a.Site)
+}
+
+type accessLog struct {
+	mu       sync.Mutex
+	accesses []Access
+}
+
+type accessLogKey struct{}
+
+// WithAccessLog returns a context that collects Access records, so that
+// request-scoped logging and tracing can report which guarded state a request
+// touched, and at which version. It helps explain stale-read complaints.
+func WithAccessLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, accessLogKey{}, &accessLog{})
+}
+
+// RecordAccess notes that the caller read the state called name at stamp. It
+// does nothing unless ctx came from WithAccessLog.
+func RecordAccess(ctx context.Context, name string, stamp Stamp) {
+	recordAccess(ctx, name, stamp, 2)
+}
+
+func recordAccess(ctx context.Context, name string, stamp Stamp, skip int) {
+	log, _ := ctx.Value(accessLogKey{}).(*accessLog)
+	if log == nil {
+		return
+	}
+	site := "unknown"
+	if _, file, line, ok := runtime.Caller(skip); ok {
+		site = fmt.Sprintf("%s:%d", file, line)
+	}
+	log.mu.Lock()
+	log.accesses = append(log.accesses, Access{Name: name, Site: site, Stamp: stamp})
+	log.mu.Unlock()
+}
+
+// Accesses returns the accesses recorded on ctx so far, in order.
+func Accesses(ctx context.Context) []Access {
+	log, _ := ctx.Value(accessLogKey{}).(*accessLog)
+	if log == nil {
+		return nil
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return append([]Access(nil), log.accesses...)
+}
+
+// LoadTraced is LoadStamped, recording the access on ctx under name.
+func LoadTraced[T any](ctx context.Context, name string, p *Protected[T]) T {
+	v, stamp := p.LoadStamped()
+	recordAccess(ctx, name, stamp, 2)
+	return v
+}
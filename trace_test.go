@@ -0,0 +1,34 @@
+package seqmut
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	config := NewProtected("v1")
+	config.Store("v2")
+	ctx := WithAccessLog(context.Background())
+
+	v := LoadTraced(ctx, "config", config)
+	RecordAccess(ctx, "other", 8)
+
+	assert.Equal(t, "v2", v)
+	accesses := Accesses(ctx)
+	assert.Len(t, accesses, 2)
+	assert.Equal(t, "config", accesses[0].Name)
+	assert.Equal(t, Stamp(2), accesses[0].Stamp)
+	assert.True(t, strings.Contains(accesses[0].Site, "trace_test.go"), accesses[0].Site)
+	assert.Equal(t, "other", accesses[1].Name)
+	assert.True(t, strings.HasPrefix(accesses[1].String(), "other@8 ("))
+}
+
+func TestAccessLogOptional(t *testing.T) {
+	ctx := context.Background()
+
+	RecordAccess(ctx, "config", 0)
+
+	assert.Nil(t, Accesses(ctx))
+}
@@ -0,0 +1,85 @@
+package seqmut
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// VersionBus is a set of named generation counters for coordinating cache
+// invalidation. Writers bump a topic when they commit, typically via
+// Protected.BumpOnCommit, and every cache derived from that data checks
+// whether it is still current with a single atomic load, rather than each
+// keeping its own ad-hoc invalidation flag.
+//
+// The zero value is ready to use.
+type VersionBus struct {
+	topics ObjectRegistry[*Topic]
+	mu     sync.Mutex
+}
+
+// Topic is one generation counter on a VersionBus.
+type Topic struct {
+	name       string
+	generation atomic.Uint64
+}
+
+// Topic returns the topic called name, creating it on first use. Callers on
+// hot paths should hold on to the result rather than look it up every time.
+func (b *VersionBus) Topic(name string) *Topic {
+	if t, ok := b.topics.Get(name); ok {
+		return t
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.topics.Get(name); ok {
+		return t
+	}
+	t := &Topic{name: name}
+	b.topics.Register(name, t)
+	return t
+}
+
+// Name returns the name of the topic.
+func (t *Topic) Name() string {
+	return t.name
+}
+
+// Generation returns the current generation of the topic.
+func (t *Topic) Generation() uint64 {
+	return t.generation.Load()
+}
+
+// Bump advances the topic to a new generation, returning it.
+func (t *Topic) Bump() uint64 {
+	return t.generation.Add(1)
+}
+
+// Observe returns an Observation of the topic's current generation, for a
+// dependent cache to keep alongside the data it derived.
+func (t *Topic) Observe() Observation {
+	return Observation{topic: t, generation: t.Generation()}
+}
+
+// Observation records the generation of a topic that a cache was built at.
+type Observation struct {
+	topic      *Topic
+	generation uint64
+}
+
+// Current returns true if the topic has not been bumped since the
+// observation was taken.
+func (o Observation) Current() bool {
+	return o.topic.generation.Load() == o.generation
+}
+
+// Generation returns the observed generation.
+func (o Observation) Generation() uint64 {
+	return o.generation
+}
+
+// BumpOnCommit makes every subsequent write to p bump t once it has
+// committed, so a cache that sees the new generation also sees the new
+// value. It must not be called concurrently with writes.
+func (p *Protected[T]) BumpOnCommit(t *Topic) {
+	p.topics = append(p.topics, t)
+}
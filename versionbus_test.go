@@ -0,0 +1,34 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVersionBus(t *testing.T) {
+	var bus VersionBus
+	users := bus.Topic("users")
+	assert.True(t, users == bus.Topic("users"))
+	assert.Equal(t, "users", users.Name())
+
+	o := users.Observe()
+	assert.True(t, o.Current())
+
+	users.Bump()
+	assert.False(t, o.Current())
+	assert.True(t, users.Observe().Current())
+	assert.Equal(t, uint64(1), users.Generation())
+}
+
+func TestBumpOnCommit(t *testing.T) {
+	var bus VersionBus
+	p := NewProtected(1)
+	p.BumpOnCommit(bus.Topic("a"))
+	p.BumpOnCommit(bus.Topic("b"))
+	a := bus.Topic("a").Observe()
+
+	p.Store(2)
+
+	assert.False(t, a.Current())
+	assert.Equal(t, uint64(1), bus.Topic("b").Generation())
+}
@@ -0,0 +1,33 @@
+package seqmut
+
+// View returns fn's projection of the guarded value, computed under
+// validation, so callers can read one field, or a summary, consistently
+// without copying the whole value. fn may run on torn state during attempts
+// that are retried, and its result is only returned once one validates, so it
+// must not act on what it reads or follow pointers a writer may change.
+func View[T, R any](p *Protected[T], fn func(v *T) R) R {
+	var r R
+	stamp := p.rw.RStamp()
+	for {
+		r = fn(&p.value)
+		if p.rw.Ok(stamp) {
+			return r
+		}
+		p.counter.add()
+	}
+}
+
+// ReadInto is View for projections that fill in a caller-owned destination,
+// letting a buffer be reused across reads. dst may be partially written by
+// attempts that are retried; it holds a consistent projection once ReadInto
+// returns.
+func ReadInto[T, R any](p *Protected[T], dst *R, fn func(v *T, dst *R)) {
+	stamp := p.rw.RStamp()
+	for {
+		fn(&p.value, dst)
+		if p.rw.Ok(stamp) {
+			return
+		}
+		p.counter.add()
+	}
+}
@@ -0,0 +1,57 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type account struct {
+	Name    string
+	Balance int
+	History [16]int
+}
+
+func TestView(t *testing.T) {
+	p := NewProtected(account{Name: "a", Balance: 10})
+
+	balance := View(p, func(a *account) int { return a.Balance })
+
+	assert.Equal(t, 10, balance)
+}
+
+func TestViewRetriesOnRacingWriter(t *testing.T) {
+	p := NewProtected(account{Balance: 10})
+	attempts := 0
+
+	balance := View(p, func(a *account) int {
+		attempts++
+		if attempts == 1 {
+			p.Update(func(a *account) { a.Balance = 20 })
+		}
+		return a.Balance
+	})
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 20, balance)
+}
+
+func TestReadInto(t *testing.T) {
+	p := NewProtected(account{History: [16]int{1, 2, 3}})
+	var dst []int
+
+	ReadInto(p, &dst, func(a *account, dst *[]int) {
+		*dst = append((*dst)[:0], a.History[:3]...)
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, dst)
+	if debug {
+		// Stamp provenance tracking allocates
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		ReadInto(p, &dst, func(a *account, dst *[]int) {
+			*dst = append((*dst)[:0], a.History[:3]...)
+		})
+	})
+	assert.Equal(t, float64(0), allocs)
+}
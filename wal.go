@@ -0,0 +1,210 @@
+package seqmut
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sync"
+)
+
+// WAL receives a serialized record of each logged mutation before the
+// mutation is committed, so that the guarded state can be rebuilt after a
+// restart by replaying the log.
+type WAL interface {
+	Append(record []byte) error
+}
+
+// SetWAL attaches a write-ahead log used by UpdateLogged.
+func (p *Protected[T]) SetWAL(wal WAL) {
+	p.rw.acquire()
+	p.wal = wal
+	p.rw.release()
+}
+
+// UpdateLogged appends record to the attached WAL and, once that succeeded,
+// runs fn with exclusive access to the value. record must describe the
+// mutation fn makes, in a form the replay function passed to ReplayWAL
+// understands. If the append fails, fn is not run and the error is returned.
+// Without a WAL, UpdateLogged is just Update. Like TryUpdate, it refuses
+// with ErrSealed once the value is frozen and with ErrPoisoned after a
+// writer panicked, before appending anything.
+//
+// The append happens before the write begins, holding off other writers but
+// not readers, so optimistic reads keep succeeding while the record goes to
+// disk, and a failed append leaves no trace.
+func (p *Protected[T]) UpdateLogged(record []byte, fn func(v *T)) error {
+	p.rw.acquire()
+	entered := false
+	defer func() {
+		if !entered {
+			p.rw.release()
+		}
+	}()
+	if err := p.refuse(true); err != nil {
+		return err
+	}
+	if p.wal != nil {
+		if err := p.wal.Append(record); err != nil {
+			return err
+		}
+	}
+	p.enter()
+	entered = true
+	return p.apply(fn)
+}
+
+// SyncPolicy decides, after each append, whether a FileWAL should fsync. It
+// is given the number of records appended since the last sync.
+type SyncPolicy func(unsynced int) bool
+
+// SyncAlways syncs after every record, so a committed mutation is never lost.
+func SyncAlways(int) bool { return true }
+
+// SyncNever leaves flushing to the operating system.
+func SyncNever(int) bool { return false }
+
+// SyncEvery syncs once every n records; up to n-1 committed mutations can be
+// lost in a crash.
+func SyncEvery(n int) SyncPolicy {
+	return func(unsynced int) bool { return unsynced >= n }
+}
+
+// Syncer is implemented by writers that can flush to stable storage, such as
+// *os.File.
+type Syncer interface {
+	Sync() error
+}
+
+// FileWAL frames records with a length and checksum and appends them to w,
+// syncing according to its policy if w is a Syncer.
+//
+// A failed write or sync may leave a partial frame behind, after which
+// nothing appended could be replayed, so it poisons the log: every later
+// Append fails with ErrWALPoisoned.
+type FileWAL struct {
+	mu       sync.Mutex
+	w        io.Writer
+	policy   SyncPolicy
+	unsynced int
+	// The write or sync failure that poisoned the log, if any
+	failed error
+}
+
+// NewFileWAL returns a FileWAL appending to w. A nil policy means
+// SyncAlways.
+func NewFileWAL(w io.Writer, policy SyncPolicy) *FileWAL {
+	if policy == nil {
+		policy = SyncAlways
+	}
+	return &FileWAL{w: w, policy: policy}
+}
+
+func (f *FileWAL) Append(record []byte) error {
+	if len(record) > maxWALRecord {
+		return errRecordTooLarge
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failed != nil {
+		return fmt.Errorf("%w: %w", ErrWALPoisoned, f.failed)
+	}
+
+	frame := make([]byte, 8+len(record))
+	binary.LittleEndian.PutUint32(frame, uint32(len(record)))
+	binary.LittleEndian.PutUint32(frame[4:], crc32.ChecksumIEEE(record))
+	copy(frame[8:], record)
+	if _, err := f.w.Write(frame); err != nil {
+		f.failed = err
+		return err
+	}
+
+	f.unsynced++
+	if s, ok := f.w.(Syncer); ok && f.policy(f.unsynced) {
+		if err := s.Sync(); err != nil {
+			f.failed = err
+			return err
+		}
+		f.unsynced = 0
+	}
+	return nil
+}
+
+// maxWALRecord bounds the size of a record, so a corrupt length in the log
+// cannot make replay allocate gigabytes.
+const maxWALRecord = 64 << 20
+
+var (
+	// A FileWAL append failed earlier, so the log may end in a partial frame
+	ErrWALPoisoned = errors.New("seqmut: WAL poisoned by a failed append")
+	// A record in the middle of the log is damaged, see ReplayWAL
+	ErrCorruptWAL = errors.New("seqmut: corrupt WAL record")
+
+	errTornRecord     = errors.New("seqmut: torn WAL record")
+	errRecordTooLarge = errors.New("seqmut: WAL record too large")
+)
+
+// ReplayWAL reads records written by a FileWAL and passes each to apply, in
+// order, returning the number applied. A record torn by a crash, one the log
+// ends in the middle of or a damaged last record, ends the replay without an
+// error. A damaged record with more of the log after it is not a crash
+// artifact, and fails the replay with ErrCorruptWAL rather than silently
+// dropping everything behind it. Errors from reading or from apply are
+// returned.
+func ReplayWAL(r io.Reader, apply func(record []byte) error) (int, error) {
+	n := 0
+	for {
+		record, err := readRecord(r)
+		if err == io.EOF || err == errTornRecord {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := apply(record); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+func readRecord(r io.Reader) ([]byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errTornRecord
+		}
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[:])
+	if length > maxWALRecord {
+		// No writer produced this; skip over it only to find out whether
+		// the log ends first, in which case it is a torn tail after all
+		skipped, err := io.Copy(io.Discard, io.LimitReader(r, int64(length)))
+		if err != nil {
+			return nil, err
+		}
+		if skipped < int64(length) {
+			return nil, errTornRecord
+		}
+		return nil, ErrCorruptWAL
+	}
+	// Read what is actually there rather than allocating length up front, a
+	// corrupt length at the tail may still be far beyond the end of the log
+	record, err := io.ReadAll(io.LimitReader(r, int64(length)))
+	if err != nil {
+		return nil, err
+	}
+	if len(record) < int(length) {
+		return nil, errTornRecord
+	}
+	if crc32.ChecksumIEEE(record) != binary.LittleEndian.Uint32(header[4:]) {
+		var next [1]byte
+		if _, err := io.ReadFull(r, next[:]); err == io.EOF {
+			return nil, errTornRecord
+		}
+		return nil, ErrCorruptWAL
+	}
+	return record, nil
+}
@@ -0,0 +1,208 @@
+package seqmut
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+type syncCounter struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (s *syncCounter) Sync() error {
+	s.syncs++
+	return nil
+}
+
+func addRecord(n int) []byte {
+	return []byte(strconv.Itoa(n))
+}
+
+func applyAdd(p *Protected[int]) func([]byte) error {
+	return func(record []byte) error {
+		n, err := strconv.Atoi(string(record))
+		if err != nil {
+			return err
+		}
+		p.Update(func(v *int) { *v += n })
+		return nil
+	}
+}
+
+func TestUpdateLoggedCanBeReplayed(t *testing.T) {
+	var log syncCounter
+	var p Protected[int]
+	p.SetWAL(NewFileWAL(&log, SyncAlways))
+
+	for i := 1; i <= 4; i++ {
+		assert.NoError(t, p.UpdateLogged(addRecord(i), func(v *int) { *v += i }))
+	}
+	assert.Equal(t, 4, log.syncs)
+
+	var restored Protected[int]
+	n, err := ReplayWAL(bytes.NewReader(log.Bytes()), applyAdd(&restored))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, p.Load(), restored.Load())
+}
+
+func TestReplayStopsAtTornTail(t *testing.T) {
+	var log bytes.Buffer
+	wal := NewFileWAL(&log, SyncNever)
+	wal.Append(addRecord(1))
+	wal.Append(addRecord(2))
+	torn := log.Bytes()[:log.Len()-1]
+
+	var restored Protected[int]
+	n, err := ReplayWAL(bytes.NewReader(torn), applyAdd(&restored))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, restored.Load())
+}
+
+func TestSyncEvery(t *testing.T) {
+	var log syncCounter
+	wal := NewFileWAL(&log, SyncEvery(3))
+
+	for i := 0; i < 7; i++ {
+		wal.Append(addRecord(i))
+	}
+
+	assert.Equal(t, 2, log.syncs)
+}
+
+type failingWAL struct{}
+
+func (failingWAL) Append([]byte) error { return errors.New("disk full") }
+
+func TestUpdateLoggedSkipsMutationWhenLogFails(t *testing.T) {
+	var p Protected[int]
+	p.SetWAL(failingWAL{})
+
+	err := p.UpdateLogged(addRecord(1), func(v *int) { *v = 1 })
+
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 0, p.Load())
+}
+
+func TestUpdateLoggedFailureDoesNotWrite(t *testing.T) {
+	var p Protected[int]
+	p.SetWAL(failingWAL{})
+	_, before := p.LoadStamped()
+
+	p.UpdateLogged(addRecord(1), func(v *int) { *v = 1 })
+
+	_, after := p.LoadStamped()
+	assert.Equal(t, before, after)
+}
+
+// readingWAL reads the value it is logging a mutation of, as a concurrent
+// reader would while the record is being written.
+type readingWAL struct {
+	p    *Protected[int]
+	seen int
+}
+
+func (w *readingWAL) Append([]byte) error {
+	w.seen = w.p.Load()
+	return nil
+}
+
+func TestUpdateLoggedDoesNotBlockReadersDuringAppend(t *testing.T) {
+	var p Protected[int]
+	wal := &readingWAL{p: &p}
+	p.SetWAL(wal)
+
+	assert.NoError(t, p.UpdateLogged(addRecord(1), func(v *int) { *v = 1 }))
+	assert.NoError(t, p.UpdateLogged(addRecord(1), func(v *int) { *v = 2 }))
+
+	assert.Equal(t, 1, wal.seen)
+	assert.Equal(t, 2, p.Load())
+}
+
+func TestReplayStopsAtCorruptLength(t *testing.T) {
+	var log bytes.Buffer
+	wal := NewFileWAL(&log, SyncNever)
+	wal.Append(addRecord(1))
+	// A header claiming a huge record, with nothing behind it
+	log.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+
+	var restored Protected[int]
+	n, err := ReplayWAL(&log, applyAdd(&restored))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestReplayFailsOnCorruptionMidLog(t *testing.T) {
+	var log bytes.Buffer
+	wal := NewFileWAL(&log, SyncNever)
+	wal.Append(addRecord(1))
+	wal.Append(addRecord(2))
+	wal.Append(addRecord(3))
+	// Flip a byte in the second record's payload
+	log.Bytes()[len(addRecord(1))+8+8] ^= 0xff
+
+	var restored Protected[int]
+	n, err := ReplayWAL(bytes.NewReader(log.Bytes()), applyAdd(&restored))
+
+	assert.Equal(t, ErrCorruptWAL, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestReplayToleratesDamagedLastRecord(t *testing.T) {
+	var log bytes.Buffer
+	wal := NewFileWAL(&log, SyncNever)
+	wal.Append(addRecord(1))
+	wal.Append(addRecord(2))
+	log.Bytes()[log.Len()-1] ^= 0xff
+
+	var restored Protected[int]
+	n, err := ReplayWAL(bytes.NewReader(log.Bytes()), applyAdd(&restored))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+// shortWriter accepts the first n bytes written to it and fails after that.
+type shortWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *shortWriter) Write(b []byte) (int, error) {
+	if w.Len()+len(b) > w.n {
+		written, _ := w.Buffer.Write(b[:w.n-w.Len()])
+		return written, errors.New("disk full")
+	}
+	return w.Buffer.Write(b)
+}
+
+func TestFailedAppendPoisonsFileWAL(t *testing.T) {
+	w := &shortWriter{n: 12}
+	wal := NewFileWAL(w, SyncNever)
+	assert.NoError(t, wal.Append(addRecord(1)))
+
+	assert.EqualError(t, wal.Append(addRecord(2)), "disk full")
+	err := wal.Append(addRecord(3))
+
+	assert.True(t, errors.Is(err, ErrWALPoisoned))
+	// Nothing was written behind the partial frame
+	assert.Equal(t, 12, w.Len())
+}
+
+func TestNilPolicySyncsAlways(t *testing.T) {
+	var log syncCounter
+	wal := NewFileWAL(&log, nil)
+
+	wal.Append(addRecord(1))
+	wal.Append(addRecord(2))
+
+	assert.Equal(t, 2, log.syncs)
+}
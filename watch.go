@@ -0,0 +1,34 @@
+package seqmut
+
+type sequenceWatch struct {
+	threshold uint64
+	fn        func(Stamp)
+}
+
+// OnSequence registers fn to be called whenever a write moves the sequence
+// past threshold; that is, when the sequence before the write was below
+// threshold, counting around wraparound, and after it is at or above it. fn
+// receives the new sequence and runs after the lock has been released, on the
+// writer's goroutine.
+//
+// Systems that hand the sequence out as a version token can use this to
+// rotate or re-baseline their tokens before they become ambiguous.
+func (rw *RWMutex) OnSequence(threshold uint64, fn func(Stamp)) {
+	rw.acquire()
+	rw.watches = append(rw.watches[:len(rw.watches):len(rw.watches)], sequenceWatch{threshold, fn})
+	rw.release()
+}
+
+// OnWraparound registers fn to be called when the sequence wraps past zero.
+func (rw *RWMutex) OnWraparound(fn func(Stamp)) {
+	rw.OnSequence(0, fn)
+}
+
+func notifyCrossings(watches []sequenceWatch, from, to uint64) {
+	for _, w := range watches {
+		// Unsigned arithmetic makes this hold across wraparound
+		if w.threshold-from-1 < to-from {
+			w.fn(Stamp(to))
+		}
+	}
+}
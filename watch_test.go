@@ -0,0 +1,49 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOnWraparound(t *testing.T) {
+	rw := NewWithSequence(MaxUint64 - 3)
+	var wrapped []Stamp
+	rw.OnWraparound(func(s Stamp) { wrapped = append(wrapped, s) })
+
+	rw.Lock()
+	rw.Unlock()
+	assert.Empty(t, wrapped)
+
+	rw.Lock()
+	rw.Unlock()
+	assert.Equal(t, []Stamp{0}, wrapped)
+
+	rw.Lock()
+	rw.Unlock()
+	assert.Equal(t, []Stamp{0}, wrapped)
+}
+
+func TestOnSequenceThresholds(t *testing.T) {
+	var rw RWMutex
+	var crossed []uint64
+	rw.OnSequence(3, func(Stamp) { crossed = append(crossed, 3) })
+	rw.OnSequence(4, func(Stamp) { crossed = append(crossed, 4) })
+	rw.OnSequence(6, func(s Stamp) {
+		// Runs after the lock is released
+		rw.Lock()
+		rw.Unlock()
+		crossed = append(crossed, 6)
+	})
+
+	rw.Lock()
+	rw.Unlock()
+	assert.Empty(t, crossed)
+
+	rw.Lock()
+	rw.Unlock()
+	assert.Equal(t, []uint64{3, 4}, crossed)
+
+	rw.Lock()
+	rw.Unlock()
+	assert.Equal(t, []uint64{3, 4, 6}, crossed)
+}
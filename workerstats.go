@@ -0,0 +1,74 @@
+package seqmut
+
+import "time"
+
+// WorkerSnapshot is the state of a worker pool at one moment.
+type WorkerSnapshot struct {
+	Active, Queued int
+	Completed      uint64
+	// Task latency quantiles, zero until a task has completed
+	P50, P99 time.Duration
+}
+
+// WorkerStats tracks a worker pool for autoscaling and dashboards. Workers
+// report with short writes; Snapshot reads the counters together, so a
+// scaling decision never sees a task counted as both queued and active, or
+// neither.
+type WorkerStats struct {
+	rw        RWMutex
+	active    int
+	queued    int
+	completed uint64
+	latency   *TDigest
+}
+
+// NewWorkerStats returns stats for an idle pool.
+func NewWorkerStats() *WorkerStats {
+	return &WorkerStats{latency: NewTDigest(100)}
+}
+
+// Enqueued records a task being queued.
+func (s *WorkerStats) Enqueued() {
+	s.rw.Lock()
+	s.queued++
+	s.rw.Unlock()
+}
+
+// Started records a worker taking a task off the queue.
+func (s *WorkerStats) Started() {
+	s.rw.Lock()
+	s.queued--
+	s.active++
+	s.rw.Unlock()
+}
+
+// Done records a worker finishing a task that took latency.
+func (s *WorkerStats) Done(latency time.Duration) {
+	// The latency goes in first, so a snapshot that counts the task completed
+	// has a latency to report
+	s.latency.Add(float64(latency))
+	s.rw.Lock()
+	s.active--
+	s.completed++
+	s.rw.Unlock()
+}
+
+// Snapshot returns the current state. The counters are consistent with each
+// other; the latency quantiles are read separately, from one view of the
+// latencies, and may include the latest few completions before they are
+// counted.
+func (s *WorkerStats) Snapshot() WorkerSnapshot {
+	var snap WorkerSnapshot
+	stamp := s.rw.RStamp()
+	for {
+		snap.Active, snap.Queued, snap.Completed = s.active, s.queued, s.completed
+		if s.rw.Ok(stamp) {
+			break
+		}
+	}
+	if snap.Completed > 0 {
+		q := s.latency.Quantiles(0.5, 0.99)
+		snap.P50, snap.P99 = time.Duration(q[0]), time.Duration(q[1])
+	}
+	return snap
+}
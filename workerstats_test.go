@@ -0,0 +1,81 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkerStatsSnapshot(t *testing.T) {
+	s := NewWorkerStats()
+	assert.Equal(t, WorkerSnapshot{}, s.Snapshot())
+
+	for i := 1; i <= 100; i++ {
+		s.Enqueued()
+		s.Started()
+		s.Done(time.Duration(i) * time.Millisecond)
+	}
+	s.Enqueued()
+	s.Enqueued()
+	s.Started()
+
+	snap := s.Snapshot()
+	assert.Equal(t, 1, snap.Active)
+	assert.Equal(t, 1, snap.Queued)
+	assert.Equal(t, uint64(100), snap.Completed)
+	assert.InDelta(t, 50*time.Millisecond, snap.P50, float64(2*time.Millisecond))
+	assert.InDelta(t, 99*time.Millisecond, snap.P99, float64(2*time.Millisecond))
+}
+
+func TestWorkerStatsCountersAreConsistent(t *testing.T) {
+	s := NewWorkerStats()
+	const tasks = 4
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// A fixed number of tasks cycle through the queue
+			for i := 0; i < tasks; i++ {
+				s.Enqueued()
+			}
+			for i := 0; i < tasks; i++ {
+				s.Started()
+				s.Done(time.Millisecond)
+			}
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		snap := s.Snapshot()
+		assert.True(t, snap.Active+snap.Queued <= tasks, "%+v", snap)
+		assert.True(t, snap.Active >= 0 && snap.Queued >= 0, "%+v", snap)
+		assert.True(t, snap.P50 <= snap.P99, "%+v", snap)
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestWorkerStatsCompletedTasksHaveLatencies(t *testing.T) {
+	s := NewWorkerStats()
+	go func() {
+		s.Enqueued()
+		s.Started()
+		s.Done(time.Second)
+	}()
+
+	for {
+		if snap := s.Snapshot(); snap.Completed > 0 {
+			assert.Equal(t, time.Second, snap.P50)
+			assert.Equal(t, time.Second, snap.P99)
+			return
+		}
+	}
+}
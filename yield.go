@@ -0,0 +1,30 @@
+package seqmut
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Retries returns the number of times Ok has rejected a read on this lock.
+func (rw *RWMutex) Retries() uint64 {
+	return atomic.LoadUint64(&rw.retries)
+}
+
+// Yield lets readers catch up with a long-running writer. If readers have
+// been sent around the retry loop at least minRetries times since the caller
+// acquired the lock (or last yielded), Yield unlocks, gives other goroutines
+// a chance to run, and locks again, returning true.
+//
+// The caller must hold the lock, and the guarded state must be consistent at
+// the point of the call. Other writers may get the lock in between, so the
+// caller must not assume anything it read before Yield returned true is
+// still current.
+func (rw *RWMutex) Yield(minRetries uint64) bool {
+	if atomic.LoadUint64(&rw.retries)-rw.retriesAtLock < minRetries {
+		return false
+	}
+	rw.Unlock()
+	runtime.Gosched()
+	rw.Lock()
+	return true
+}
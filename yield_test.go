@@ -0,0 +1,67 @@
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unsafe"
+)
+
+func TestOkFailuresAreCountedAsRetries(t *testing.T) {
+	var rw RWMutex
+
+	stamp := rw.RStamp()
+	assert.True(t, rw.Ok(stamp))
+	assert.Equal(t, uint64(0), rw.Retries())
+
+	rw.Lock()
+	assert.False(t, rw.Ok(stamp))
+	assert.False(t, rw.Ok(stamp))
+	rw.Unlock()
+
+	assert.Equal(t, uint64(2), rw.Retries())
+}
+
+func TestYieldDoesNothingWithoutRetryingReaders(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+
+	assert.False(t, rw.Yield(1))
+	assert.Equal(t, uint64(1), rw.sequence)
+	rw.Unlock()
+}
+
+func TestYieldReleasesAndReacquiresWhenReadersRetry(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+	stamp := rw.RStamp()
+	assert.False(t, rw.Ok(stamp))
+
+	assert.True(t, rw.Yield(1))
+	// We hold the lock again, at a new version
+	assert.Equal(t, uint64(3), rw.sequence)
+
+	// Retries are counted afresh after yielding
+	assert.False(t, rw.Yield(1))
+	rw.Unlock()
+}
+
+func TestYieldWithZeroThresholdAlwaysYields(t *testing.T) {
+	var rw RWMutex
+
+	rw.Lock()
+	assert.True(t, rw.Yield(0))
+	rw.Unlock()
+}
+
+// Readers that fail Ok write the retry counter; if it shared a cache line with
+// the sequence, every retry would invalidate the line all readers are polling.
+func TestRetriesHaveTheirOwnCacheLine(t *testing.T) {
+	const cacheLine = 64
+	var rw RWMutex
+	retries := int(unsafe.Offsetof(rw.retries))
+
+	assert.GreaterOrEqual(t, retries-int(unsafe.Offsetof(rw.sequence)), cacheLine)
+	assert.GreaterOrEqual(t, int(unsafe.Sizeof(rw))-retries, cacheLine)
+}
@@ -0,0 +1,14 @@
+package seqmut
+
+// YieldPoint identifies a place in the lock protocol where a test build can
+// pause to force a specific interleaving, see SetYieldHook.
+type YieldPoint int
+
+const (
+	// In Ok, after the critical section and before the sequence is re-read
+	YieldBeforeValidate YieldPoint = iota
+	// In Lock, after the sequence has gone odd
+	YieldAfterWriteBegin
+	// In Unlock, before the sequence goes even again
+	YieldBeforeWriteEnd
+)
@@ -0,0 +1,7 @@
+//go:build !seqmutyield
+// +build !seqmutyield
+
+package seqmut
+
+// yieldPoint is a no-op unless built with -tags seqmutyield.
+func yieldPoint(YieldPoint) {}
@@ -0,0 +1,25 @@
+//go:build seqmutyield
+// +build seqmutyield
+
+package seqmut
+
+import "sync/atomic"
+
+var yieldHook atomic.Value
+
+// SetYieldHook installs fn to be called at every YieldPoint, by the goroutine
+// passing through it, and returns a function restoring the previous hook.
+// Blocking in fn holds that goroutine at that point in the protocol, which
+// lets tests drive readers and writers through exact race windows. Only
+// available when built with -tags seqmutyield.
+func SetYieldHook(fn func(YieldPoint)) (restore func()) {
+	prev, _ := yieldHook.Load().(func(YieldPoint))
+	yieldHook.Store(fn)
+	return func() { yieldHook.Store(prev) }
+}
+
+func yieldPoint(p YieldPoint) {
+	if fn, _ := yieldHook.Load().(func(YieldPoint)); fn != nil {
+		fn(p)
+	}
+}
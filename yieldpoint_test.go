@@ -0,0 +1,54 @@
+//go:build seqmutyield
+// +build seqmutyield
+
+package seqmut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWriteBetweenReadAndValidateFailsValidation(t *testing.T) {
+	var rw RWMutex
+	once := false
+	defer SetYieldHook(func(p YieldPoint) {
+		if p == YieldBeforeValidate && !once {
+			once = true
+			rw.Lock()
+			rw.Unlock()
+		}
+	})()
+
+	stamp := rw.RStamp()
+
+	assert.False(t, rw.Ok(stamp))
+	assert.True(t, rw.Ok(stamp))
+}
+
+func TestReaderStartingInsideWriteIsRetried(t *testing.T) {
+	var rw RWMutex
+	inside := make(chan struct{})
+	release := make(chan struct{})
+	defer SetYieldHook(func(p YieldPoint) {
+		if p == YieldAfterWriteBegin {
+			close(inside)
+			<-release
+		}
+	})()
+	done := make(chan struct{})
+	go func() {
+		rw.Lock()
+		rw.Unlock()
+		close(done)
+	}()
+
+	<-inside
+	stamp := rw.RStamp()
+	assert.False(t, rw.Ok(stamp))
+	close(release)
+	<-done
+
+	// The stamp picked up inside the write is still odd, so one more lap
+	assert.False(t, rw.Ok(stamp))
+	assert.True(t, rw.Ok(stamp))
+}